@@ -0,0 +1,162 @@
+// Package audit reconstructs process launches from Linux audit (auditd)
+// execve records.
+package audit
+
+import (
+	"encoding/hex"
+	"os/user"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// Records are prefixed with type=<TYPE> msg=audit(<seconds>.<millis>:<serial>):
+var recordHeader = regexp.MustCompile(`^type=(\w+) msg=audit\((\d+)\.(\d+):(\d+)\):\s*`)
+
+const unsetID = "4294967295"
+
+type event struct {
+	time    time.Time
+	syscall map[string]string
+	execve  map[string]string
+	cwd     string
+}
+
+// ParseLaunch returns the most recent execve of pid in raw audit log
+// content that happened no earlier than notBefore, or nil
+func ParseLaunch(content string, pid int, notBefore time.Time) *model.LaunchRecord {
+	events := make(map[string]*event)
+	var order []string
+	for line := range strings.Lines(content) {
+		// Enriched logs append interpreted fields after a group separator
+		line, _, _ = strings.Cut(strings.TrimRight(line, "\n"), "\x1d")
+		m := recordHeader.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		kind, serial := m[1], m[4]
+		if kind != "SYSCALL" && kind != "EXECVE" && kind != "CWD" {
+			continue
+		}
+		ev := events[serial]
+		if ev == nil {
+			sec, _ := strconv.ParseInt(m[2], 10, 64)
+			ms, _ := strconv.ParseInt(m[3], 10, 64)
+			ev = &event{time: time.Unix(sec, ms*int64(time.Millisecond))}
+			events[serial] = ev
+			order = append(order, serial)
+		}
+		fields := parseFields(line[len(m[0]):])
+		switch kind {
+		case "SYSCALL":
+			ev.syscall = fields
+		case "EXECVE":
+			ev.execve = fields
+		case "CWD":
+			ev.cwd = decodeValue(fields["cwd"])
+		}
+	}
+
+	sort.SliceStable(order, func(i, j int) bool { return events[order[i]].time.Before(events[order[j]].time) })
+	want := strconv.Itoa(pid)
+	for i := len(order) - 1; i >= 0; i-- {
+		ev := events[order[i]]
+		if ev.syscall == nil || ev.execve == nil || ev.syscall["pid"] != want || ev.syscall["success"] == "no" {
+			continue
+		}
+		// A later process may reuse the PID; older records are not its launch
+		if ev.time.Before(notBefore) {
+			return nil
+		}
+		return ev.record()
+	}
+	return nil
+}
+
+func (ev *event) record() *model.LaunchRecord {
+	r := &model.LaunchRecord{Time: ev.time, Cwd: ev.cwd, Argv: execveArgs(ev.execve)}
+	sc := ev.syscall
+	r.Exe = decodeValue(sc["exe"])
+	r.PPID, _ = strconv.Atoi(sc["ppid"])
+	r.UID = sc["uid"]
+	if tty := sc["tty"]; tty != "" && tty != "(none)" {
+		r.TTY = tty
+	}
+	if ses := sc["ses"]; ses != "" && ses != unsetID {
+		r.Session = ses
+	}
+	if auid := sc["auid"]; auid != "" && auid != unsetID {
+		r.LoginUser = auid
+		if u, err := user.LookupId(auid); err == nil {
+			r.LoginUser = u.Username + " (" + auid + ")"
+		}
+	}
+	return r
+}
+
+// execveArgs rebuilds argv from a0..aN, joining a1[0], a1[1]... chunks that
+// auditd uses for long arguments
+func execveArgs(fields map[string]string) []string {
+	argc, _ := strconv.Atoi(fields["argc"])
+	args := make([]string, 0, argc)
+	for i := 0; i < argc; i++ {
+		key := "a" + strconv.Itoa(i)
+		if v, ok := fields[key]; ok {
+			args = append(args, decodeValue(v))
+			continue
+		}
+		var b strings.Builder
+		for j := 0; ; j++ {
+			chunk, ok := fields[key+"["+strconv.Itoa(j)+"]"]
+			if !ok {
+				break
+			}
+			b.WriteString(decodeValue(chunk))
+		}
+		args = append(args, b.String())
+	}
+	return args
+}
+
+// parseFields splits key=value pairs, keeping quoted values intact
+func parseFields(s string) map[string]string {
+	fields := make(map[string]string)
+	for s != "" {
+		s = strings.TrimLeft(s, " ")
+		key, rest, ok := strings.Cut(s, "=")
+		if !ok {
+			break
+		}
+		var val string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.IndexByte(rest[1:], '"')
+			if end == -1 {
+				end = len(rest) - 1
+			}
+			val, s = rest[:end+2], rest[min(end+2, len(rest)):]
+		} else {
+			val, s, _ = strings.Cut(rest, " ")
+		}
+		fields[key] = val
+	}
+	return fields
+}
+
+// decodeValue unquotes a value, or decodes it from hex, as auditd encodes
+// values containing spaces or control characters
+func decodeValue(v string) string {
+	if strings.HasPrefix(v, `"`) && strings.HasSuffix(v, `"`) && len(v) >= 2 {
+		return v[1 : len(v)-1]
+	}
+	if v == "(null)" {
+		return ""
+	}
+	if b, err := hex.DecodeString(v); err == nil && len(v)%2 == 0 && v != "" {
+		return string(b)
+	}
+	return v
+}
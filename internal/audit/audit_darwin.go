@@ -0,0 +1,14 @@
+//go:build darwin
+
+package audit
+
+import (
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// FindLaunch is not supported on macOS, which uses OpenBSM rather than auditd
+func FindLaunch(_ int, _ time.Time) *model.LaunchRecord {
+	return nil
+}
@@ -0,0 +1,31 @@
+//go:build linux
+
+package audit
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+const auditLog = "/var/log/audit/audit.log"
+
+// FindLaunch looks up the execve record of pid, started at startedAt, with
+// ausearch or, without it, by reading the audit log directly
+func FindLaunch(pid int, startedAt time.Time) *model.LaunchRecord {
+	// Start times are derived from clock ticks, so allow a little slack
+	notBefore := startedAt.Add(-2 * time.Second)
+
+	out, err := exec.Command("ausearch", "--raw", "-p", strconv.Itoa(pid), "-m", "SYSCALL,EXECVE,CWD").Output()
+	if err == nil {
+		return ParseLaunch(string(out), pid, notBefore)
+	}
+	data, err := os.ReadFile(auditLog)
+	if err != nil {
+		return nil
+	}
+	return ParseLaunch(string(data), pid, notBefore)
+}
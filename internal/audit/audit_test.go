@@ -0,0 +1,35 @@
+package audit
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+const sampleLog = `type=SYSCALL msg=audit(1700000000.120:456): arch=c000003e syscall=59 success=yes exit=0 a0=55d1 a1=55d2 a2=55d3 a3=0 items=2 ppid=812 pid=4242 auid=1000 uid=0 gid=0 euid=0 tty=pts1 ses=3 comm="miner" exe="/tmp/.x/miner" key="exec"
+type=EXECVE msg=audit(1700000000.120:456): argc=3 a0="/tmp/.x/miner" a1="--pool" a2=706F6F6C2E6578616D706C653A33333333202D71
+type=CWD msg=audit(1700000000.120:456): cwd="/tmp/.x"
+type=SYSCALL msg=audit(1600000000.000:12): arch=c000003e syscall=59 success=yes exit=0 ppid=1 pid=4242 auid=4294967295 uid=0 tty=(none) ses=4294967295 comm="old" exe="/usr/bin/old"
+type=EXECVE msg=audit(1600000000.000:12): argc=1 a0="old"
+`
+
+func TestParseLaunch(t *testing.T) {
+	r := ParseLaunch(sampleLog, 4242, time.Unix(1699999999, 0))
+	if r == nil {
+		t.Fatal("ParseLaunch() = nil, want the miner launch")
+	}
+	if want := []string{"/tmp/.x/miner", "--pool", "pool.example:3333 -q"}; !reflect.DeepEqual(r.Argv, want) {
+		t.Errorf("Argv = %q, want %q", r.Argv, want)
+	}
+	if r.Exe != "/tmp/.x/miner" || r.Cwd != "/tmp/.x" || r.PPID != 812 || r.TTY != "pts1" || r.Session != "3" || r.UID != "0" {
+		t.Errorf("ParseLaunch() = %+v", r)
+	}
+	if r.LoginUser == "" || !r.Time.Equal(time.Unix(1700000000, 120*int64(time.Millisecond))) {
+		t.Errorf("LoginUser = %q, Time = %v", r.LoginUser, r.Time)
+	}
+
+	// The process started after every record, so none describe its launch
+	if r := ParseLaunch(sampleLog, 4242, time.Unix(1800000000, 0)); r != nil {
+		t.Errorf("ParseLaunch() = %+v, want nil for a reused PID", r)
+	}
+}
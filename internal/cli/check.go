@@ -0,0 +1,117 @@
+//go:build linux || darwin
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+	"github.com/spf13/cobra"
+)
+
+// finding is one warning raised by check, in its structured output
+type finding struct {
+	Severity string `json:"severity"`
+	Code     string `json:"code"`
+	PID      int    `json:"pid"`
+	Command  string `json:"command"`
+	Source   string `json:"source"`
+	Warning  string `json:"warning"`
+}
+
+func newCheckCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "check",
+		Short: "Check listening processes for warnings (for cron and CI)",
+		Long: "check scans every process with a listening socket and reports warnings at\n" +
+			"or above a severity threshold.\n\n" +
+			"With --warnings-only nothing is printed when the host is clean.\n\n" +
+			"Exit status:\n" +
+			"  0  no findings\n" +
+			"  1  the check could not run (bad flags, unreadable snapshot, ...)\n" +
+			"  2  findings were reported",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mineFlag, _ := cmd.Flags().GetBool("mine")
+			jsonFlag, _ := cmd.Flags().GetBool("json")
+			onlyFlag, _ := cmd.Flags().GetBool("warnings-only")
+			severityFlag, _ := cmd.Flags().GetString("severity")
+
+			threshold, err := model.ParseSeverity(severityFlag)
+			if err != nil {
+				return err
+			}
+			filter, err := sourceFilterFromFlags(cmd)
+			if err != nil {
+				return err
+			}
+			uid := -1
+			if mineFlag {
+				uid = os.Getuid()
+			}
+
+			prog, err := progressFromFlags(cmd)
+			if err != nil {
+				return err
+			}
+			snap, err := loadSnapshot(cmd)
+			if err != nil {
+				return err
+			}
+			findings := []finding{}
+			checked := 0
+			err = scanResults(snap, uid, prog, filter.wrap(func(r model.Result) error {
+				checked++
+				for _, warning := range r.WarningFindings() {
+					if warning.Severity < threshold {
+						continue
+					}
+					findings = append(findings, finding{
+						Severity: warning.Severity.String(),
+						Code:     warning.Code,
+						PID:      r.Process.PID,
+						Command:  r.Process.Command,
+						Source:   r.Source.Name,
+						Warning:  warning.Message,
+					})
+				}
+				return nil
+			}))
+			if err != nil {
+				return err
+			}
+
+			switch {
+			case jsonFlag && (len(findings) > 0 || !onlyFlag):
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(findings); err != nil {
+					return err
+				}
+			case !jsonFlag:
+				for _, f := range findings {
+					fmt.Printf("%-6s pid %d (%s): %s\n", f.Severity, f.PID, f.Command, f.Warning)
+				}
+				if !onlyFlag {
+					fmt.Printf("%d processes checked, %d findings at or above %s\n", checked, len(findings), threshold)
+				}
+			}
+
+			// Errors exit with 1, so findings get a status of their own
+			if len(findings) > 0 {
+				os.Exit(2)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("mine", false, "only check processes owned by the invoking user")
+	cmd.Flags().Bool("json", false, "output findings as JSON")
+	cmd.Flags().Bool("warnings-only", false, "print nothing unless there are findings")
+	cmd.Flags().String("severity", "medium", "minimum severity to report: info, low, medium or high")
+	addSourceFilterFlags(cmd)
+	addProgressFlag(cmd)
+	return cmd
+}
@@ -0,0 +1,46 @@
+//go:build linux || darwin
+
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pranshuparmar/witr/internal/output"
+)
+
+// colorOutput decides whether output to out is colorized. In auto mode,
+// colors are used only on a terminal and when NO_COLOR is unset or empty
+// (https://no-color.org); always and never override both. --no-color is
+// the same as never.
+func colorOutput(mode string, noColor bool, out *os.File) (bool, error) {
+	switch mode {
+	case "auto", "":
+		if noColor || os.Getenv("NO_COLOR") != "" || out == nil {
+			return false, nil
+		}
+		fi, err := out.Stat()
+		return err == nil && fi.Mode()&os.ModeCharDevice != 0, nil
+	case "always":
+		return !noColor, nil
+	case "never":
+		return false, nil
+	}
+	return false, fmt.Errorf("unknown --color %q (want auto, always or never)", mode)
+}
+
+// applyTheme selects the color theme from --theme, or else the config
+// file, keeping the default palette when neither names one
+func applyTheme(flag string) error {
+	if flag == "" {
+		c, err := loadConfig()
+		if err != nil {
+			return err
+		}
+		flag = c.theme
+	}
+	if flag == "" {
+		return nil
+	}
+	return output.SetTheme(flag)
+}
@@ -0,0 +1,306 @@
+//go:build linux || darwin
+
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/internal/source"
+	"github.com/pranshuparmar/witr/pkg/model"
+	"github.com/spf13/cobra"
+)
+
+// comparison is one compared attribute of a target on both hosts
+type comparison struct {
+	Field   string `json:"field"`
+	Local   string `json:"local"`
+	Remote  string `json:"remote"`
+	Differs bool   `json:"differs"`
+}
+
+// Unit properties compared between hosts; values that change on every
+// start (PIDs, timestamps) are left out
+var compareUnitProps = []string{"FragmentPath", "DropInPaths", "ExecStart", "User", "Group", "Environment", "Restart"}
+
+func newCompareCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "compare --host <host> [process name]",
+		Short: "Explain a target locally and on another host and show the differences",
+		Long: "compare explains the same target on this host and, over ssh, on --host (which\n" +
+			"needs witr installed), then lists the source, executable, binary digest,\n" +
+			"command line and systemd unit configuration side by side, marking the\n" +
+			"attributes that differ.\n\n" +
+			"ssh runs non-interactively, so the host must accept key or agent\n" +
+			"authentication; ports and users come from ssh_config or user@host.",
+		Args:    cobra.MaximumNArgs(1),
+		PreRunE: rejectSnapshot,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			hostFlag, _ := cmd.Flags().GetString("host")
+			sshFlag, _ := cmd.Flags().GetString("ssh")
+			remoteFlag, _ := cmd.Flags().GetString("remote-witr")
+			pidFlag, _ := cmd.Flags().GetString("pid")
+			portFlag, _ := cmd.Flags().GetString("port")
+			socketFlag, _ := cmd.Flags().GetString("socket")
+			jsonFlag, _ := cmd.Flags().GetBool("json")
+
+			if hostFlag == "" {
+				return fmt.Errorf("--host is required")
+			}
+			var t model.Target
+			switch {
+			case pidFlag != "":
+				t = model.Target{Type: model.TargetPID, Value: pidFlag}
+			case portFlag != "":
+				t = model.Target{Type: model.TargetPort, Value: portFlag}
+			case socketFlag != "":
+				t = model.Target{Type: model.TargetSocket, Value: socketFlag}
+			case len(args) > 0:
+				t = model.Target{Type: model.TargetName, Value: args[0]}
+			default:
+				return fmt.Errorf("must specify --pid, --port, --socket, or a process name")
+			}
+
+			prog, err := progressFromFlags(cmd)
+			if err != nil {
+				return err
+			}
+
+			cmd.SilenceUsage = true
+			prog.Start("compare", 2)
+			local := explainTarget(t, enrichOptions{})
+			if local.Error != "" {
+				prog.Finish()
+				return fmt.Errorf("local: %s", local.Error)
+			}
+			prog.Step("local")
+			remoteRun := func(script string) (string, error) {
+				return runOutput(exec.Command(sshFlag, "-o", "BatchMode=yes", "--", hostFlag, script))
+			}
+			remote, err := remoteExplain(remoteRun, remoteFlag, t)
+			prog.Step(hostFlag)
+			prog.Finish()
+			if err != nil {
+				return fmt.Errorf("%s: %v", hostFlag, err)
+			}
+			localRun := func(script string) (string, error) {
+				return runOutput(exec.Command("sh", "-c", script))
+			}
+			rows := compareResults(local, remote, hostFacts(localRun, local.Process), hostFacts(remoteRun, remote.Process))
+
+			if jsonFlag {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(struct {
+					Host        string       `json:"host"`
+					Target      model.Target `json:"target"`
+					Comparisons []comparison `json:"comparisons"`
+					Local       model.Result `json:"local"`
+					Remote      model.Result `json:"remote"`
+				}{hostFlag, t, rows, local, remote})
+			}
+			printComparison(t, hostFlag, rows)
+			return nil
+		},
+	}
+
+	cmd.Flags().String("host", "", "host to compare with, as given to ssh (host or user@host)")
+	cmd.Flags().String("ssh", "ssh", "ssh client to run")
+	cmd.Flags().String("remote-witr", "witr", "witr command on the remote host")
+	cmd.Flags().String("pid", "", "pid to look up (PIDs rarely match across hosts)")
+	cmd.Flags().String("port", "", "port to look up")
+	cmd.Flags().String("socket", "", "unix socket path to look up")
+	cmd.Flags().Bool("json", false, "output as JSON, including both results")
+	addProgressFlag(cmd)
+	return cmd
+}
+
+// runOutput runs a command and returns its output, folding stderr into the
+// error so remote failures explain themselves
+func runOutput(c *exec.Cmd) (string, error) {
+	out, err := c.Output()
+	if exitErr := (*exec.ExitError)(nil); errors.As(err, &exitErr) {
+		if msg := strings.TrimSpace(string(exitErr.Stderr)); msg != "" {
+			return "", errors.New(msg)
+		}
+	}
+	return string(out), err
+}
+
+// remoteExplain runs witr --json for the target through run and decodes the
+// result
+func remoteExplain(run func(string) (string, error), witr string, t model.Target) (model.Result, error) {
+	script := shellQuote(witr) + " --json"
+	if t.Type == model.TargetName {
+		script += " " + shellQuote(t.Value)
+	} else {
+		script += " --" + string(t.Type) + " " + shellQuote(t.Value)
+	}
+	out, err := run(script)
+	if err != nil {
+		return model.Result{}, err
+	}
+	var res model.Result
+	if err := json.Unmarshal([]byte(out), &res); err != nil {
+		return model.Result{}, fmt.Errorf("unexpected output from %s: %v", witr, err)
+	}
+	return res, nil
+}
+
+// hostFacts collects what the result alone does not show through run: the
+// digest of the executable and the configuration of its systemd unit
+func hostFacts(run func(string) (string, error), p model.Process) map[string]string {
+	facts := make(map[string]string)
+	if p.Exe != "" {
+		exe := shellQuote(p.Exe)
+		if out, err := run("sha256sum -- " + exe + " 2>/dev/null || shasum -a 256 -- " + exe); err == nil {
+			if fields := strings.Fields(out); len(fields) > 0 {
+				facts["SHA-256"] = fields[0]
+			}
+		}
+	}
+	if p.Service != "" {
+		script := "systemctl show " + shellQuote(p.Service)
+		for _, prop := range compareUnitProps {
+			script += " -p " + prop
+		}
+		if out, err := run(script); err == nil {
+			for line := range strings.Lines(out) {
+				key, val, ok := strings.Cut(strings.TrimRight(line, "\n"), "=")
+				if !ok || val == "" {
+					continue
+				}
+				switch key {
+				case "ExecStart":
+					val = source.ExecStartArgv(val)
+				case "Environment":
+					val = strings.Join(procpkg.RedactEnv(strings.Fields(val)), " ")
+				}
+				facts["Unit "+key] = val
+			}
+		}
+	}
+	return facts
+}
+
+// compareResults lists the attributes of two results for the same target,
+// skipping those neither side has
+func compareResults(local, remote model.Result, localFacts, remoteFacts map[string]string) []comparison {
+	var rows []comparison
+	add := func(field, l, r string) {
+		if l == "" && r == "" {
+			return
+		}
+		rows = append(rows, comparison{Field: field, Local: l, Remote: r, Differs: l != r})
+	}
+	lp, rp := local.Process, remote.Process
+
+	add("Process", lp.Command, rp.Command)
+	add("Executable", lp.Exe, rp.Exe)
+	add("SHA-256", localFacts["SHA-256"], remoteFacts["SHA-256"])
+	add("Package", packageLabel(local.Package), packageLabel(remote.Package))
+	add("Command", lp.Cmdline, rp.Cmdline)
+	add("User", lp.User, rp.User)
+	add("Source", sourceLabel(local.Source), sourceLabel(remote.Source))
+	// Hints embed the invoking user and would always differ
+	for _, key := range unionKeys(local.Source.Details, remote.Source.Details, "hint") {
+		add("Source "+key, local.Source.Details[key], remote.Source.Details[key])
+	}
+	add("Service", lp.Service, rp.Service)
+	add("Container", lp.Container, rp.Container)
+	for _, key := range unionKeys(localFacts, remoteFacts, "SHA-256") {
+		add(key, localFacts[key], remoteFacts[key])
+	}
+	add("Ports", joinPorts(lp.ListeningPorts), joinPorts(rp.ListeningPorts))
+	add("Warnings", strings.Join(local.Warnings, "; "), strings.Join(remote.Warnings, "; "))
+	return rows
+}
+
+func packageLabel(pkg *model.PackageOwner) string {
+	switch {
+	case pkg == nil:
+		return ""
+	case pkg.Name == "":
+		return "none (" + pkg.Manager + ")"
+	}
+	return strings.TrimSpace(pkg.Name + " " + pkg.Version)
+}
+
+func sourceLabel(src model.Source) string {
+	if src.Type == "" {
+		return ""
+	}
+	if src.Name == "" {
+		return string(src.Type)
+	}
+	return string(src.Type) + " (" + src.Name + ")"
+}
+
+// unionKeys returns the sorted keys present in either map, except skip
+func unionKeys(a, b map[string]string, skip string) []string {
+	var keys []string
+	for _, m := range []map[string]string{a, b} {
+		for k := range m {
+			if k != skip && !slices.Contains(keys, k) {
+				keys = append(keys, k)
+			}
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func joinPorts(ports []int) string {
+	s := make([]string, len(ports))
+	for i, p := range ports {
+		s[i] = strconv.Itoa(p)
+	}
+	return strings.Join(s, ", ")
+}
+
+// printComparison prints the rows in two columns, marking differences with *.
+// Values too long for the local column are printed on a line per host.
+func printComparison(t model.Target, host string, rows []comparison) {
+	differ := 0
+	width := len("local")
+	for _, r := range rows {
+		if r.Differs {
+			differ++
+		}
+		if len(r.Local) <= 48 {
+			width = max(width, len(r.Local))
+		}
+	}
+	fmt.Printf("Comparing %s %s: local vs %s (", t.Type, t.Value, host)
+	if differ == 1 {
+		fmt.Print("1 difference)\n\n")
+	} else {
+		fmt.Printf("%d differences)\n\n", differ)
+	}
+	fmt.Printf("  %-20s %-*s  %s\n", "", width, "local", host)
+	for _, r := range rows {
+		mark := " "
+		if r.Differs {
+			mark = "*"
+		}
+		if len(r.Local) > width {
+			// Too long for the column: one line per host
+			fmt.Printf("%s %-20s %s: %s\n", mark, r.Field, "local", r.Local)
+			fmt.Printf("  %-20s %s: %s\n", "", host, r.Remote)
+			continue
+		}
+		fmt.Printf("%s %-20s %-*s  %s\n", mark, r.Field, width, r.Local, r.Remote)
+	}
+}
+
+// shellQuote quotes s for a POSIX shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
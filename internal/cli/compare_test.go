@@ -0,0 +1,42 @@
+//go:build linux || darwin
+
+package cli
+
+import (
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestCompareResults(t *testing.T) {
+	local := model.Result{
+		Process: model.Process{Command: "nginx", Exe: "/usr/sbin/nginx", User: "www-data", Service: "nginx.service"},
+		Source:  model.Source{Type: model.SourceSystemd, Name: "nginx.service", Details: map[string]string{"hint": "a", "manager": "system"}},
+	}
+	remote := model.Result{
+		Process: model.Process{Command: "nginx", Exe: "/usr/local/sbin/nginx", User: "www-data"},
+		Source:  model.Source{Type: model.SourceContainer, Name: "web", Details: map[string]string{"hint": "b"}},
+	}
+	rows := compareResults(local, remote, map[string]string{"SHA-256": "aa"}, map[string]string{"SHA-256": "aa"})
+
+	got := map[string]comparison{}
+	for _, r := range rows {
+		got[r.Field] = r
+	}
+	for field, differs := range map[string]bool{
+		"Process": false, "Executable": true, "SHA-256": false, "User": false,
+		"Source": true, "Source manager": true, "Service": true,
+	} {
+		r, ok := got[field]
+		if !ok {
+			t.Errorf("missing row %q", field)
+		} else if r.Differs != differs {
+			t.Errorf("row %q differs = %v, want %v", field, r.Differs, differs)
+		}
+	}
+	for _, field := range []string{"Source hint", "Container", "Command", "Warnings"} {
+		if _, ok := got[field]; ok {
+			t.Errorf("unexpected row %q", field)
+		}
+	}
+}
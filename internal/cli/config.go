@@ -0,0 +1,80 @@
+//go:build linux || darwin
+
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// config holds defaults read from the config file; flags override them
+type config struct {
+	// theme is the color theme of human-readable output
+	theme string
+}
+
+// configPath is the config file: witr/config in the user config directory
+// ($XDG_CONFIG_HOME or ~/.config on Linux, ~/Library/Application Support on
+// macOS), or $WITR_CONFIG when set
+func configPath() string {
+	if p := os.Getenv("WITR_CONFIG"); p != "" {
+		return p
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "witr", "config")
+}
+
+// loadConfig reads the config file. A missing file is an empty config.
+func loadConfig() (config, error) {
+	path := configPath()
+	if path == "" {
+		return config{}, nil
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return config{}, nil
+	}
+	if err != nil {
+		return config{}, err
+	}
+	defer f.Close()
+	c, err := parseConfig(f)
+	if err != nil {
+		return config{}, fmt.Errorf("%s: %v", path, err)
+	}
+	return c, nil
+}
+
+// parseConfig reads "key = value" lines; blank lines and lines starting
+// with # are ignored
+func parseConfig(r io.Reader) (config, error) {
+	var c config
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return config{}, fmt.Errorf("line %d: want key = value", n)
+		}
+		key, value = strings.TrimSpace(key), strings.Trim(strings.TrimSpace(value), `"`)
+		switch key {
+		case "theme":
+			c.theme = value
+		default:
+			return config{}, fmt.Errorf("line %d: unknown setting %q", n, key)
+		}
+	}
+	return c, sc.Err()
+}
@@ -0,0 +1,23 @@
+//go:build linux || darwin
+
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseConfig(t *testing.T) {
+	c, err := parseConfig(strings.NewReader("# witr settings\n\ntheme = \"light\"\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.theme != "light" {
+		t.Errorf("theme = %q, want light", c.theme)
+	}
+	for _, bad := range []string{"theme light\n", "colour = never\n"} {
+		if _, err := parseConfig(strings.NewReader(bad)); err == nil {
+			t.Errorf("parseConfig(%q) succeeded, want an error", bad)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+//go:build linux || darwin
+
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/internal/source"
+	"github.com/pranshuparmar/witr/internal/store"
+	"github.com/pranshuparmar/witr/pkg/model"
+	"github.com/spf13/cobra"
+)
+
+// pruneEvery is how often the daemon applies the retention policy
+const pruneEvery = time.Hour
+
+func newDaemonCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "daemon",
+		Short: "Record the origin of new processes as they start",
+		Long: "daemon watches for new processes and records each one's origin (source,\n" +
+			"ancestry, unit, container and session context) in a snapshot store while\n" +
+			"the process is new, so later queries with --history see the environment\n" +
+			"as it was at start time, even after parents, units or containers are gone.\n\n" +
+			"On Linux, processes are recorded as they call exec, from the kernel's process\n" +
+			"events connector. Where that is unavailable (macOS, or kernels without\n" +
+			"CONFIG_PROC_EVENTS) new processes are found by polling every --interval, and\n" +
+			"ones that exit sooner may be missed. Processes already running when the\n" +
+			"daemon starts are not recorded, as their origin can no longer be captured\n" +
+			"at start time.",
+		Args:    cobra.NoArgs,
+		PreRunE: rejectSnapshot,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			storeFlag, _ := cmd.Flags().GetString("store")
+			intervalFlag, _ := cmd.Flags().GetDuration("interval")
+			maxAgeFlag, _ := cmd.Flags().GetString("max-age")
+			maxSizeFlag, _ := cmd.Flags().GetString("max-size")
+
+			if storeFlag == "" {
+				return fmt.Errorf("--store is required (json:<dir> or sqlite:<file>)")
+			}
+			if intervalFlag <= 0 {
+				return fmt.Errorf("--interval must be positive")
+			}
+			maxAge, err := parseAge(maxAgeFlag)
+			if err != nil {
+				return err
+			}
+			maxSize, err := parseSize(maxSizeFlag)
+			if err != nil {
+				return fmt.Errorf("--max-size: %v", err)
+			}
+			s, err := store.Open(storeFlag)
+			if err != nil {
+				return err
+			}
+			defer s.Close()
+			return recordOrigins(s, intervalFlag, store.Retention{MaxAge: maxAge, MaxBytes: maxSize})
+		},
+	}
+
+	cmd.Flags().String("store", "", "snapshot store to write (json:<dir> or sqlite:<file>)")
+	cmd.Flags().Duration("interval", time.Second, "how often to save new records, and to look for new processes when polling")
+	cmd.Flags().String("max-age", "7d", "remove records older than this (0 keeps everything)")
+	cmd.Flags().String("max-size", "0", "remove the oldest records while the store is larger than this, e.g. 500M (0 for no limit)")
+	return cmd
+}
+
+// recordOrigins records new processes until interrupted, as they call exec
+// where the kernel reports it and by polling otherwise, saving the records
+// of each interval as one snapshot
+func recordOrigins(s store.Store, interval time.Duration, retention store.Retention) error {
+	host, _ := os.Hostname()
+	bootID := procpkg.BootID()
+	self := os.Getpid()
+
+	var results []model.Result
+	record := func(pid int) {
+		if pid == self {
+			return
+		}
+		if res, ok := originResult(pid, bootID); ok {
+			results = append(results, res)
+		}
+	}
+
+	// Processes are keyed by PID and start time so reused PIDs count as new.
+	// seen stays nil while exec events arrive.
+	var seen map[int]time.Time
+	execs, execErrs, err := watchExecs()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "witr daemon: %v; polling every %s instead\n", err, interval)
+		seen = procpkg.StartTimes()
+	}
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	lastPrune := time.Time{}
+
+	for {
+		select {
+		case <-stop:
+			return nil
+		case pid := <-execs:
+			record(pid)
+			continue
+		case err := <-execErrs:
+			return fmt.Errorf("reading exec events: %w", err)
+		case <-ticker.C:
+		}
+
+		if seen != nil {
+			pollNew(seen, record)
+		}
+		if len(results) > 0 {
+			snap := &store.Snapshot{Host: host, Kind: store.KindOrigins, BootID: bootID, Results: results}
+			if err := s.Save(snap); err != nil {
+				fmt.Fprintf(os.Stderr, "witr daemon: %v\n", err)
+			}
+			results = nil
+		}
+		if (retention.MaxAge > 0 || retention.MaxBytes > 0) && time.Since(lastPrune) >= pruneEvery {
+			if err := s.Prune(retention); err != nil {
+				fmt.Fprintf(os.Stderr, "witr daemon: %v\n", err)
+			}
+			lastPrune = time.Now()
+		}
+	}
+}
+
+// watchExecs sends the PID of each process that calls exec on the first
+// channel, and the error that ends the events on the second
+func watchExecs() (<-chan int, <-chan error, error) {
+	w, err := procpkg.WatchExecs()
+	if err != nil {
+		return nil, nil, err
+	}
+	pids := make(chan int, 256)
+	errs := make(chan error, 1)
+	go func() {
+		defer w.Close()
+		for {
+			pid, err := w.Next()
+			if err != nil {
+				errs <- err
+				return
+			}
+			pids <- pid
+		}
+	}()
+	return pids, errs, nil
+}
+
+// pollNew calls record for each process started since the last poll and
+// forgets processes that have exited
+func pollNew(seen map[int]time.Time, record func(pid int)) {
+	starts := procpkg.StartTimes()
+	for pid, started := range starts {
+		if prev, ok := seen[pid]; ok && prev.Equal(started) {
+			continue
+		}
+		seen[pid] = started
+		record(pid)
+	}
+	for pid := range seen {
+		if _, ok := starts[pid]; !ok {
+			delete(seen, pid)
+		}
+	}
+}
+
+// parseSize parses a byte count with an optional K, M or G suffix (powers
+// of 1024)
+func parseSize(s string) (int64, error) {
+	num, unit := strings.ToUpper(s), int64(1)
+	for suffix, u := range map[string]int64{"K": 1 << 10, "M": 1 << 20, "G": 1 << 30} {
+		if n, ok := strings.CutSuffix(num, suffix); ok {
+			num, unit = n, u
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(num, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return int64(n * float64(unit)), nil
+}
+
+// originResult captures how a new process was started. Only what describes
+// the origin is kept; live state is collected again at query time.
+func originResult(pid int, bootID string) (model.Result, bool) {
+	ancestry, err := procpkg.ResolveAncestry(pid)
+	if err != nil {
+		return model.Result{}, false
+	}
+	p := ancestry[len(ancestry)-1]
+	// Kernel threads have no command line and no interesting origin
+	if p.Cmdline == "" {
+		return model.Result{}, false
+	}
+	res := model.Result{
+		Target:         model.Target{Type: model.TargetPID, Value: strconv.Itoa(pid)},
+		ResolvedTarget: p.Command,
+		Process:        p,
+		Ancestry:       ancestry,
+		Source:         source.Detect(ancestry),
+		BootID:         bootID,
+	}
+	sampled(&res, model.SampledProcess)
+	sampled(&res, model.SampledSource)
+	redactResult(&res)
+	return res, true
+}
@@ -0,0 +1,107 @@
+//go:build linux || darwin
+
+package cli
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/internal/progress"
+	"github.com/pranshuparmar/witr/internal/store"
+	"github.com/pranshuparmar/witr/pkg/model"
+	"github.com/spf13/cobra"
+)
+
+func newDumpCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dump",
+		Short: "Write a snapshot of every process for offline analysis",
+		Long: "dump explains every process on the host and writes the results, with their\n" +
+			"ancestry, sockets and sources, as one JSON snapshot on stdout:\n\n" +
+			"  witr dump > snapshot.json\n\n" +
+			"witr, scan, check and stale accept --from-snapshot snapshot.json to work\n" +
+			"on the captured system instead of the live one, for offline analysis,\n" +
+			"reproducible bug reports and testing detectors against real hosts.\n" +
+			"Secret-looking environment values are redacted unless --unsafe-env is given.\n\n" +
+			"With --store the snapshot is saved to a snapshot store instead, where\n" +
+			"witr fleet correlates the latest one of every host:\n\n" +
+			"  witr dump --store json:/srv/witr",
+		Args:    cobra.NoArgs,
+		PreRunE: rejectSnapshot,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mineFlag, _ := cmd.Flags().GetBool("mine")
+			unsafeEnvFlag, _ := cmd.Flags().GetBool("unsafe-env")
+			storeFlag, _ := cmd.Flags().GetString("store")
+
+			prog, err := progressFromFlags(cmd)
+			if err != nil {
+				return err
+			}
+			uid := -1
+			if mineFlag {
+				uid = os.Getuid()
+			}
+			// Open the store first so a bad URI fails before the dump runs
+			var s store.Store
+			if storeFlag != "" {
+				if s, err = store.Open(storeFlag); err != nil {
+					return err
+				}
+				defer s.Close()
+			}
+			snap, err := dumpSnapshot(uid, unsafeEnvFlag, prog)
+			if err != nil {
+				return err
+			}
+			if s != nil {
+				return s.Save(snap)
+			}
+			return store.WriteSnapshot(os.Stdout, snap)
+		},
+	}
+
+	cmd.Flags().Bool("mine", false, "only dump processes owned by the invoking user")
+	cmd.Flags().Bool("unsafe-env", false, "keep full environment values, including secrets")
+	cmd.Flags().String("store", "", "save the snapshot to a store (json:<dir> or sqlite:<file>) instead of stdout")
+	addProgressFlag(cmd)
+	return cmd
+}
+
+// dumpSnapshot explains every process (or every process of uid) into a
+// snapshot, in PID order
+func dumpSnapshot(uid int, unsafeEnv bool, prog *progress.Reporter) (*store.Snapshot, error) {
+	pids, err := procpkg.ListPIDs(uid)
+	if err != nil {
+		return nil, err
+	}
+	host, _ := os.Hostname()
+	snap := &store.Snapshot{TakenAt: time.Now(), Host: host, Kind: store.KindHost, BootID: procpkg.BootID()}
+	defer procpkg.CacheTable()()
+	self := os.Getpid()
+	prog.Start("dump", len(pids))
+	results := parallelMap(pids, func(pid int) *model.Result {
+		defer prog.Step("pid " + strconv.Itoa(pid))
+		if pid == self {
+			return nil
+		}
+		// Processes that exit while the dump runs are left out
+		ancestry, err := procpkg.ResolveAncestry(pid)
+		if err != nil {
+			return nil
+		}
+		res := explain(model.Target{Type: model.TargetPID, Value: strconv.Itoa(pid)}, ancestry)
+		if !unsafeEnv {
+			redactResult(&res)
+		}
+		return &res
+	})
+	prog.Finish()
+	for _, res := range results {
+		if res != nil {
+			snap.Results = append(snap.Results, *res)
+		}
+	}
+	return snap, nil
+}
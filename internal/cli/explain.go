@@ -0,0 +1,144 @@
+//go:build linux || darwin
+
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/internal/source"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// explain builds the full result for a resolved target from its ancestry
+// (root first, target last). An empty ancestry yields a failed result.
+func explain(t model.Target, ancestry []model.Process) model.Result {
+	if len(ancestry) == 0 {
+		return model.Result{Target: t, Error: "no process ancestry found"}
+	}
+	proc := ancestry[len(ancestry)-1]
+	pid := proc.PID
+	// The ancestry was read just before
+	readAt := time.Now()
+
+	src := source.Detect(ancestry)
+
+	// Calculate restart count (consecutive same-command entries)
+	restartCount := 0
+	lastCmd := ""
+	for _, procA := range ancestry {
+		if procA.Command == lastCmd {
+			restartCount++
+		}
+		lastCmd = procA.Command
+	}
+
+	res := model.Result{
+		Target:         t,
+		ResolvedTarget: proc.Command,
+		Process:        proc,
+		RestartCount:   restartCount,
+		Ancestry:       ancestry,
+		Source:         src,
+		BootID:         procpkg.BootID(),
+		SampledAt:      map[string]time.Time{model.SampledProcess: readAt},
+	}
+	sampled(&res, model.SampledSource)
+	res.AddWarnings(source.Warnings(ancestry, src)...)
+	res.Login = procpkg.LoginSession(pid)
+	res.Service = source.ServiceDetails(src)
+	if res.Container = source.ContainerDetails(src, proc); res.Container != nil {
+		sampled(&res, model.SampledContainer)
+	}
+	res.AddWarnings(source.Drift(proc, src)...)
+	res.AddWarnings(source.CrashLoop(src, proc, res.Service)...)
+
+	// Add socket state info for port queries
+	if t.Type == model.TargetPort {
+		portNum := 0
+		fmt.Sscanf(t.Value, "%d", &portNum)
+		if portNum > 0 {
+			res.SocketInfo = procpkg.GetSocketStateForPort(portNum)
+			sampled(&res, model.SampledSocket)
+		}
+	}
+
+	// Count the family, summarizing a pathologically large descendant set
+	var d *model.DescendantSummary
+	if res.Family, d = procpkg.Family(pid); d != nil {
+		res.Descendants = d
+		res.AddWarnings(source.DescendantWarning(d))
+	}
+
+	// Root in a user namespace is an unprivileged user on the host
+	if res.Sandbox = procpkg.Sandbox(); res.Sandbox != nil && res.Sandbox.RootUID != "" {
+		// Every warning so far was added with AddWarnings, so Findings and
+		// Warnings line up
+		for i, f := range res.Findings {
+			if f.Code == model.WarnRoot {
+				f = model.Warning{Code: model.WarnRootInUserNamespace, Severity: model.SeverityInfo,
+					Message: "Process is running as root in a user namespace (uid " + res.Sandbox.RootUID + " on the host)"}
+				res.Findings[i], res.Warnings[i] = f, f.Message
+			}
+		}
+	}
+
+	// Add resource context (thermal state, sleep prevention)
+	res.ResourceContext = procpkg.GetResourceContext(pid)
+	sampled(&res, model.SampledResources)
+
+	// cgroup limits and throttling
+	if res.Cgroup = procpkg.GetCgroup(pid); res.Cgroup != nil {
+		sampled(&res, model.SampledCgroup)
+		res.AddWarnings(source.CgroupWarnings(res.Cgroup)...)
+	}
+
+	// GPU devices held open, with NVIDIA utilization
+	res.GPU = procpkg.GetGPU(pid)
+
+	// Resource limits and how close the process is to them
+	res.Limits = procpkg.GetLimits(proc)
+	res.AddWarnings(source.LimitWarnings(res.Limits)...)
+
+	// Add file context (open files, locks)
+	res.FileContext = procpkg.GetFileContext(pid)
+	sampled(&res, model.SampledFiles)
+
+	// Kernel integrity measurements of the executable
+	res.Integrity = procpkg.GetIntegrity(proc.Exe)
+	sampled(&res, model.SampledIntegrity)
+
+	// The package database describes the host, not a container's filesystem
+	if src.Type != model.SourceContainer {
+		if res.Package = procpkg.ExePackage(proc.Exe); res.Package != nil {
+			sampled(&res, model.SampledPackage)
+			if res.Package.Name == "" {
+				res.AddWarnings(model.Warning{Code: model.WarnUnpackagedBinary, Severity: model.SeverityLow,
+					Message: "Executable is not owned by any " + res.Package.Manager + " package (hand-installed binary): " + proc.Exe})
+			}
+		}
+	}
+
+	return res
+}
+
+// sampled records that a part of the result was collected now
+func sampled(res *model.Result, part string) {
+	if res.SampledAt == nil {
+		res.SampledAt = make(map[string]time.Time)
+	}
+	res.SampledAt[part] = time.Now()
+}
+
+// redactResult redacts secret-looking environment values of the process and
+// its ancestors before they are rendered or stored
+func redactResult(res *model.Result) {
+	res.Process.Env = procpkg.RedactEnv(res.Process.Env)
+	ancestry := make([]model.Process, len(res.Ancestry))
+	for i, p := range res.Ancestry {
+		p.Env = procpkg.RedactEnv(p.Env)
+		ancestry[i] = p
+	}
+	res.Ancestry = ancestry
+}
@@ -0,0 +1,16 @@
+//go:build linux || darwin
+
+package cli
+
+import (
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestExplainEmptyAncestry(t *testing.T) {
+	res := explain(model.Target{Type: model.TargetPID, Value: "42"}, nil)
+	if res.Error == "" {
+		t.Errorf("explain(nil) = %+v, want a failed result", res)
+	}
+}
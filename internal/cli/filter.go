@@ -0,0 +1,94 @@
+//go:build linux || darwin
+
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+	"github.com/spf13/cobra"
+)
+
+// sourceAliases groups source types under the names users filter by;
+// "manual" covers anything started by hand from a shell or SSH session
+var sourceAliases = map[string][]model.SourceType{
+	"manual": {model.SourceShell, model.SourceSSH},
+}
+
+var knownSourceTypes = []model.SourceType{
+	model.SourceContainer, model.SourceSystemd, model.SourceLaunchd, model.SourceSupervisor,
+	model.SourceCron, model.SourceShell, model.SourceBackup, model.SourceSSH, model.SourceSubsystem,
+	model.SourceDesktop, model.SourceUdev, model.SourceSnap, model.SourceFlatpak, model.SourceAppImage,
+	model.SourceWSL, model.SourceUnknown,
+}
+
+// sourceFilter keeps results whose source type is included (if any
+// inclusions are given) and not excluded
+type sourceFilter struct {
+	include map[model.SourceType]bool
+	exclude map[model.SourceType]bool
+}
+
+func addSourceFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSlice("source", nil, "only include these source types (systemd, container, cron, manual, unknown, ...)")
+	cmd.Flags().StringSlice("not-source", nil, "exclude these source types")
+}
+
+func sourceFilterFromFlags(cmd *cobra.Command) (*sourceFilter, error) {
+	include, _ := cmd.Flags().GetStringSlice("source")
+	exclude, _ := cmd.Flags().GetStringSlice("not-source")
+	f := &sourceFilter{}
+	var err error
+	if f.include, err = parseSourceTypes(include); err != nil {
+		return nil, err
+	}
+	if f.exclude, err = parseSourceTypes(exclude); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func parseSourceTypes(names []string) (map[model.SourceType]bool, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	types := make(map[model.SourceType]bool)
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if alias, ok := sourceAliases[name]; ok {
+			for _, t := range alias {
+				types[t] = true
+			}
+			continue
+		}
+		found := false
+		for _, t := range knownSourceTypes {
+			if string(t) == name {
+				types[t] = true
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown source type %q", name)
+		}
+	}
+	return types, nil
+}
+
+func (f *sourceFilter) match(r model.Result) bool {
+	if f.include != nil && !f.include[r.Source.Type] {
+		return false
+	}
+	return !f.exclude[r.Source.Type]
+}
+
+// wrap returns fn limited to results that match the filter
+func (f *sourceFilter) wrap(fn func(model.Result) error) func(model.Result) error {
+	return func(r model.Result) error {
+		if !f.match(r) {
+			return nil
+		}
+		return fn(r)
+	}
+}
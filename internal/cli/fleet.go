@@ -0,0 +1,87 @@
+//go:build linux || darwin
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pranshuparmar/witr/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func newFleetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fleet",
+		Short: "Correlate processes across the hosts in a snapshot store",
+		Long: "fleet reads the latest full-host snapshot of every host in a store, as\n" +
+			"written by witr dump --store, and groups identical binaries (or command\n" +
+			"lines with --by cmdline) across hosts and users. Snapshots saved by\n" +
+			"witr daemon hold only newly started processes and are not used.\n\n" +
+			"With --outliers only processes running on at most --max-hosts hosts are\n" +
+			"shown, for fleet-wide anomaly hunting.",
+		Args:    cobra.NoArgs,
+		PreRunE: rejectSnapshot,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			storeFlag, _ := cmd.Flags().GetString("store")
+			byFlag, _ := cmd.Flags().GetString("by")
+			outliersFlag, _ := cmd.Flags().GetBool("outliers")
+			maxHostsFlag, _ := cmd.Flags().GetInt("max-hosts")
+			jsonFlag, _ := cmd.Flags().GetBool("json")
+
+			if storeFlag == "" {
+				return fmt.Errorf("--store is required (json:<dir> or sqlite:<file>)")
+			}
+			s, err := store.Open(storeFlag)
+			if err != nil {
+				return err
+			}
+			defer s.Close()
+
+			snaps, err := store.LatestPerHost(s)
+			if err != nil {
+				return err
+			}
+			if len(snaps) == 0 {
+				return fmt.Errorf("no full-host snapshots in %s; save them with witr dump --store", storeFlag)
+			}
+			groups, hosts, err := store.Correlate(snaps, byFlag)
+			if err != nil {
+				return err
+			}
+			if outliersFlag {
+				groups = store.Outliers(groups, hosts, maxHostsFlag)
+			}
+
+			if jsonFlag {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(struct {
+					Hosts  int
+					Groups []store.Group
+				}{hosts, groups})
+			}
+			for _, g := range groups {
+				where := strings.Join(g.Hosts, ", ")
+				if len(g.Hosts) > 5 {
+					where = strings.Join(g.Hosts[:5], ", ") + ", ..."
+				}
+				fmt.Printf("%s\n  runs on %d of %d hosts (%s)", g.Key, len(g.Hosts), hosts, where)
+				if len(g.Users) > 0 {
+					fmt.Printf("; users: %s", strings.Join(g.Users, ", "))
+				}
+				fmt.Println()
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String("store", "", "snapshot store to read (json:<dir> or sqlite:<file>)")
+	cmd.Flags().String("by", store.ByExe, "group by exe or cmdline")
+	cmd.Flags().Bool("outliers", false, "only show processes running on few hosts")
+	cmd.Flags().Int("max-hosts", 1, "host count at or below which a process is an outlier")
+	cmd.Flags().Bool("json", false, "output as JSON")
+	return cmd
+}
@@ -0,0 +1,83 @@
+//go:build linux || darwin
+
+package cli
+
+import (
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/pranshuparmar/witr/internal/output"
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// followInterval is how often --follow-children looks for new descendants
+const followInterval = 250 * time.Millisecond
+
+// followChildren emits a result for every descendant pid spawns until pid
+// exits or witr is interrupted. Descendants are found by polling, so ones
+// that exit within an interval, or that daemonize away from pid, are missed.
+func followChildren(renderer output.Renderer, pid int, unsafeEnv bool) error {
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(stop)
+	ticker := time.NewTicker(followInterval)
+	defer ticker.Stop()
+
+	// Children already running are part of the target, not spawned by it
+	seen := make(map[int]bool)
+	existing, _ := procpkg.Descendants(pid)
+	for _, child := range existing {
+		seen[child] = true
+	}
+
+	for {
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+		}
+
+		children, ok := procpkg.Descendants(pid)
+		if !ok {
+			return nil
+		}
+		current := make(map[int]bool, len(children))
+		for _, child := range children {
+			current[child] = true
+			if seen[child] {
+				continue
+			}
+			seen[child] = true
+			if res, ok := childResult(child, unsafeEnv); ok {
+				if err := renderer.Emit(res); err != nil {
+					return err
+				}
+			}
+		}
+		// Forget exited children so a reused PID is reported again
+		for child := range seen {
+			if !current[child] {
+				delete(seen, child)
+			}
+		}
+	}
+}
+
+// childResult explains a new descendant, or reports false if it exited
+// before it could be read. CPU is not sampled: most children are short-lived
+// and the wait would delay the next poll.
+func childResult(pid int, unsafeEnv bool) (model.Result, bool) {
+	ancestry, err := procpkg.ResolveAncestry(pid)
+	if err != nil {
+		return model.Result{}, false
+	}
+	res := explain(model.Target{Type: model.TargetPID, Value: strconv.Itoa(pid)}, ancestry)
+	if !unsafeEnv {
+		redactResult(&res)
+	}
+	return res, true
+}
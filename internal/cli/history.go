@@ -0,0 +1,48 @@
+//go:build linux || darwin
+
+package cli
+
+import (
+	"github.com/pranshuparmar/witr/internal/store"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// applyHistory replaces the live origin of a result with the one witr daemon
+// recorded when the process started, if the store has it. The recorded
+// ancestry keeps parents that have exited since; the process itself stays live.
+func applyHistory(res *model.Result, uri string) error {
+	s, err := store.Open(uri)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	if res.BootID == "" || res.Process.StartedAt.IsZero() {
+		return nil
+	}
+	recorded, at, err := store.FindOrigin(s, res.BootID, res.Process.PID, res.Process.StartedAt)
+	if err != nil || recorded == nil {
+		return err
+	}
+
+	// The origin is as old as the record, not the query
+	res.SampledAt[model.SampledSource] = at
+	src := recorded.Source
+	src.Evidence = append([]string{"recorded by witr daemon at " + at.Local().Format("2006-01-02 15:04:05")}, src.Evidence...)
+	res.Source = src
+	if res.Process.Service == "" {
+		res.Process.Service = recorded.Process.Service
+	}
+	if res.Process.Container == "" {
+		res.Process.Container = recorded.Process.Container
+	}
+	// The target ends the ancestry too, so it carries the backfilled unit
+	// and container
+	if n := len(recorded.Ancestry); n > 0 {
+		ancestry := append([]model.Process{}, recorded.Ancestry[:n-1]...)
+		res.Ancestry = append(ancestry, res.Process)
+	} else if n := len(res.Ancestry); n > 0 {
+		res.Ancestry[n-1] = res.Process
+	}
+	return nil
+}
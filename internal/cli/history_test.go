@@ -0,0 +1,46 @@
+//go:build linux || darwin
+
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pranshuparmar/witr/internal/store"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestApplyHistoryBackfillsAncestry(t *testing.T) {
+	dir := t.TempDir()
+	s, err := store.OpenJSON(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	started := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	recorded := model.Process{PID: 100, StartedAt: started, Service: "web.service", Container: "web"}
+	err = s.Save(&store.Snapshot{TakenAt: started, BootID: "boot-a", Results: []model.Result{{
+		Process:  recorded,
+		Ancestry: []model.Process{{PID: 1, Command: "systemd"}, recorded},
+		Source:   model.Source{Type: model.SourceSystemd, Name: "systemd"},
+	}}})
+	s.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The unit and container are gone by query time
+	live := model.Process{PID: 100, StartedAt: started}
+	res := model.Result{
+		Process:   live,
+		Ancestry:  []model.Process{{PID: 1, Command: "systemd"}, live},
+		BootID:    "boot-a",
+		SampledAt: map[string]time.Time{},
+	}
+	if err := applyHistory(&res, "json:"+dir); err != nil {
+		t.Fatal(err)
+	}
+	last := res.Ancestry[len(res.Ancestry)-1]
+	if res.Process.Service != "web.service" || last.Service != "web.service" || last.Container != "web" {
+		t.Errorf("after applyHistory Process = %+v, last ancestor = %+v; want web.service and web in both", res.Process, last)
+	}
+}
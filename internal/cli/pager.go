@@ -0,0 +1,94 @@
+//go:build linux || darwin
+
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"regexp"
+	"strings"
+	"syscall"
+	"unicode/utf8"
+	"unsafe"
+
+	"github.com/pranshuparmar/witr/internal/output"
+)
+
+// defaultPager is used when $PAGER is unset; -R passes colors through
+const defaultPager = "less -R"
+
+// openOutput opens the --output sink. With page set and the result going
+// to a terminal, output is held back and shown through the pager if it
+// does not fit on the screen.
+func openOutput(uri string, page bool) (io.WriteCloser, error) {
+	if page && !redirected(uri) {
+		if rows, cols, ok := terminalSize(os.Stdout); ok {
+			return &pagerSink{rows: rows, cols: cols}, nil
+		}
+	}
+	return output.OpenSink(uri)
+}
+
+// pagerSink buffers output and, on Close, pipes it through $PAGER when it
+// is taller than the terminal, as git and systemctl do
+type pagerSink struct {
+	rows, cols int
+	buf        bytes.Buffer
+}
+
+func (p *pagerSink) Write(b []byte) (int, error) { return p.buf.Write(b) }
+
+func (p *pagerSink) Close() error {
+	pager := os.Getenv("PAGER")
+	if pager == "" {
+		pager = defaultPager
+	}
+	if pager == "cat" || screenLines(p.buf.String(), p.cols) < p.rows {
+		_, err := os.Stdout.Write(p.buf.Bytes())
+		return err
+	}
+	cmd := exec.Command("sh", "-c", pager)
+	cmd.Stdin = bytes.NewReader(p.buf.Bytes())
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	// Ctrl-C belongs to the pager while it runs
+	signal.Ignore(os.Interrupt)
+	defer signal.Reset(os.Interrupt)
+	err := cmd.Run()
+	if exit := (*exec.ExitError)(nil); err != nil && (!errors.As(err, &exit) || exit.ExitCode() == 127) {
+		// No usable pager: print the output as if there were none
+		_, err := os.Stdout.Write(p.buf.Bytes())
+		return err
+	}
+	return nil
+}
+
+// ansiEscape matches the escape sequences color output uses, which take
+// no columns on screen
+var ansiEscape = regexp.MustCompile("\x1b\\[[0-9;?]*[A-Za-z]")
+
+// screenLines estimates how many terminal rows text takes, counting long
+// lines as wrapped at cols
+func screenLines(text string, cols int) int {
+	n := 0
+	for line := range strings.Lines(text) {
+		width := utf8.RuneCountInString(ansiEscape.ReplaceAllString(strings.TrimRight(line, "\n"), ""))
+		n += max(1, (width+cols-1)/cols)
+	}
+	return n
+}
+
+// terminalSize returns the rows and columns of the terminal f is, or false
+// when f is not a terminal
+func terminalSize(f *os.File) (rows, cols int, ok bool) {
+	var ws struct{ Row, Col, X, Y uint16 }
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&ws)))
+	if errno != 0 || ws.Row == 0 || ws.Col == 0 {
+		return 0, 0, false
+	}
+	return int(ws.Row), int(ws.Col), true
+}
@@ -0,0 +1,20 @@
+//go:build linux || darwin
+
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScreenLines(t *testing.T) {
+	text := "short\n\n" + strings.Repeat("x", 25) + "\nlast"
+	if got := screenLines(text, 10); got != 6 {
+		t.Errorf("screenLines = %d, want 6", got)
+	}
+	// Color codes take no columns
+	colored := "\x1b[1;36m" + strings.Repeat("x", 10) + "\x1b[0m"
+	if got := screenLines(colored, 10); got != 1 {
+		t.Errorf("screenLines(colored) = %d, want 1", got)
+	}
+}
@@ -0,0 +1,36 @@
+//go:build linux || darwin
+
+package cli
+
+import (
+	"runtime"
+	"sync"
+)
+
+// maxWorkers bounds how many targets are explained at once; each one runs
+// several helper commands
+const maxWorkers = 8
+
+// parallelMap applies fn to every item concurrently and returns the results
+// in input order
+func parallelMap[T, R any](items []T, fn func(T) R) []R {
+	out := make([]R, len(items))
+	workers := min(maxWorkers, runtime.GOMAXPROCS(0), len(items))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				out[i] = fn(items[i])
+			}
+		}()
+	}
+	for i := range items {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return out
+}
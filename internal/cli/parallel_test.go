@@ -0,0 +1,24 @@
+//go:build linux || darwin
+
+package cli
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParallelMapKeepsOrder(t *testing.T) {
+	items := make([]int, 100)
+	for i := range items {
+		items[i] = i
+	}
+	got := parallelMap(items, func(n int) int { return n * n })
+	for i, v := range got {
+		if v != i*i {
+			t.Fatalf("parallelMap()[%d] = %d, want %d", i, v, i*i)
+		}
+	}
+	if got := parallelMap([]int{}, func(n int) int { return n }); !slices.Equal(got, []int{}) {
+		t.Errorf("parallelMap(empty) = %v", got)
+	}
+}
@@ -0,0 +1,85 @@
+//go:build linux || darwin
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	osuser "os/user"
+	"strconv"
+	"strings"
+
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/internal/target"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// preflight reports what explaining t needs and whether the current user
+// can access it, without explaining anything
+func preflight(w io.Writer, t model.Target, audit, verify, jsonOut bool) error {
+	// Ports and sockets are resolved by reading other processes' fds, which
+	// Preflight checks instead; names are cheap to resolve
+	pid := 0
+	switch t.Type {
+	case model.TargetPID:
+		n, err := strconv.Atoi(t.Value)
+		if err != nil {
+			return fmt.Errorf("invalid pid")
+		}
+		pid = n
+	case model.TargetName:
+		if pids, err := target.Resolve(t); err == nil && len(pids) == 1 {
+			pid = pids[0]
+		}
+	}
+
+	checks := procpkg.Preflight(t, pid)
+	if audit {
+		checks = append(checks,
+			procpkg.CheckCommand("ausearch", "execve records for --audit"),
+			procpkg.CheckFile("/var/log/audit/audit.log", "execve records for --audit (without ausearch)"))
+	}
+	if verify {
+		checks = append(checks, procpkg.CheckCommand("cosign", "signature verification for --verify-signature"))
+	}
+
+	if jsonOut {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(checks)
+	}
+
+	who := "uid " + strconv.Itoa(os.Geteuid())
+	if u, err := osuser.Current(); err == nil {
+		who = u.Username + " (" + who + ")"
+	}
+	fmt.Fprintf(w, "Preflight for %s %s, running as %s:\n\n", t.Type, t.Value, who)
+	width := 0
+	for _, c := range checks {
+		width = max(width, len(c.Resource))
+	}
+	denied := false
+	for _, c := range checks {
+		line := fmt.Sprintf("  %-7s %-*s  %s", c.Status, width, c.Resource, c.Purpose)
+		if c.Detail != "" {
+			line += " (" + c.Detail + ")"
+		}
+		fmt.Fprintln(w, line)
+		denied = denied || c.Status == procpkg.PreflightDenied
+	}
+	switch {
+	case denied && os.Geteuid() == 0:
+		fmt.Fprintln(w, "\nSome checks were denied even as root (by a security module, ptrace restrictions or a\ncontainer boundary); those parts of the result will be missing.")
+	case denied:
+		args := []string{}
+		for _, a := range os.Args[1:] {
+			if a != "--preflight" {
+				args = append(args, a)
+			}
+		}
+		fmt.Fprintf(w, "\nSome checks were denied; for complete results run:\n  sudo witr %s\n", strings.Join(args, " "))
+	}
+	return nil
+}
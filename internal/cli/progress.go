@@ -0,0 +1,21 @@
+//go:build linux || darwin
+
+package cli
+
+import (
+	"os"
+
+	"github.com/pranshuparmar/witr/internal/progress"
+	"github.com/spf13/cobra"
+)
+
+func addProgressFlag(cmd *cobra.Command) {
+	cmd.Flags().String("progress", progress.ModeAuto, "progress on stderr: auto (spinner on a terminal), none, human or json")
+}
+
+// progressFromFlags returns the reporter selected by --progress; nil
+// reports nothing
+func progressFromFlags(cmd *cobra.Command) (*progress.Reporter, error) {
+	mode, _ := cmd.Flags().GetString("progress")
+	return progress.New(mode, os.Stderr)
+}
@@ -0,0 +1,97 @@
+//go:build linux || darwin
+
+package cli
+
+import (
+	"errors"
+	"os"
+
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/internal/store"
+	"github.com/pranshuparmar/witr/internal/target"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// Exit statuses of --quiet
+const (
+	quietFound      = 0
+	quietNotFound   = 1
+	quietWarnings   = 2
+	quietPermission = 3
+)
+
+// quietRank orders statuses from best to worst, for combining the statuses
+// of several targets
+var quietRank = map[int]int{quietFound: 0, quietWarnings: 1, quietNotFound: 2, quietPermission: 3}
+
+// worseStatus returns the worse of two --quiet statuses
+func worseStatus(a, b int) int {
+	if quietRank[b] > quietRank[a] {
+		return b
+	}
+	return a
+}
+
+// quietStatus looks up targets without printing anything and returns the
+// exit status: found, found with warnings, not found, or not visible
+// without more privileges. Several targets take the worst status, and every
+// process a name matches is checked for warnings.
+func quietStatus(snap *store.Snapshot, targets []model.Target, o enrichOptions) int {
+	status := quietFound
+	for _, t := range targets {
+		if snap != nil {
+			status = worseStatus(status, resultsStatus(snapshotMatches(snap, t)))
+		} else {
+			status = worseStatus(status, liveStatus(t, o))
+		}
+	}
+	return status
+}
+
+// liveStatus is the --quiet status of one target on the live system
+func liveStatus(t model.Target, o enrichOptions) int {
+	pids, err := target.Resolve(t)
+	if amb := (*target.AmbiguousError)(nil); errors.As(err, &amb) {
+		// A process and a service both match the name: something is there
+		return quietFound
+	}
+	if err != nil {
+		return errorStatus(err)
+	}
+	var results []model.Result
+	for _, pid := range pids {
+		ancestry, err := procpkg.ResolveAncestry(pid)
+		if err != nil {
+			return errorStatus(err)
+		}
+		res := explain(t, ancestry)
+		if err := o.enrich(&res, pid); err != nil {
+			return errorStatus(err)
+		}
+		results = append(results, res)
+	}
+	return resultsStatus(results)
+}
+
+// resultsStatus is found, or found with warnings, for the processes a
+// target matched, and not found when it matched none
+func resultsStatus(results []model.Result) int {
+	if len(results) == 0 {
+		return quietNotFound
+	}
+	for _, r := range results {
+		if len(r.Warnings) > 0 {
+			return quietWarnings
+		}
+	}
+	return quietFound
+}
+
+// errorStatus tells a lookup that failed for lack of privileges from a
+// target that does not exist
+func errorStatus(err error) int {
+	if errors.Is(err, os.ErrPermission) || errors.Is(err, target.ErrOwnerHidden) {
+		return quietPermission
+	}
+	return quietNotFound
+}
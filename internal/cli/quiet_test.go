@@ -0,0 +1,31 @@
+//go:build linux || darwin
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"syscall"
+	"testing"
+
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+)
+
+func TestErrorStatus(t *testing.T) {
+	// A PID above pid_max never exists
+	_, err := procpkg.ResolveAncestry(1 << 30)
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("ResolveAncestry() error = %v, want it to wrap fs.ErrNotExist", err)
+	}
+	if got := errorStatus(err); got != quietNotFound {
+		t.Errorf("errorStatus(%v) = %d, want %d", err, got, quietNotFound)
+	}
+
+	// As ResolveAncestry wraps a /proc read denied by hidepid
+	denied := &fs.PathError{Op: "open", Path: "/proc/42/stat", Err: syscall.EACCES}
+	err = fmt.Errorf("no process ancestry found: %w", fmt.Errorf("reading process 42: %w", denied))
+	if got := errorStatus(err); got != quietPermission {
+		t.Errorf("errorStatus(%v) = %d, want %d (exit 3)", err, got, quietPermission)
+	}
+}
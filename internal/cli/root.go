@@ -0,0 +1,461 @@
+//go:build linux || darwin
+
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pranshuparmar/witr/internal/output"
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/internal/target"
+	"github.com/pranshuparmar/witr/pkg/model"
+	"github.com/spf13/cobra"
+)
+
+// Execute runs the witr command line with the given build information
+func Execute(version, commit, buildDate string) {
+	rootCmd := &cobra.Command{
+		Use:   "witr [process name]...",
+		Short: "Explain processes",
+		Long:  "witr explains processes and their ancestry, showing how they were started and what they are doing.",
+		Args:  cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if schemaFlag, _ := cmd.Flags().GetBool("schema"); schemaFlag {
+				schema, err := model.Schema()
+				if err != nil {
+					return err
+				}
+				_, err = fmt.Println(string(schema))
+				return err
+			}
+			envFlag, _ := cmd.Flags().GetBool("env")
+			pidFlag, _ := cmd.Flags().GetString("pid")
+			portFlag, _ := cmd.Flags().GetString("port")
+			socketFlag, _ := cmd.Flags().GetString("socket")
+			shortFlag, _ := cmd.Flags().GetBool("short")
+			treeFlag, _ := cmd.Flags().GetBool("tree")
+			jsonFlag, _ := cmd.Flags().GetBool("json")
+			ndjsonFlag, _ := cmd.Flags().GetBool("ndjson")
+			formatFlag, _ := cmd.Flags().GetString("format")
+			csvFlag, _ := cmd.Flags().GetBool("csv")
+			tsvFlag, _ := cmd.Flags().GetBool("tsv")
+			dotFlag, _ := cmd.Flags().GetBool("dot")
+			markdownFlag, _ := cmd.Flags().GetBool("markdown")
+			htmlFlag, _ := cmd.Flags().GetBool("html")
+			warnFlag, _ := cmd.Flags().GetBool("warnings")
+			noColorFlag, _ := cmd.Flags().GetBool("no-color")
+			colorFlag, _ := cmd.Flags().GetString("color")
+			themeFlag, _ := cmd.Flags().GetString("theme")
+			asciiFlag, _ := cmd.Flags().GetBool("ascii")
+			indentFlag, _ := cmd.Flags().GetInt("indent")
+			investigateFlag, _ := cmd.Flags().GetBool("investigate")
+			verifyFlag, _ := cmd.Flags().GetBool("verify-signature")
+			connectionsFlag, _ := cmd.Flags().GetBool("connections")
+			auditFlag, _ := cmd.Flags().GetBool("audit")
+			unsafeEnvFlag, _ := cmd.Flags().GetBool("unsafe-env")
+			historyFlag, _ := cmd.Flags().GetString("history")
+			outputFlag, _ := cmd.Flags().GetString("output")
+			preflightFlag, _ := cmd.Flags().GetBool("preflight")
+			stdinFlag, _ := cmd.Flags().GetBool("stdin")
+			followFlag, _ := cmd.Flags().GetBool("follow-children")
+			childrenFlag, _ := cmd.Flags().GetBool("children")
+			depthFlag, _ := cmd.Flags().GetInt("depth")
+			logsFlag, _ := cmd.Flags().GetInt("logs")
+			fdsFlag, _ := cmd.Flags().GetBool("fds")
+			quietFlag, _ := cmd.Flags().GetBool("quiet")
+			verboseFlag, _ := cmd.Flags().GetBool("verbose")
+			wideFlag, _ := cmd.Flags().GetBool("wide")
+			verbose := verboseFlag || wideFlag
+			noPagerFlag, _ := cmd.Flags().GetBool("no-pager")
+			page := !noPagerFlag
+
+			opts := enrichOptions{
+				history:     historyFlag,
+				audit:       auditFlag,
+				connections: connectionsFlag || verbose,
+				investigate: investigateFlag || verbose,
+				verify:      verifyFlag,
+				unsafeEnv:   unsafeEnvFlag,
+				logs:        logsFlag,
+				children:    childrenFlag || cmd.Flags().Changed("depth"),
+				depth:       depthFlag,
+				fds:         fdsFlag || verbose,
+			}
+			if depthFlag < 0 || logsFlag < 0 {
+				return fmt.Errorf("--depth and --logs must not be negative")
+			}
+			format := output.FormatStandard
+			switch {
+			case formatFlag != "":
+				if _, err := output.ParseTemplate(formatFlag); err != nil {
+					return fmt.Errorf("--format: %v", err)
+				}
+				format = output.TemplateFormat(formatFlag)
+			case ndjsonFlag:
+				format = output.FormatNDJSON
+			case csvFlag:
+				format = output.FormatCSV
+			case tsvFlag:
+				format = output.FormatTSV
+			case dotFlag:
+				format = output.FormatDOT
+			case markdownFlag:
+				format = output.FormatMarkdown
+			case htmlFlag:
+				format = output.FormatHTML
+			case jsonFlag:
+				format = output.FormatJSON
+			case warnFlag:
+				format = output.FormatWarnings
+			case treeFlag:
+				format = output.FormatTree
+			case shortFlag:
+				format = output.FormatShort
+			case verbose:
+				format = output.FormatVerbose
+			}
+			colorOut := os.Stdout
+			if redirected(outputFlag) {
+				colorOut = nil
+			}
+			colorEnabled, err := colorOutput(colorFlag, noColorFlag, colorOut)
+			if err != nil {
+				return err
+			}
+			if err := applyTheme(themeFlag); err != nil {
+				return err
+			}
+			if err := output.SetTreeStyle(asciiFlag, indentFlag); err != nil {
+				return fmt.Errorf("--indent: %v", err)
+			}
+
+			snap, err := loadSnapshot(cmd)
+			if err != nil {
+				return err
+			}
+			if snap != nil {
+				if err := checkSnapshotFlags(cmd); err != nil {
+					return err
+				}
+			}
+
+			// Quiet mode only reports through the exit status
+			if quietFlag {
+				if envFlag || preflightFlag || followFlag {
+					return fmt.Errorf("--quiet cannot be combined with --env, --preflight or --follow-children")
+				}
+				targets, err := targetsFromFlags(pidFlag, portFlag, socketFlag, args, stdinFlag)
+				if err != nil {
+					return err
+				}
+				os.Exit(quietStatus(snap, targets, opts))
+			}
+
+			// Several targets are explained concurrently, each failing on its
+			// own; targets in a snapshot are reported the same way
+			if len(args) > 1 || stdinFlag || snap != nil {
+				if envFlag || preflightFlag || followFlag {
+					return fmt.Errorf("--env, --preflight and --follow-children take a single target")
+				}
+				targets, err := targetsFromFlags(pidFlag, portFlag, socketFlag, args, stdinFlag)
+				if err != nil {
+					return err
+				}
+				prog, err := progressFromFlags(cmd)
+				if err != nil {
+					return err
+				}
+				var results []model.Result
+				var failed int
+				if snap != nil {
+					results, failed = snapshotTargets(snap, targets, opts)
+				} else {
+					results, failed = explainTargets(targets, opts, prog)
+				}
+				if err := renderResults(format, outputFlag, colorEnabled, page, results); err != nil {
+					return err
+				}
+				if failed > 0 {
+					cmd.SilenceUsage = true
+					return fmt.Errorf("%d of %s failed", failed, targetCount(len(results)))
+				}
+				return nil
+			}
+
+			var t model.Target
+			switch {
+			case pidFlag != "":
+				t = model.Target{Type: model.TargetPID, Value: pidFlag}
+			case portFlag != "":
+				t = model.Target{Type: model.TargetPort, Value: portFlag}
+			case socketFlag != "":
+				t = model.Target{Type: model.TargetSocket, Value: socketFlag}
+			case len(args) > 0:
+				t = parseTargetSpec(args[0])
+			default:
+				return fmt.Errorf("must specify --pid, --port, --socket, or a process name")
+			}
+
+			if preflightFlag {
+				return preflight(os.Stdout, t, auditFlag, verifyFlag, jsonFlag)
+			}
+
+			if envFlag {
+				pids, err := target.Resolve(t)
+				if amb := (*target.AmbiguousError)(nil); errors.As(err, &amb) {
+					amb.Report(os.Stdout)
+					os.Exit(1)
+				}
+				if err != nil {
+					return fmt.Errorf("error: %v", err)
+				}
+				if len(pids) > 1 {
+					fmt.Print("Multiple matching processes found:\n\n")
+					for i, pid := range pids {
+						cmdline := procpkg.GetCmdline(pid)
+						fmt.Printf("[%d] PID %d   %s\n", i+1, pid, cmdline)
+					}
+					fmt.Println("\nRe-run with:")
+					fmt.Println("  witr --pid <pid> --env")
+					return fmt.Errorf("multiple processes found")
+				}
+				pid := pids[0]
+				procInfo, err := procpkg.ReadProcess(pid)
+				if err != nil {
+					return fmt.Errorf("error: %v", err)
+				}
+				if !unsafeEnvFlag {
+					procInfo.Env = procpkg.RedactEnv(procInfo.Env)
+				}
+				sink, err := openOutput(outputFlag, page)
+				if err != nil {
+					return err
+				}
+				if jsonFlag {
+					type envOut struct {
+						Command string   `json:"Command"`
+						Env     []string `json:"Env"`
+					}
+					out := envOut{Command: procInfo.Cmdline, Env: procInfo.Env}
+					enc, _ := json.MarshalIndent(out, "", "  ")
+					fmt.Fprintln(sink, string(enc))
+				} else {
+					output.RenderEnvOnly(sink, procInfo, colorEnabled)
+				}
+				return sink.Close()
+			}
+
+			pids, err := target.Resolve(t)
+			if amb := (*target.AmbiguousError)(nil); errors.As(err, &amb) {
+				amb.Report(os.Stdout)
+				os.Exit(1)
+			}
+			if err != nil {
+				errStr := err.Error()
+				var errorMsg string
+				if errors.Is(err, target.ErrOwnerHidden) {
+					errorMsg = fmt.Sprintf("%s\n\nA socket was found for the target, but the owning process could not be detected.\nThis may be due to insufficient permissions. Try running with sudo:\n  sudo %s", errStr, strings.Join(os.Args, " "))
+				} else {
+					errorMsg = fmt.Sprintf("%s\n\nNo matching process or service found. Please check your query or try a different name/port/PID.", errStr)
+				}
+				return errors.New(errorMsg)
+			}
+
+			if len(pids) > 1 {
+				fmt.Print("Multiple matching processes found:\n\n")
+				for i, pid := range pids {
+					cmdline := procpkg.GetCmdline(pid)
+					fmt.Printf("[%d] PID %d   %s\n", i+1, pid, cmdline)
+				}
+				fmt.Println("\nRe-run with:")
+				fmt.Println("  witr --pid <pid>")
+				return fmt.Errorf("multiple processes found")
+			}
+
+			pid := pids[0]
+
+			ancestry, err := procpkg.ResolveAncestry(pid)
+			if err != nil {
+				fmt.Println()
+				fmt.Println("Error:")
+				fmt.Printf("  %s\n", err.Error())
+				fmt.Println("\nNo matching process or service found. Please check your query or try a different name/port/PID.")
+				fmt.Println("For usage and options, run: witr --help")
+				os.Exit(1)
+			}
+
+			res := explain(t, ancestry)
+			if err := opts.enrich(&res, pid); err != nil {
+				return err
+			}
+			if followFlag {
+				if format == output.FormatDOT || format == output.FormatHTML {
+					return fmt.Errorf("--dot and --html cannot be combined with --follow-children")
+				}
+				// Results stream as children appear, so JSON is one object per line
+				if format == output.FormatJSON {
+					format = output.FormatNDJSON
+				}
+				return followResults(format, outputFlag, colorEnabled, res, pid, unsafeEnvFlag)
+			}
+			if format == output.FormatJSON {
+				format = output.FormatJSONObject
+			}
+			return renderResults(format, outputFlag, colorEnabled, page, []model.Result{res})
+		},
+	}
+
+	rootCmd.Version = version
+	rootCmd.SetVersionTemplate(fmt.Sprintf("witr {{.Version}} (commit %s, built %s)\n", commit, buildDate))
+
+	rootCmd.Flags().String("pid", "", "pid to look up")
+	rootCmd.Flags().String("port", "", "port to look up")
+	rootCmd.Flags().String("socket", "", "unix socket path to look up (@name for abstract sockets)")
+	rootCmd.Flags().Bool("short", false, "short output")
+	rootCmd.Flags().Bool("tree", false, "tree output")
+	rootCmd.Flags().Bool("json", false, "output as JSON")
+	rootCmd.Flags().Bool("schema", false, "print the JSON Schema of --json results and exit")
+	rootCmd.Flags().Bool("ndjson", false, "output one compact JSON object per result, one per line")
+	rootCmd.Flags().String("format", "", "print each result with a Go template, e.g. '{{.Process.PID}} {{.Source.Type}}'")
+	rootCmd.Flags().Bool("dot", false, "output the ancestry (and --children) as a Graphviz DOT graph")
+	rootCmd.Flags().Bool("markdown", false, "output a Markdown report for issues and postmortems")
+	rootCmd.Flags().Bool("html", false, "output a self-contained HTML report (with --output file:<path> to save it)")
+	rootCmd.Flags().Bool("csv", false, "output one CSV row per process (pid, command, user, source, unit, container, warnings)")
+	rootCmd.Flags().Bool("tsv", false, "like --csv, separated by tabs")
+	rootCmd.Flags().Bool("warnings", false, "show only warnings")
+	rootCmd.Flags().Bool("verbose", false, "show everything: full command lines, environment summary, connections, open fds and triage")
+	rootCmd.Flags().Bool("wide", false, "same as --verbose")
+	rootCmd.Flags().Bool("no-pager", false, "do not page output taller than the terminal through $PAGER (less -R)")
+	rootCmd.Flags().Bool("quiet", false, "print nothing; exit 0 if found, 1 if not found, 2 if found with warnings, 3 on a permission problem")
+	rootCmd.Flags().String("color", "auto", "colorize output: auto (on a terminal, unless NO_COLOR is set), always or never")
+	rootCmd.Flags().Bool("no-color", false, "disable colorized output (same as --color never)")
+	rootCmd.Flags().Bool("ascii", false, "draw trees and chains with ASCII (+-, `-, |, ->) instead of box-drawing characters")
+	rootCmd.Flags().Int("indent", 2, "columns to indent each level of a tree (1 to 8)")
+	rootCmd.Flags().String("theme", "", "color theme: default, light, monochrome-bold or high-contrast (overrides the config file)")
+	rootCmd.Flags().String("output", "", "write the result to file:<path> (atomically), unix:<socket> or journald: instead of stdout")
+	rootCmd.Flags().Bool("env", false, "show only environment variables for the process (secret-looking values redacted)")
+	rootCmd.Flags().Bool("unsafe-env", false, "show full environment values, including secrets")
+	rootCmd.Flags().Bool("audit", false, "reconstruct the launch (argv, login user, tty, time) from auditd execve records")
+	rootCmd.Flags().Bool("connections", false, "list established connections grouped by remote host and port")
+	rootCmd.Flags().Bool("verify-signature", false, "verify a detached Sigstore signature shipped next to the executable (needs cosign)")
+	rootCmd.Flags().String("history", "", "prefer the origin witr daemon recorded at start time (json:<dir> or sqlite:<file>)")
+	rootCmd.Flags().Bool("stdin", false, "read more targets from stdin, one per line (pid:<n>, port:<n>, socket:<path> or a name)")
+	rootCmd.Flags().Bool("preflight", false, "report the files and tools the lookup needs and whether they are accessible, without running it")
+	rootCmd.Flags().Int("logs", 0, "append the last N journal lines of the systemd unit, or the container's logs")
+	rootCmd.Flags().Bool("fds", false, "summarize open file descriptors by type, with the largest open files and the fd limit")
+	rootCmd.Flags().Bool("children", false, "show the processes below the target (with --tree, as part of the tree)")
+	rootCmd.Flags().Int("depth", 0, "with --children, how many levels below the target to show (0 for all)")
+	rootCmd.Flags().Bool("follow-children", false, "keep running after the result and explain every child the process spawns until interrupted")
+	rootCmd.Flags().Bool("investigate", false, "include a triage report of raw evidence (tty, session, cgroup, environment hints, open fds)")
+
+	rootCmd.PersistentFlags().String("from-snapshot", "", "read processes from a snapshot written by witr dump instead of the live system")
+	addProgressFlag(rootCmd)
+
+	rootCmd.AddCommand(newScanCmd())
+	rootCmd.AddCommand(newCheckCmd())
+	rootCmd.AddCommand(newStaleCmd())
+	rootCmd.AddCommand(newFleetCmd())
+	rootCmd.AddCommand(newDaemonCmd())
+	rootCmd.AddCommand(newCompareCmd())
+	rootCmd.AddCommand(newDumpCmd())
+
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+// targetsFromFlags lists the targets of a run: the --pid, --port or
+// --socket flag, each argument and, with --stdin, each line of stdin
+func targetsFromFlags(pid, port, socket string, args []string, stdin bool) ([]model.Target, error) {
+	var targets []model.Target
+	switch {
+	case pid != "":
+		targets = append(targets, model.Target{Type: model.TargetPID, Value: pid})
+	case port != "":
+		targets = append(targets, model.Target{Type: model.TargetPort, Value: port})
+	case socket != "":
+		targets = append(targets, model.Target{Type: model.TargetSocket, Value: socket})
+	}
+	for _, a := range args {
+		targets = append(targets, parseTargetSpec(a))
+	}
+	if stdin {
+		more, err := readTargetSpecs(os.Stdin)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, more...)
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("no targets given")
+	}
+	return targets, nil
+}
+
+// redirected reports whether --output sends the result somewhere other
+// than the terminal, where color codes would only get in the way
+func redirected(uri string) bool {
+	return uri != "" && uri != "-"
+}
+
+// renderResults writes results in format to the --output sink, through
+// the pager when page is set
+func renderResults(format, uri string, colorEnabled, page bool, results []model.Result) error {
+	sink, err := openOutput(uri, page)
+	if err != nil {
+		return err
+	}
+	renderer, err := output.NewRenderer(format, sink, colorEnabled)
+	if err != nil {
+		sink.Close()
+		return err
+	}
+	if err := renderer.Begin(); err != nil {
+		sink.Close()
+		return err
+	}
+	for _, res := range results {
+		if err := renderer.Emit(res); err != nil {
+			sink.Close()
+			return err
+		}
+	}
+	if err := renderer.End(); err != nil {
+		sink.Close()
+		return err
+	}
+	return sink.Close()
+}
+
+// followResults writes the target's result and then one for each child it
+// spawns, until the target exits or witr is interrupted
+func followResults(format, uri string, colorEnabled bool, res model.Result, pid int, unsafeEnv bool) error {
+	sink, err := output.OpenSink(uri)
+	if err != nil {
+		return err
+	}
+	renderer, err := output.NewRenderer(format, sink, colorEnabled)
+	if err != nil {
+		sink.Close()
+		return err
+	}
+	err = renderer.Begin()
+	if err == nil {
+		err = renderer.Emit(res)
+	}
+	if err == nil {
+		err = followChildren(renderer, pid, unsafeEnv)
+	}
+	if err == nil {
+		err = renderer.End()
+	}
+	if err != nil {
+		sink.Close()
+		return err
+	}
+	return sink.Close()
+}
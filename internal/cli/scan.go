@@ -0,0 +1,172 @@
+//go:build linux || darwin
+
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/pranshuparmar/witr/internal/output"
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/internal/progress"
+	"github.com/pranshuparmar/witr/internal/store"
+	"github.com/pranshuparmar/witr/pkg/model"
+	"github.com/spf13/cobra"
+)
+
+func newScanCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "scan",
+		Short: "Explain every process with a listening socket",
+		Long: "scan explains every process that is listening on a socket.\n\n" +
+			"With --mine only the invoking user's processes are explained, so no extra\n" +
+			"privileges are needed. Their ancestors, and the process table used to\n" +
+			"walk the tree, still include other users' processes.\n\n" +
+			"--source and --not-source filter by source type, e.g. --not-source systemd,container\n" +
+			"lists what is running outside any service manager.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mineFlag, _ := cmd.Flags().GetBool("mine")
+			jsonFlag, _ := cmd.Flags().GetBool("json")
+			ndjsonFlag, _ := cmd.Flags().GetBool("ndjson")
+			formatFlag, _ := cmd.Flags().GetString("format")
+			csvFlag, _ := cmd.Flags().GetBool("csv")
+			tsvFlag, _ := cmd.Flags().GetBool("tsv")
+			shortFlag, _ := cmd.Flags().GetBool("short")
+			noColorFlag, _ := cmd.Flags().GetBool("no-color")
+			colorFlag, _ := cmd.Flags().GetString("color")
+			themeFlag, _ := cmd.Flags().GetString("theme")
+			asciiFlag, _ := cmd.Flags().GetBool("ascii")
+			indentFlag, _ := cmd.Flags().GetInt("indent")
+
+			filter, err := sourceFilterFromFlags(cmd)
+			if err != nil {
+				return err
+			}
+			uid := -1
+			if mineFlag {
+				uid = os.Getuid()
+			}
+
+			format := output.FormatStandard
+			switch {
+			case formatFlag != "":
+				if _, err := output.ParseTemplate(formatFlag); err != nil {
+					return fmt.Errorf("--format: %v", err)
+				}
+				format = output.TemplateFormat(formatFlag)
+			case ndjsonFlag:
+				format = output.FormatNDJSON
+			case csvFlag:
+				format = output.FormatCSV
+			case tsvFlag:
+				format = output.FormatTSV
+			case jsonFlag:
+				format = output.FormatJSON
+			case shortFlag:
+				format = output.FormatShort
+			}
+			colorEnabled, err := colorOutput(colorFlag, noColorFlag, os.Stdout)
+			if err != nil {
+				return err
+			}
+			if err := applyTheme(themeFlag); err != nil {
+				return err
+			}
+			if err := output.SetTreeStyle(asciiFlag, indentFlag); err != nil {
+				return fmt.Errorf("--indent: %v", err)
+			}
+			renderer, err := output.NewRenderer(format, os.Stdout, colorEnabled)
+			if err != nil {
+				return err
+			}
+			if err := renderer.Begin(); err != nil {
+				return err
+			}
+
+			prog, err := progressFromFlags(cmd)
+			if err != nil {
+				return err
+			}
+			snap, err := loadSnapshot(cmd)
+			if err != nil {
+				return err
+			}
+			if err := scanResults(snap, uid, prog, filter.wrap(renderer.Emit)); err != nil {
+				return err
+			}
+			return renderer.End()
+		},
+	}
+
+	cmd.Flags().Bool("mine", false, "only scan processes owned by the invoking user")
+	cmd.Flags().Bool("short", false, "short output")
+	cmd.Flags().Bool("json", false, "output as JSON")
+	cmd.Flags().Bool("ndjson", false, "output one compact JSON object per process, one per line")
+	cmd.Flags().String("format", "", "print each process with a Go template, e.g. '{{.Process.PID}} {{.Source.Type}}'")
+	cmd.Flags().Bool("csv", false, "output one CSV row per process (pid, command, user, source, unit, container, warnings)")
+	cmd.Flags().Bool("tsv", false, "like --csv, separated by tabs")
+	cmd.Flags().String("color", "auto", "colorize output: auto (on a terminal, unless NO_COLOR is set), always or never")
+	cmd.Flags().Bool("no-color", false, "disable colorized output (same as --color never)")
+	cmd.Flags().Bool("ascii", false, "draw trees and chains with ASCII (+-, `-, |, ->) instead of box-drawing characters")
+	cmd.Flags().Int("indent", 2, "columns to indent each level of a tree (1 to 8)")
+	cmd.Flags().String("theme", "", "color theme: default, light, monochrome-bold or high-contrast (overrides the config file)")
+	addSourceFilterFlags(cmd)
+	addProgressFlag(cmd)
+	return cmd
+}
+
+// scanResults explains every process with a listening socket, calling fn for
+// each result in PID order. A uid other than -1 limits the scan to that
+// user's processes. Processes are read concurrently, reporting to prog, or
+// taken from snap when it is not nil.
+func scanResults(snap *store.Snapshot, uid int, prog *progress.Reporter, fn func(model.Result) error) error {
+	if snap != nil {
+		for _, res := range snap.Results {
+			if len(res.Process.ListeningPorts) == 0 || !snapshotOwned(res, uid) {
+				continue
+			}
+			if err := fn(res); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	pids, err := procpkg.ListPIDs(uid)
+	if err != nil {
+		return err
+	}
+	defer procpkg.CacheTable()()
+	self := os.Getpid()
+	prog.Start("scan", len(pids))
+	results := parallelMap(pids, func(pid int) *model.Result {
+		defer prog.Step("pid " + strconv.Itoa(pid))
+		if pid == self {
+			return nil
+		}
+		// Processes we may not read (or that exited) are skipped
+		p, err := procpkg.ReadProcess(pid)
+		if err != nil || len(p.ListeningPorts) == 0 {
+			return nil
+		}
+		ancestry, err := procpkg.ResolveAncestry(pid)
+		if err != nil {
+			return nil
+		}
+		t := model.Target{Type: model.TargetPID, Value: strconv.Itoa(pid)}
+		res := explain(t, ancestry)
+		redactResult(&res)
+		return &res
+	})
+	prog.Finish()
+	for _, res := range results {
+		if res == nil {
+			continue
+		}
+		if err := fn(*res); err != nil {
+			return err
+		}
+	}
+	return nil
+}
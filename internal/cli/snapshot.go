@@ -0,0 +1,167 @@
+//go:build linux || darwin
+
+package cli
+
+import (
+	"fmt"
+	osuser "os/user"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/internal/store"
+	"github.com/pranshuparmar/witr/pkg/model"
+	"github.com/spf13/cobra"
+)
+
+// loadSnapshot reads the --from-snapshot file, returning nil when the flag
+// is not set
+func loadSnapshot(cmd *cobra.Command) (*store.Snapshot, error) {
+	path, _ := cmd.Flags().GetString("from-snapshot")
+	if path == "" {
+		return nil, nil
+	}
+	snap, err := store.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("--from-snapshot: %w", err)
+	}
+	return snap, nil
+}
+
+// snapshotTarget returns the result a target resolves to in a snapshot,
+// matching the way a live lookup would. Failures are returned as a result
+// carrying the error, as with explainTarget.
+func snapshotTarget(snap *store.Snapshot, t model.Target, o enrichOptions) model.Result {
+	matches := snapshotMatches(snap, t)
+	switch len(matches) {
+	case 0:
+		return model.Result{Target: t, Error: fmt.Sprintf("no process in the snapshot of %s matches", snap.Host)}
+	case 1:
+	default:
+		list := make([]string, len(matches))
+		for i, r := range matches {
+			list[i] = strconv.Itoa(r.Process.PID)
+		}
+		return model.Result{Target: t, Error: fmt.Sprintf("matches %d processes (pids %s); use pid:<pid>", len(matches), strings.Join(list, ", "))}
+	}
+	res := matches[0]
+	res.Target = t
+	if o.children {
+		res.Children = snapshotChildren(snap, res.Process.PID, o.depth)
+	}
+	return res
+}
+
+// snapshotTargets returns the results for targets from a snapshot, in
+// target order, and the number that failed
+func snapshotTargets(snap *store.Snapshot, targets []model.Target, o enrichOptions) ([]model.Result, int) {
+	results := make([]model.Result, len(targets))
+	failed := 0
+	for i, t := range targets {
+		if results[i] = snapshotTarget(snap, t, o); results[i].Error != "" {
+			failed++
+		}
+	}
+	return results, failed
+}
+
+// snapshotMatches returns the results in a snapshot that a target selects:
+// by PID, listening port or socket path, or for a name by service unit,
+// command or command line (case-insensitive substrings, as live)
+func snapshotMatches(snap *store.Snapshot, t model.Target) []model.Result {
+	var matches []model.Result
+	switch t.Type {
+	case model.TargetPID, model.TargetPort:
+		n, err := strconv.Atoi(t.Value)
+		if err != nil {
+			return nil
+		}
+		for _, r := range snap.Results {
+			if (t.Type == model.TargetPID && r.Process.PID == n) || (t.Type == model.TargetPort && slices.Contains(r.Process.ListeningPorts, n)) {
+				matches = append(matches, r)
+			}
+		}
+	case model.TargetSocket:
+		for _, r := range snap.Results {
+			if slices.ContainsFunc(r.Process.Sockets, func(s model.ListenSocket) bool { return s.Path == t.Value }) {
+				matches = append(matches, r)
+			}
+		}
+	default:
+		name := strings.ToLower(t.Value)
+		for _, r := range snap.Results {
+			if strings.TrimSuffix(r.Process.Service, ".service") == t.Value {
+				return []model.Result{r}
+			}
+		}
+		for _, r := range snap.Results {
+			cmdline := strings.ToLower(r.Process.Cmdline)
+			if strings.Contains(strings.ToLower(r.Process.Command), name) ||
+				(strings.Contains(cmdline, name) && !strings.Contains(cmdline, "witr")) {
+				matches = append(matches, r)
+			}
+		}
+	}
+	return matches
+}
+
+// snapshotChildren rebuilds the subtree below pid from the parent links of
+// the processes in a snapshot, like proc.ChildTree does live
+func snapshotChildren(snap *store.Snapshot, pid, depth int) []model.ChildProcess {
+	children := make(map[int][]model.Process)
+	for _, r := range snap.Results {
+		if p := r.Process; p.PID != p.PPID {
+			children[p.PPID] = append(children[p.PPID], p)
+		}
+	}
+	var walk func(pid, level int) []model.ChildProcess
+	walk = func(pid, level int) []model.ChildProcess {
+		if depth > 0 && level > depth {
+			return nil
+		}
+		kids := children[pid]
+		sort.Slice(kids, func(i, j int) bool { return kids[i].PID < kids[j].PID })
+		var nodes []model.ChildProcess
+		for _, p := range kids {
+			nodes = append(nodes, model.ChildProcess{PID: p.PID, Command: p.Command, Cmdline: p.Cmdline, Children: walk(p.PID, level+1)})
+		}
+		return nodes
+	}
+	return walk(pid, 1)
+}
+
+// snapshotOwned reports whether a snapshot result belongs to uid, or to
+// anyone when uid is -1
+func snapshotOwned(r model.Result, uid int) bool {
+	if uid < 0 {
+		return true
+	}
+	if id := r.Process.Identity; id != nil {
+		return id.RealUID == uid
+	}
+	u, err := osuser.LookupId(strconv.Itoa(uid))
+	return err == nil && r.Process.User == u.Username
+}
+
+// rejectSnapshot fails commands that need the live system when
+// --from-snapshot is given
+func rejectSnapshot(cmd *cobra.Command, _ []string) error {
+	if path, _ := cmd.Flags().GetString("from-snapshot"); path != "" {
+		return fmt.Errorf("%s inspects the live system and cannot read --from-snapshot", cmd.Name())
+	}
+	return nil
+}
+
+// liveOnlyFlags are the root flags that read more than a snapshot holds
+var liveOnlyFlags = []string{"env", "preflight", "follow-children", "audit", "connections", "verify-signature", "investigate", "history", "logs", "fds"}
+
+// checkSnapshotFlags rejects root flags a snapshot cannot answer
+func checkSnapshotFlags(cmd *cobra.Command) error {
+	for _, name := range liveOnlyFlags {
+		if cmd.Flags().Changed(name) {
+			return fmt.Errorf("--%s needs the live system and cannot be used with --from-snapshot", name)
+		}
+	}
+	return nil
+}
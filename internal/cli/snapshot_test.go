@@ -0,0 +1,46 @@
+//go:build linux || darwin
+
+package cli
+
+import (
+	"testing"
+
+	"github.com/pranshuparmar/witr/internal/store"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestSnapshotMatches(t *testing.T) {
+	snap := &store.Snapshot{Results: []model.Result{
+		{Process: model.Process{PID: 1, Command: "systemd", Cmdline: "/sbin/init"}},
+		{Process: model.Process{PID: 10, PPID: 1, Command: "nginx", Cmdline: "nginx: master process", Service: "nginx.service", ListeningPorts: []int{80}}},
+		{Process: model.Process{PID: 11, PPID: 10, Command: "nginx", Cmdline: "nginx: worker process"}},
+		{Process: model.Process{PID: 20, PPID: 1, Command: "app", Sockets: []model.ListenSocket{{Protocol: "unix", Path: "/run/app.sock"}}}},
+	}}
+	tests := []struct {
+		target model.Target
+		pids   []int
+	}{
+		{model.Target{Type: model.TargetPID, Value: "11"}, []int{11}},
+		{model.Target{Type: model.TargetPort, Value: "80"}, []int{10}},
+		{model.Target{Type: model.TargetSocket, Value: "/run/app.sock"}, []int{20}},
+		// A service name picks its main process over matching commands
+		{model.Target{Type: model.TargetName, Value: "nginx"}, []int{10}},
+		{model.Target{Type: model.TargetName, Value: "WORKER"}, []int{11}},
+		{model.Target{Type: model.TargetPort, Value: "443"}, nil},
+	}
+	for _, tt := range tests {
+		matches := snapshotMatches(snap, tt.target)
+		var pids []int
+		for _, r := range matches {
+			pids = append(pids, r.Process.PID)
+		}
+		if len(pids) != len(tt.pids) || (len(pids) > 0 && pids[0] != tt.pids[0]) {
+			t.Errorf("snapshotMatches(%s %s) = %v, want %v", tt.target.Type, tt.target.Value, pids, tt.pids)
+		}
+	}
+
+	children := snapshotChildren(snap, 1, 1)
+	if len(children) != 2 || children[0].PID != 10 || children[0].Children != nil {
+		t.Errorf("snapshotChildren(1, depth 1) = %+v", children)
+	}
+}
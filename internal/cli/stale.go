@@ -0,0 +1,259 @@
+//go:build linux || darwin
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/internal/source"
+	"github.com/pranshuparmar/witr/internal/store"
+	"github.com/pranshuparmar/witr/pkg/model"
+	"github.com/spf13/cobra"
+)
+
+// staleProcess is one forgotten process reported by stale
+type staleProcess struct {
+	User      string    `json:"user"`
+	Origin    string    `json:"origin"`
+	PID       int       `json:"pid"`
+	Command   string    `json:"command"`
+	StartedAt time.Time `json:"started_at"`
+	Age       string    `json:"age"`
+}
+
+// Session hosts are not workloads themselves
+var staleSkip = map[string]bool{
+	"bash": true, "zsh": true, "sh": true, "fish": true, "-bash": true, "-zsh": true,
+	"tmux": true, "tmux: server": true, "screen": true, "SCREEN": true, "sshd": true, "sshd-session": true,
+}
+
+func newStaleCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stale",
+		Short: "List old interactively-launched processes",
+		Long: "stale lists processes started by hand (from a shell, tmux or screen, an SSH\n" +
+			"session, or with nohup) that are older than --older-than, grouped by user\n" +
+			"and origin, so forgotten workloads can be found and reclaimed.\n\n" +
+			"Durations accept d (days) and w (weeks) as well as Go units, e.g. 30d or 12h.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mineFlag, _ := cmd.Flags().GetBool("mine")
+			jsonFlag, _ := cmd.Flags().GetBool("json")
+			olderFlag, _ := cmd.Flags().GetString("older-than")
+
+			age, err := parseAge(olderFlag)
+			if err != nil {
+				return err
+			}
+			uid := -1
+			if mineFlag {
+				uid = os.Getuid()
+			}
+
+			snap, err := loadSnapshot(cmd)
+			if err != nil {
+				return err
+			}
+			var stale []staleProcess
+			if snap != nil {
+				stale = snapshotStale(snap, uid, age)
+			} else {
+				stale, err = staleProcesses(uid, time.Now().Add(-age))
+			}
+			if err != nil {
+				return err
+			}
+
+			if jsonFlag {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(stale)
+			}
+			if len(stale) == 0 {
+				fmt.Printf("No interactively-launched processes older than %s\n", olderFlag)
+				return nil
+			}
+			user, origin := "", ""
+			for _, s := range stale {
+				if s.User != user {
+					if user != "" {
+						fmt.Println()
+					}
+					user, origin = s.User, ""
+					fmt.Println(user)
+				}
+				if s.Origin != origin {
+					origin = s.Origin
+					fmt.Printf("  %s (%d)\n", origin, countOrigin(stale, user, origin))
+				}
+				fmt.Printf("    pid %-7d %-5s %s\n", s.PID, s.Age, s.Command)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String("older-than", "30d", "report processes started longer ago than this")
+	cmd.Flags().Bool("mine", false, "only list processes owned by the invoking user")
+	cmd.Flags().Bool("json", false, "output as JSON")
+	return cmd
+}
+
+// staleProcesses returns interactively-launched processes started before
+// cutoff, sorted by user, origin and age
+func staleProcesses(uid int, cutoff time.Time) ([]staleProcess, error) {
+	pids, err := procpkg.ListPIDs(uid)
+	if err != nil {
+		return nil, err
+	}
+	defer procpkg.CacheTable()()
+	starts := procpkg.StartTimes()
+	self := os.Getpid()
+
+	stale := []staleProcess{}
+	for _, pid := range pids {
+		started, ok := starts[pid]
+		if !ok || pid == self || !started.Before(cutoff) {
+			continue
+		}
+		ancestry, err := procpkg.ResolveAncestry(pid)
+		if err != nil {
+			continue
+		}
+		p := ancestry[len(ancestry)-1]
+		if staleSkip[p.Command] || p.Cmdline == "" {
+			continue
+		}
+		origin := staleOrigin(source.Detect(ancestry), func() bool { return procpkg.IgnoresHangup(pid) })
+		if origin == "" {
+			continue
+		}
+		stale = append(stale, staleProcess{
+			User:      p.User,
+			Origin:    origin,
+			PID:       pid,
+			Command:   p.Cmdline,
+			StartedAt: started,
+			Age:       formatAge(time.Since(started)),
+		})
+	}
+
+	sortStale(stale)
+	return stale, nil
+}
+
+// snapshotStale is staleProcesses for a snapshot, with ages as of when it
+// was taken. Whether a process ignored SIGHUP is not recorded, so nohup'd
+// processes are only reported while their shell is still their parent.
+func snapshotStale(snap *store.Snapshot, uid int, age time.Duration) []staleProcess {
+	cutoff := snap.TakenAt.Add(-age)
+	stale := []staleProcess{}
+	for _, r := range snap.Results {
+		p := r.Process
+		if p.StartedAt.IsZero() || !p.StartedAt.Before(cutoff) || !snapshotOwned(r, uid) {
+			continue
+		}
+		if staleSkip[p.Command] || p.Cmdline == "" {
+			continue
+		}
+		origin := staleOrigin(r.Source, func() bool { return false })
+		if origin == "" {
+			continue
+		}
+		stale = append(stale, staleProcess{
+			User:      p.User,
+			Origin:    origin,
+			PID:       p.PID,
+			Command:   p.Cmdline,
+			StartedAt: p.StartedAt,
+			Age:       formatAge(snap.TakenAt.Sub(p.StartedAt)),
+		})
+	}
+	sortStale(stale)
+	return stale
+}
+
+// sortStale orders stale processes by user, origin and age
+func sortStale(stale []staleProcess) {
+	sort.SliceStable(stale, func(i, j int) bool {
+		a, b := stale[i], stale[j]
+		if a.User != b.User {
+			return a.User < b.User
+		}
+		if a.Origin != b.Origin {
+			return a.Origin < b.Origin
+		}
+		return a.StartedAt.Before(b.StartedAt)
+	})
+}
+
+// staleOrigin names how an interactively-launched process was started, or
+// returns "" for processes a service manager or runtime owns. ignoresHangup
+// reports whether the process ignores SIGHUP, as nohup'd processes do.
+func staleOrigin(src model.Source, ignoresHangup func() bool) string {
+	switch {
+	case src.Type == model.SourceShell && (src.Name == "tmux" || src.Name == "screen"):
+		return src.Name
+	case src.Type == model.SourceSSH:
+		if remote := src.Details["remote"]; remote != "" {
+			return "ssh session from " + remote
+		}
+		return "ssh session"
+	case src.Type == model.SourceShell:
+		if ignoresHangup() {
+			return "nohup"
+		}
+		return "shell (" + src.Name + ")"
+	case src.Type == model.SourceUnknown && ignoresHangup():
+		// nohup'd processes outlive their shell and are re-parented
+		return "nohup"
+	}
+	return ""
+}
+
+func countOrigin(stale []staleProcess, user, origin string) int {
+	n := 0
+	for _, s := range stale {
+		if s.User == user && s.Origin == origin {
+			n++
+		}
+	}
+	return n
+}
+
+// parseAge parses a duration, accepting d (days) and w (weeks) suffixes in
+// addition to the units time.ParseDuration knows
+func parseAge(s string) (time.Duration, error) {
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			v, err := strconv.ParseFloat(n, 64)
+			if err != nil || v < 0 {
+				return 0, fmt.Errorf("invalid duration %q", s)
+			}
+			return time.Duration(v * float64(unit)), nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return d, nil
+}
+
+// formatAge renders an age in its largest whole unit (45d, 3h, 12m)
+func formatAge(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour:
+		return strconv.Itoa(int(d.Hours()/24)) + "d"
+	case d >= time.Hour:
+		return strconv.Itoa(int(d.Hours())) + "h"
+	default:
+		return strconv.Itoa(int(d.Minutes())) + "m"
+	}
+}
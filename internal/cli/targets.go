@@ -0,0 +1,165 @@
+//go:build linux || darwin
+
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/internal/audit"
+	procpkg "github.com/pranshuparmar/witr/internal/proc"
+	"github.com/pranshuparmar/witr/internal/progress"
+	"github.com/pranshuparmar/witr/internal/source"
+	"github.com/pranshuparmar/witr/internal/target"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// enrichOptions selects the optional sections added to a result
+type enrichOptions struct {
+	history     string
+	audit       bool
+	connections bool
+	investigate bool
+	verify      bool
+	unsafeEnv   bool
+	// logs adds the last logs lines of the unit or container
+	logs int
+	// children adds the subtree below the process, down to depth levels
+	children bool
+	depth    int
+	// fds adds the open file descriptor summary
+	fds bool
+}
+
+// enrich adds the sampled CPU usage and the sections selected by o to a
+// freshly explained result
+func (o enrichOptions) enrich(res *model.Result, pid int) error {
+	res.Process.CPUPercent = procpkg.SampleCPU(pid, procpkg.CPUSampleInterval)
+	res.Ancestry[len(res.Ancestry)-1].CPUPercent = res.Process.CPUPercent
+	sampled(res, model.SampledCPU)
+	if o.history != "" {
+		if err := applyHistory(res, o.history); err != nil {
+			return err
+		}
+	}
+	if o.audit {
+		res.Launch = audit.FindLaunch(pid, res.Process.StartedAt)
+		sampled(res, model.SampledLaunch)
+	}
+	if o.connections {
+		res.Connections = procpkg.GetConnections(pid)
+		sampled(res, model.SampledConnections)
+	}
+	if o.logs > 0 {
+		res.Logs = source.RecentLogs(res.Source, res.Process, o.logs)
+		sampled(res, model.SampledLogs)
+	}
+	if o.fds {
+		if res.FDs = procpkg.FDSummary(pid); res.FDs != nil {
+			sampled(res, model.SampledFDs)
+		}
+	}
+	if o.children {
+		res.Children = procpkg.ChildTree(pid, o.depth)
+		sampled(res, model.SampledChildren)
+	}
+	if o.investigate {
+		res.Triage = procpkg.Investigate(res.Process)
+		sampled(res, model.SampledTriage)
+	}
+	if o.verify {
+		res.Integrity = procpkg.VerifySignature(res.Integrity, res.Process.Exe)
+		sampled(res, model.SampledIntegrity)
+		if in := res.Integrity; in != nil && in.Signature != "" && !in.SignatureOK {
+			res.AddWarnings(model.Warning{Code: model.WarnSignatureUnverified, Severity: model.SeverityHigh,
+				Message: "Executable signature could not be verified: " + in.Signature})
+		}
+	}
+	if !o.unsafeEnv {
+		redactResult(res)
+	}
+	return nil
+}
+
+// parseTargetSpec parses a target given as pid:<n>, port:<n>, socket:<path>
+// or name:<name>; anything else is a process or service name
+func parseTargetSpec(spec string) model.Target {
+	for _, tt := range []model.TargetType{model.TargetPID, model.TargetPort, model.TargetSocket, model.TargetName} {
+		if v, ok := strings.CutPrefix(spec, string(tt)+":"); ok && v != "" {
+			return model.Target{Type: tt, Value: v}
+		}
+	}
+	return model.Target{Type: model.TargetName, Value: spec}
+}
+
+// readTargetSpecs reads one target per line, skipping blanks and # comments
+func readTargetSpecs(r io.Reader) ([]model.Target, error) {
+	var targets []model.Target
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		targets = append(targets, parseTargetSpec(line))
+	}
+	return targets, scanner.Err()
+}
+
+// explainTarget explains the process a target resolves to. Failures are
+// returned as a result carrying only the target and the error, so a missing
+// target or a permission problem does not affect the others.
+func explainTarget(t model.Target, o enrichOptions) model.Result {
+	failed := func(err error) model.Result {
+		return model.Result{Target: t, Error: err.Error()}
+	}
+	pids, err := target.Resolve(t)
+	if err != nil {
+		return failed(err)
+	}
+	if len(pids) > 1 {
+		list := make([]string, len(pids))
+		for i, pid := range pids {
+			list[i] = strconv.Itoa(pid)
+		}
+		return failed(fmt.Errorf("matches %d processes (pids %s); use pid:<pid>", len(pids), strings.Join(list, ", ")))
+	}
+	ancestry, err := procpkg.ResolveAncestry(pids[0])
+	if err != nil {
+		return failed(err)
+	}
+	res := explain(t, ancestry)
+	if err := o.enrich(&res, pids[0]); err != nil {
+		return failed(err)
+	}
+	return res
+}
+
+// explainTargets explains targets concurrently, reporting to prog, and returns
+// the results in target order and the number that failed
+func explainTargets(targets []model.Target, o enrichOptions, prog *progress.Reporter) ([]model.Result, int) {
+	prog.Start("explain", len(targets))
+	results := parallelMap(targets, func(t model.Target) model.Result {
+		defer prog.Step(string(t.Type) + ":" + t.Value)
+		return explainTarget(t, o)
+	})
+	prog.Finish()
+	failed := 0
+	for _, r := range results {
+		if r.Error != "" {
+			failed++
+		}
+	}
+	return results, failed
+}
+
+// targetCount describes a number of results for the failure summary
+func targetCount(n int) string {
+	if n == 1 {
+		return "1 target"
+	}
+	return strconv.Itoa(n) + " targets"
+}
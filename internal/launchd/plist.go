@@ -25,6 +25,7 @@ type LaunchdInfo struct {
 	StartCalendarInterval string // human-readable schedule
 	WatchPaths            []string
 	QueueDirectories      []string
+	SocketPaths           []string // SockPathName entries under Sockets
 
 	// Program info
 	Program          string
@@ -146,6 +147,33 @@ func FindPlistPath(label string) string {
 	return ""
 }
 
+// FindSocketJob returns the plist of the launchd job that declares a
+// listener on the given Unix socket path
+func FindSocketJob(socketPath string) (*LaunchdInfo, error) {
+	homeDir, _ := os.UserHomeDir()
+
+	for _, searchPath := range plistSearchPaths {
+		dir := searchPath
+		if strings.HasPrefix(dir, "~") {
+			dir = filepath.Join(homeDir, dir[1:])
+		}
+		matches, _ := filepath.Glob(filepath.Join(dir, "*.plist"))
+		for _, plistPath := range matches {
+			info, err := ParsePlist(plistPath)
+			if err != nil {
+				continue
+			}
+			for _, p := range info.SocketPaths {
+				if p == socketPath {
+					return info, nil
+				}
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("no launchd job declares socket %s", socketPath)
+}
+
 // ParsePlist reads and parses a launchd plist file
 func ParsePlist(path string) (*LaunchdInfo, error) {
 	// Use plutil to convert to XML (handles binary plists)
@@ -171,6 +199,7 @@ func parsePlistXML(data []byte, info *LaunchdInfo) error {
 	decoder := xml.NewDecoder(strings.NewReader(string(data)))
 
 	var currentKey string
+	var nestedKey string
 	var dictDepth int // Track dict nesting depth (1 = root dict)
 
 	for {
@@ -190,12 +219,21 @@ func parsePlistXML(data []byte, info *LaunchdInfo) error {
 				}
 			case "key":
 				// Only capture keys at root dict level
+				var key string
+				decoder.DecodeElement(&key, &t)
 				if dictDepth == 1 {
-					var key string
-					decoder.DecodeElement(&key, &t)
 					currentKey = key
+				} else {
+					nestedKey = key
 				}
 			case "string":
+				// Socket listener paths live in dicts nested under Sockets
+				if dictDepth > 1 && nestedKey == "SockPathName" {
+					var val string
+					decoder.DecodeElement(&val, &t)
+					info.SocketPaths = append(info.SocketPaths, val)
+					nestedKey = ""
+				}
 				if dictDepth == 1 && currentKey != "" {
 					var val string
 					decoder.DecodeElement(&val, &t)
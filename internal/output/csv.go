@@ -0,0 +1,50 @@
+package output
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// csvHeader names the columns csvRenderer writes
+var csvHeader = []string{"pid", "command", "user", "source", "unit", "container", "warnings", "error"}
+
+// csvRenderer writes one row per result with a header row, for importing
+// into a spreadsheet. Warnings share a cell, separated by "; ".
+type csvRenderer struct {
+	w *csv.Writer
+}
+
+func newCSVRenderer(w io.Writer, comma rune) *csvRenderer {
+	cw := csv.NewWriter(w)
+	cw.Comma = comma
+	return &csvRenderer{w: cw}
+}
+
+func (c *csvRenderer) Begin() error {
+	return c.w.Write(csvHeader)
+}
+
+func (c *csvRenderer) Emit(r model.Result) error {
+	var row []string
+	if r.Error != "" {
+		row = []string{"", string(r.Target.Type) + " " + r.Target.Value, "", "", "", "", "", r.Error}
+	} else {
+		p := r.Ancestry[len(r.Ancestry)-1]
+		row = []string{strconv.Itoa(p.PID), p.Command, p.User, string(r.Source.Type), p.Service, p.Container, strings.Join(r.Warnings, "; "), ""}
+	}
+	if err := c.w.Write(row); err != nil {
+		return err
+	}
+	// Flush per row so long scans show progress when piped
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvRenderer) End() error {
+	c.w.Flush()
+	return c.w.Error()
+}
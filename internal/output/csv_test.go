@@ -0,0 +1,27 @@
+package output
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestCSVRenderer(t *testing.T) {
+	var b strings.Builder
+	r, _ := NewRenderer(FormatCSV, &b, false)
+	r.Begin()
+	r.Emit(model.Result{
+		Ancestry: []model.Process{{PID: 1}, {PID: 42, Command: "nginx", User: "www-data", Service: "nginx.service"}},
+		Source:   model.Source{Type: model.SourceSystemd},
+		Warnings: []string{"Process is listening on a public interface", `Binary "nginx" was deleted, restart needed`},
+	})
+	r.Emit(model.Result{Target: model.Target{Type: model.TargetPort, Value: "8080"}, Error: "no process listening on port 8080"})
+	r.End()
+	want := "pid,command,user,source,unit,container,warnings,error\n" +
+		`42,nginx,www-data,systemd,nginx.service,,"Process is listening on a public interface; Binary ""nginx"" was deleted, restart needed",` + "\n" +
+		",port 8080,,,,,,no process listening on port 8080\n"
+	if b.String() != want {
+		t.Errorf("output =\n%s\nwant\n%s", b.String(), want)
+	}
+}
@@ -0,0 +1,132 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// dotRenderer collects results into one Graphviz digraph, written at End.
+// Processes shared by several ancestries (init, a supervisor) appear once;
+// targets are drawn bold and descendants dashed.
+type dotRenderer struct {
+	w     io.Writer
+	ids   []string
+	nodes map[string]*dotNode
+	edges []string
+	seen  map[string]bool
+}
+
+type dotNode struct {
+	label string
+	attrs string
+}
+
+func (d *dotRenderer) Begin() error {
+	d.nodes = make(map[string]*dotNode)
+	d.seen = make(map[string]bool)
+	return nil
+}
+
+func (d *dotRenderer) Emit(r model.Result) error {
+	if r.Error != "" {
+		return nil
+	}
+	prev := ""
+	for i, p := range r.Ancestry {
+		id := "p" + strconv.Itoa(p.PID)
+		d.node(id, processLabel(p), "")
+		if i == len(r.Ancestry)-1 {
+			d.nodes[id].attrs = "style=bold"
+		}
+		if prev != "" {
+			d.edge(prev, id)
+		}
+		prev = id
+		if p.SkippedAncestors > 0 {
+			skip := id + "_skipped"
+			d.node(skip, skippedLabel(p.SkippedAncestors), "shape=plaintext")
+			d.edge(id, skip)
+			prev = skip
+		}
+	}
+	var walk func(parent string, children []model.ChildProcess)
+	walk = func(parent string, children []model.ChildProcess) {
+		for _, c := range children {
+			id := "p" + strconv.Itoa(c.PID)
+			d.node(id, fmt.Sprintf("%s\npid %d", c.Command, c.PID), "style=dashed")
+			d.edge(parent, id)
+			walk(id, c.Children)
+		}
+	}
+	if prev != "" {
+		walk(prev, r.Children)
+	}
+	return nil
+}
+
+func (d *dotRenderer) End() error {
+	var b strings.Builder
+	b.WriteString("digraph witr {\n")
+	b.WriteString("  rankdir=TB;\n")
+	b.WriteString("  node [shape=box, fontname=\"monospace\"];\n")
+	for _, id := range d.ids {
+		n := d.nodes[id]
+		b.WriteString("  " + id + " [label=" + dotQuote(n.label))
+		if n.attrs != "" {
+			b.WriteString(", " + n.attrs)
+		}
+		b.WriteString("];\n")
+	}
+	for _, e := range d.edges {
+		b.WriteString("  " + e + ";\n")
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(d.w, b.String())
+	return err
+}
+
+// node adds a node the first time its id is seen
+func (d *dotRenderer) node(id, label, attrs string) {
+	if d.nodes[id] != nil {
+		return
+	}
+	d.ids = append(d.ids, id)
+	d.nodes[id] = &dotNode{label: label, attrs: attrs}
+}
+
+// edge adds an edge from parent to child once
+func (d *dotRenderer) edge(from, to string) {
+	e := from + " -> " + to
+	if d.seen[e] {
+		return
+	}
+	d.seen[e] = true
+	d.edges = append(d.edges, e)
+}
+
+// processLabel names a process with its PID, user, unit and container
+func processLabel(p model.Process) string {
+	lines := []string{p.Command, "pid " + strconv.Itoa(p.PID)}
+	if p.User != "" {
+		lines = append(lines, "user: "+p.User)
+	}
+	if p.Service != "" {
+		lines = append(lines, "unit: "+p.Service)
+	}
+	if p.Container != "" {
+		lines = append(lines, "container: "+p.Container)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// dotQuote quotes s as a DOT string, keeping newlines as line breaks
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
@@ -0,0 +1,36 @@
+package output
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestDOTRendererMergesAncestries(t *testing.T) {
+	var b strings.Builder
+	r, _ := NewRenderer(FormatDOT, &b, false)
+	r.Begin()
+	initProc := model.Process{PID: 1, Command: "systemd", User: "root"}
+	r.Emit(model.Result{
+		Ancestry: []model.Process{initProc, {PID: 10, Command: "nginx", User: "www-data", Service: "nginx.service"}},
+		Children: []model.ChildProcess{{PID: 11, Command: "nginx"}},
+	})
+	r.Emit(model.Result{Ancestry: []model.Process{initProc, {PID: 20, Command: `say "hi"`, Container: "web"}}})
+	r.End()
+	want := `digraph witr {
+  rankdir=TB;
+  node [shape=box, fontname="monospace"];
+  p1 [label="systemd\npid 1\nuser: root"];
+  p10 [label="nginx\npid 10\nuser: www-data\nunit: nginx.service", style=bold];
+  p11 [label="nginx\npid 11", style=dashed];
+  p20 [label="say \"hi\"\npid 20\ncontainer: web", style=bold];
+  p1 -> p10;
+  p10 -> p11;
+  p1 -> p20;
+}
+`
+	if b.String() != want {
+		t.Errorf("output =\n%s\nwant\n%s", b.String(), want)
+	}
+}
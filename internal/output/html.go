@@ -0,0 +1,203 @@
+package output
+
+import (
+	"html/template"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// htmlRenderer collects results into one self-contained HTML page, written
+// at End, with no external stylesheets or scripts
+type htmlRenderer struct {
+	w       io.Writer
+	reports []htmlReport
+}
+
+// htmlReport is the view of one result the page template renders
+type htmlReport struct {
+	Title    string
+	Error    string
+	Fields   reportFields
+	Cmdline  string
+	Tree     *htmlNode
+	Source   string
+	Details  reportFields
+	Evidence []string
+	Service  reportFields
+	Contain  reportFields
+	Warnings []string
+}
+
+// htmlNode is a process in the collapsible ancestry tree
+type htmlNode struct {
+	Label    string
+	Note     string
+	Target   bool
+	Below    bool
+	Children []*htmlNode
+}
+
+func (h *htmlRenderer) Begin() error { return nil }
+
+func (h *htmlRenderer) Emit(r model.Result) error {
+	if r.Error != "" {
+		h.reports = append(h.reports, htmlReport{Title: string(r.Target.Type) + " " + r.Target.Value, Error: r.Error})
+		return nil
+	}
+	proc := r.Ancestry[len(r.Ancestry)-1]
+	rep := htmlReport{
+		Title:    proc.Command + " (pid " + strconv.Itoa(proc.PID) + ")",
+		Fields:   processFields(r),
+		Cmdline:  proc.Cmdline,
+		Tree:     htmlTree(r),
+		Source:   sourceSummary(r.Source),
+		Details:  sourceFields(r.Source),
+		Evidence: r.Source.Evidence,
+		Warnings: r.Warnings,
+	}
+	if r.Service != nil {
+		rep.Service = serviceFields(r.Service)
+	}
+	if r.Container != nil {
+		rep.Contain = containerFields(r.Container)
+	}
+	h.reports = append(h.reports, rep)
+	return nil
+}
+
+func (h *htmlRenderer) End() error {
+	return htmlPage.Execute(h.w, struct {
+		Generated string
+		Reports   []htmlReport
+	}{time.Now().Format("Mon 2006-01-02 15:04:05 -07:00"), h.reports})
+}
+
+// htmlTree nests the ancestry from the root down to the target, followed by
+// the subtree below the target when the result has one
+func htmlTree(r model.Result) *htmlNode {
+	var root, parent *htmlNode
+	attach := func(n *htmlNode) {
+		if parent == nil {
+			root = n
+		} else {
+			parent.Children = append(parent.Children, n)
+		}
+		parent = n
+	}
+	for i, p := range r.Ancestry {
+		attach(&htmlNode{
+			Label:  p.Command + " (pid " + strconv.Itoa(p.PID) + ")",
+			Note:   processLabelNote(p),
+			Target: i == len(r.Ancestry)-1,
+		})
+		if p.SkippedAncestors > 0 {
+			attach(&htmlNode{Label: skippedLabel(p.SkippedAncestors)})
+		}
+	}
+	var below func(children []model.ChildProcess) []*htmlNode
+	below = func(children []model.ChildProcess) []*htmlNode {
+		var nodes []*htmlNode
+		for _, c := range children {
+			nodes = append(nodes, &htmlNode{
+				Label:    c.Command + " (pid " + strconv.Itoa(c.PID) + ")",
+				Note:     childArgs(c),
+				Below:    true,
+				Children: below(c.Children),
+			})
+		}
+		return nodes
+	}
+	if parent != nil {
+		parent.Children = append(parent.Children, below(r.Children)...)
+	}
+	return root
+}
+
+// processLabelNote is the user, unit and container shown next to a process
+// in the tree
+func processLabelNote(p model.Process) string {
+	note := ""
+	add := func(s string) {
+		if s == "" {
+			return
+		}
+		if note != "" {
+			note += ", "
+		}
+		note += s
+	}
+	if p.User != "" && p.User != "unknown" {
+		add(p.User)
+	}
+	add(p.Service)
+	add(p.Container)
+	return note
+}
+
+var htmlPage = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>witr report{{range $i, $r := .Reports}}{{if not $i}}: {{$r.Title}}{{end}}{{end}}</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2rem auto; max-width: 60rem; padding: 0 1rem; color: #1f2328; }
+h1 { font-size: 1.4rem; }
+h2 { border-bottom: 1px solid #d0d7de; padding-bottom: .3rem; margin-top: 2.5rem; }
+h3 { font-size: 1.05rem; margin-top: 1.5rem; }
+table { border-collapse: collapse; width: 100%; }
+th, td { text-align: left; vertical-align: top; padding: .3rem .6rem; border-bottom: 1px solid #eaeef2; }
+th { width: 10rem; font-weight: 600; }
+code, pre { font-family: ui-monospace, monospace; font-size: .9em; }
+pre { background: #f6f8fa; padding: .8rem; overflow-x: auto; white-space: pre-wrap; word-break: break-all; }
+.tree, .tree ul { list-style: none; padding-left: 1.2rem; margin: 0; }
+.tree { padding-left: 0; }
+.tree summary { cursor: pointer; }
+.tree .leaf { padding-left: 1.1rem; }
+.target { font-weight: 700; }
+.below { color: #57606a; }
+.note { color: #57606a; font-size: .9em; margin-left: .5rem; }
+.warnings li { margin: .3rem 0; color: #9a6700; }
+.error { color: #cf222e; }
+.generated { color: #57606a; font-size: .85em; }
+</style>
+</head>
+<body>
+<h1>witr report</h1>
+<p class="generated">Generated {{.Generated}}</p>
+{{range .Reports}}
+<section>
+<h2>{{.Title}}</h2>
+{{if .Error}}<p class="error">Error: {{.Error}}</p>{{else}}
+{{template "fields" .Fields}}
+{{if .Cmdline}}<h3>Command</h3>
+<pre>{{.Cmdline}}</pre>{{end}}
+<h3>Why It Exists</h3>
+<ul class="tree">{{template "node" .Tree}}</ul>
+<h3>Source</h3>
+<p><strong>{{.Source}}</strong></p>
+{{template "fields" .Details}}
+{{if .Evidence}}<p>Evidence:</p>
+<ul>{{range .Evidence}}<li>{{.}}</li>{{end}}</ul>{{end}}
+{{if .Service}}<h3>Service Details</h3>
+{{template "fields" .Service}}{{end}}
+{{if .Contain}}<h3>Container Details</h3>
+{{template "fields" .Contain}}{{end}}
+<h3>Warnings</h3>
+{{if .Warnings}}<ul class="warnings">{{range .Warnings}}<li>{{.}}</li>{{end}}</ul>{{else}}<p>No warnings.</p>{{end}}
+{{end}}
+</section>
+{{end}}
+</body>
+</html>
+{{- define "fields"}}{{if .}}<table>{{range .}}
+<tr><th>{{.Name}}</th><td>{{$code := .Code}}{{range $i, $v := .Values}}{{if $i}}<br>{{end}}{{if $code}}<code>{{$v}}</code>{{else}}{{$v}}{{end}}{{end}}</td></tr>{{end}}
+</table>{{end}}{{end}}
+{{- define "node"}}<li>{{if .Children}}<details open><summary>{{template "label" .}}</summary>
+<ul>{{range .Children}}{{template "node" .}}{{end}}</ul>
+</details>{{else}}<div class="leaf">{{template "label" .}}</div>{{end}}</li>{{end}}
+{{- define "label"}}<span{{if .Target}} class="target"{{else if .Below}} class="below"{{end}}>{{.Label}}</span>{{if .Note}}<span class="note">{{.Note}}</span>{{end}}{{end}}
+`))
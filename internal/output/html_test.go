@@ -0,0 +1,41 @@
+package output
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestHTMLReport(t *testing.T) {
+	var b strings.Builder
+	r, _ := NewRenderer(FormatHTML, &b, false)
+	r.Begin()
+	r.Emit(model.Result{
+		Ancestry: []model.Process{
+			{PID: 1, Command: "systemd", User: "root", SkippedAncestors: 3},
+			{PID: 42, Command: "sh", User: "alice", Cmdline: "sh -c 'echo <b>'"},
+		},
+		Children: []model.ChildProcess{{PID: 43, Command: "sleep", Cmdline: "sleep 60"}},
+		Source:   model.Source{Type: model.SourceShell, Evidence: []string{"parent is a shell"}},
+		Warnings: []string{"Process is running as root"},
+	})
+	r.Emit(model.Result{Target: model.Target{Type: model.TargetPID, Value: "7"}, Error: "no process ancestry found"})
+	r.End()
+	out := b.String()
+	for _, want := range []string{
+		"<h2>sh (pid 42)</h2>",
+		"<pre>sh -c &#39;echo &lt;b&gt;&#39;</pre>",
+		`<summary><span>systemd (pid 1)</span><span class="note">root</span></summary>`,
+		`<summary><span>… 3 more …</span></summary>`,
+		`<summary><span class="target">sh (pid 42)</span><span class="note">alice</span></summary>`,
+		`<div class="leaf"><span class="below">sleep (pid 43)</span><span class="note">sleep 60</span></div>`,
+		"<li>parent is a shell</li>",
+		"<li>Process is running as root</li>",
+		`<p class="error">Error: no process ancestry found</p>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report lacks %s", want)
+		}
+	}
+}
@@ -6,8 +6,9 @@ import (
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
+// ToJSON formats a result as --json writes it
 func ToJSON(r model.Result) (string, error) {
-	data, err := json.MarshalIndent(r, "", "  ")
+	data, err := json.MarshalIndent(forJSON(r), "", "  ")
 	if err != nil {
 		return "{}", err
 	}
@@ -0,0 +1,53 @@
+package output
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestToJSON(t *testing.T) {
+	var r model.Result
+	r.AddWarnings(model.Warning{Code: model.WarnRoot, Severity: model.SeverityLow, Message: "Process is running as root"})
+	r = r.WithWarning("Added by a wrapper")
+	data, err := ToJSON(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got model.Result
+	if err := json.Unmarshal([]byte(data), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.SchemaVersion != model.SchemaVersion {
+		t.Errorf("schema_version = %d, want %d", got.SchemaVersion, model.SchemaVersion)
+	}
+	if len(got.Findings) != 2 || got.Findings[0].Code != model.WarnRoot || got.Findings[1].Code != model.WarnOther {
+		t.Errorf("Findings = %+v, want WarnRoot and WarnOther", got.Findings)
+	}
+}
+
+func TestJSONRendererShape(t *testing.T) {
+	tests := []struct {
+		format string
+		n      int
+		want   string
+	}{
+		{FormatJSON, 0, "["},
+		{FormatJSON, 1, "["},
+		{FormatJSONObject, 1, "{"},
+	}
+	for _, tt := range tests {
+		var b strings.Builder
+		r, _ := NewRenderer(tt.format, &b, false)
+		r.Begin()
+		for range tt.n {
+			r.Emit(model.Result{})
+		}
+		r.End()
+		if !strings.HasPrefix(b.String(), tt.want) {
+			t.Errorf("%s with %d results = %q, want it to start with %q", tt.format, tt.n, b.String(), tt.want)
+		}
+	}
+}
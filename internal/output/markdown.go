@@ -0,0 +1,154 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// markdownRenderer writes each result as a Markdown report, separated by
+// horizontal rules, for pasting into issues and postmortems
+type markdownRenderer struct {
+	w     io.Writer
+	count int
+}
+
+func (m *markdownRenderer) Begin() error { return nil }
+
+func (m *markdownRenderer) Emit(r model.Result) error {
+	if m.count > 0 {
+		fmt.Fprint(m.w, "\n---\n\n")
+	}
+	m.count++
+	if r.Error != "" {
+		fmt.Fprintf(m.w, "## %s %s\n\n**Error:** %s\n", r.Target.Type, mdEscape(r.Target.Value), mdEscape(r.Error))
+		return nil
+	}
+	RenderMarkdown(m.w, r)
+	return nil
+}
+
+func (m *markdownRenderer) End() error { return nil }
+
+// RenderMarkdown prints a result as a Markdown report: a table of the
+// process's attributes, the command line and ancestry as code blocks, the
+// source with its evidence, and the warnings
+func RenderMarkdown(w io.Writer, r model.Result) {
+	proc := r.Ancestry[len(r.Ancestry)-1]
+	fmt.Fprintf(w, "## %s (pid %d)\n\n", mdEscape(proc.Command), proc.PID)
+	mdTable(w, "Field", processFields(r))
+
+	if cmdline := proc.Cmdline; cmdline != "" {
+		fmt.Fprint(w, "\n### Command\n\n")
+		mdCodeBlock(w, "sh", cmdline)
+	}
+
+	fmt.Fprint(w, "\n### Why It Exists\n\n")
+	var tree strings.Builder
+	RenderTree(&tree, r, false)
+	mdCodeBlock(w, "text", tree.String())
+
+	fmt.Fprintf(w, "\n### Source\n\n**Source:** %s\n", mdEscape(sourceSummary(r.Source)))
+	if details := sourceFields(r.Source); len(details) > 0 {
+		fmt.Fprintln(w)
+		mdTable(w, "Detail", details)
+	}
+	if len(r.Source.Evidence) > 0 {
+		fmt.Fprint(w, "\nEvidence:\n\n")
+		for _, e := range r.Source.Evidence {
+			fmt.Fprintf(w, "- %s\n", mdEscape(e))
+		}
+	}
+
+	if r.Service != nil {
+		fmt.Fprint(w, "\n### Service Details\n\n")
+		mdTable(w, "Field", serviceFields(r.Service))
+	}
+	if r.Container != nil {
+		fmt.Fprint(w, "\n### Container Details\n\n")
+		mdTable(w, "Field", containerFields(r.Container))
+	}
+
+	fmt.Fprint(w, "\n### Warnings\n\n")
+	if len(r.Warnings) == 0 {
+		fmt.Fprintln(w, "No warnings.")
+	}
+	for _, warning := range r.Warnings {
+		fmt.Fprintf(w, "- %s\n", mdEscape(warning))
+	}
+}
+
+// mdTable prints fields as a two-column table, or nothing without fields.
+// Multiple values share a cell, one per line.
+func mdTable(w io.Writer, key string, fields reportFields) {
+	if len(fields) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "| %s | Value |\n| --- | --- |\n", key)
+	for _, f := range fields {
+		values := make([]string, len(f.Values))
+		for i, v := range f.Values {
+			if f.Code {
+				values[i] = mdCode(v)
+			} else {
+				values[i] = mdEscape(v)
+			}
+		}
+		fmt.Fprintf(w, "| %s | %s |\n", f.Name, strings.Join(values, "<br>"))
+	}
+}
+
+// mdCodeBlock prints text in a fenced code block, with a fence longer than
+// any run of backticks in the text
+func mdCodeBlock(w io.Writer, lang, text string) {
+	fence := strings.Repeat("`", max(3, longestRun(text, '`')+1))
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	fmt.Fprintf(w, "%s%s\n%s%s\n", fence, lang, text, fence)
+}
+
+// mdCode formats s as inline code that is safe inside a table cell, or ""
+// for an empty string
+func mdCode(s string) string {
+	if s == "" {
+		return ""
+	}
+	ticks := strings.Repeat("`", longestRun(s, '`')+1)
+	s = strings.ReplaceAll(s, "|", `\|`)
+	s = strings.Join(strings.Fields(s), " ")
+	if strings.HasPrefix(s, "`") || strings.HasSuffix(s, "`") {
+		s = " " + s + " "
+	}
+	return ticks + s + ticks
+}
+
+// mdEscape escapes the characters that would start Markdown formatting or
+// break a table cell, and folds newlines into spaces
+func mdEscape(s string) string {
+	var b strings.Builder
+	for _, c := range strings.Join(strings.Fields(s), " ") {
+		switch c {
+		case '\\', '`', '*', '_', '[', ']', '<', '>', '|', '#':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// longestRun is the length of the longest run of c in s
+func longestRun(s string, c byte) int {
+	longest, run := 0, 0
+	for i := range len(s) {
+		if s[i] == c {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	return longest
+}
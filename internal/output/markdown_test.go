@@ -0,0 +1,21 @@
+package output
+
+import "testing"
+
+func TestMarkdownEscaping(t *testing.T) {
+	for _, tt := range []struct{ in, code, escaped string }{
+		{"nginx.service", "`nginx.service`", "nginx.service"},
+		{"a | b", "`a \\| b`", "a \\| b"},
+		{"echo `date`", "`` echo `date` ``", "echo \\`date\\`"},
+		{"line\nbreak", "`line break`", "line break"},
+		{"*_[x]_*", "`*_[x]_*`", "\\*\\_\\[x\\]\\_\\*"},
+		{"", "", ""},
+	} {
+		if got := mdCode(tt.in); got != tt.code {
+			t.Errorf("mdCode(%q) = %q, want %q", tt.in, got, tt.code)
+		}
+		if got := mdEscape(tt.in); got != tt.escaped {
+			t.Errorf("mdEscape(%q) = %q, want %q", tt.in, got, tt.escaped)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// Renderer writes a stream of results. Single-shot and long-running modes
+// (watch, scan, trace, serve) share the same renderers: Begin is called once
+// before the first result, Emit for each result, and End once at the end.
+type Renderer interface {
+	Begin() error
+	Emit(r model.Result) error
+	End() error
+}
+
+// Output formats accepted by NewRenderer
+const (
+	FormatStandard = "standard"
+	FormatVerbose  = "verbose"
+	FormatShort    = "short"
+	FormatTree     = "tree"
+	FormatWarnings = "warnings"
+	FormatJSON     = "json"
+	FormatNDJSON   = "ndjson"
+	FormatDOT      = "dot"
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+	FormatCSV      = "csv"
+	FormatTSV      = "tsv"
+
+	// FormatJSONObject is FormatJSON writing its one result as a bare
+	// object, as witr does when explaining a single target
+	FormatJSONObject = "json-object"
+)
+
+// NewRenderer returns the renderer for a format, writing to w. Formats made
+// by TemplateFormat render each result with a Go template.
+func NewRenderer(format string, w io.Writer, colorEnabled bool) (Renderer, error) {
+	if text, ok := strings.CutPrefix(format, templatePrefix); ok {
+		tmpl, err := ParseTemplate(text)
+		if err != nil {
+			return nil, err
+		}
+		return &templateRenderer{w: w, tmpl: tmpl}, nil
+	}
+	switch format {
+	case FormatStandard, "":
+		return &humanRenderer{w: w, render: func(r model.Result) { RenderStandard(w, r, colorEnabled) }, color: colorEnabled}, nil
+	case FormatVerbose:
+		return &humanRenderer{w: w, render: func(r model.Result) { RenderVerbose(w, r, colorEnabled) }, color: colorEnabled}, nil
+	case FormatShort:
+		return &humanRenderer{w: w, render: func(r model.Result) { RenderShort(w, r, colorEnabled) }, compact: true, color: colorEnabled}, nil
+	case FormatTree:
+		return &humanRenderer{w: w, render: func(r model.Result) { RenderTree(w, r, colorEnabled) }, color: colorEnabled}, nil
+	case FormatWarnings:
+		return &humanRenderer{w: w, render: func(r model.Result) { RenderWarnings(w, r.Warnings, colorEnabled) }, color: colorEnabled}, nil
+	case FormatJSON:
+		return &jsonRenderer{w: w}, nil
+	case FormatJSONObject:
+		return &jsonRenderer{w: w, object: true}, nil
+	case FormatNDJSON:
+		return &ndjsonRenderer{enc: json.NewEncoder(w)}, nil
+	case FormatDOT:
+		return &dotRenderer{w: w}, nil
+	case FormatMarkdown:
+		return &markdownRenderer{w: w}, nil
+	case FormatHTML:
+		return &htmlRenderer{w: w}, nil
+	case FormatCSV:
+		return newCSVRenderer(w, ','), nil
+	case FormatTSV:
+		return newCSVRenderer(w, '\t'), nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+// humanRenderer prints each result with a human-readable renderer, separating
+// consecutive results with a blank line (unless compact). Failed targets
+// are reported in place of their result.
+type humanRenderer struct {
+	w       io.Writer
+	render  func(model.Result)
+	compact bool
+	color   bool
+	count   int
+}
+
+func (h *humanRenderer) Begin() error { return nil }
+
+func (h *humanRenderer) Emit(r model.Result) error {
+	if h.count > 0 && !h.compact {
+		fmt.Fprintln(h.w)
+	}
+	if r.Error != "" {
+		renderError(h.w, r, h.compact, h.color)
+	} else {
+		h.render(r)
+	}
+	h.count++
+	return nil
+}
+
+func (h *humanRenderer) End() error { return nil }
+
+// jsonRenderer writes the results as an array, even when there are none
+// or one, so scans and multi-target runs parse the same way at any size.
+// object writes a single result as a bare object instead.
+type jsonRenderer struct {
+	w       io.Writer
+	object  bool
+	results []model.Result
+}
+
+func (j *jsonRenderer) Begin() error { return nil }
+
+func (j *jsonRenderer) Emit(r model.Result) error {
+	j.results = append(j.results, forJSON(r))
+	return nil
+}
+
+func (j *jsonRenderer) End() error {
+	var v any = j.results
+	if j.results == nil {
+		v = []model.Result{}
+	}
+	if j.object && len(j.results) == 1 {
+		v = j.results[0]
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(j.w, string(data))
+	return err
+}
+
+// forJSON fills in the fields only machine-readable output carries: the
+// schema version and a coded finding for every warning
+func forJSON(r model.Result) model.Result {
+	r.SchemaVersion = model.SchemaVersion
+	r.Findings = r.WarningFindings()
+	return r
+}
+
+// ndjsonRenderer writes one compact JSON object per line as results arrive
+type ndjsonRenderer struct {
+	enc *json.Encoder
+}
+
+func (n *ndjsonRenderer) Begin() error { return nil }
+
+func (n *ndjsonRenderer) Emit(r model.Result) error {
+	return n.enc.Encode(forJSON(r))
+}
+
+func (n *ndjsonRenderer) End() error { return nil }
+
+// renderError reports a target that could not be explained
+func renderError(w io.Writer, r model.Result, compact, colorEnabled bool) {
+	name := string(r.Target.Type) + " " + r.Target.Value
+	switch {
+	case compact && colorEnabled:
+		fmt.Fprintf(w, "%s: %s%s%s\n", name, colorRed, r.Error, colorReset)
+	case compact:
+		fmt.Fprintf(w, "%s: %s\n", name, r.Error)
+	case colorEnabled:
+		fmt.Fprintf(w, "%sTarget%s      : %s\n%sError%s       : %s\n", colorBlue, colorReset, name, colorRed, colorReset, r.Error)
+	default:
+		fmt.Fprintf(w, "Target      : %s\nError       : %s\n", name, r.Error)
+	}
+}
@@ -0,0 +1,147 @@
+package output
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// reportField is one row of the attribute tables the Markdown and HTML
+// reports share. Code values are shown verbatim in a monospace font.
+type reportField struct {
+	Name   string
+	Values []string
+	Code   bool
+}
+
+// reportFields collects rows, skipping empty values
+type reportFields []reportField
+
+func (f *reportFields) text(name, value string) {
+	if value != "" {
+		*f = append(*f, reportField{Name: name, Values: []string{value}})
+	}
+}
+
+func (f *reportFields) code(name string, values ...string) {
+	var kept []string
+	for _, v := range values {
+		if v != "" {
+			kept = append(kept, v)
+		}
+	}
+	if len(kept) > 0 {
+		*f = append(*f, reportField{Name: name, Values: kept, Code: true})
+	}
+}
+
+// processFields lists what the report tables show about the target
+func processFields(r model.Result) reportFields {
+	proc := r.Ancestry[len(r.Ancestry)-1]
+	var f reportFields
+	if proc.Health != "" && proc.Health != "healthy" {
+		f.text("Health", proc.Health)
+	}
+	f.text("User", formatUser(proc))
+	if r.Login != nil {
+		f.text("Login", formatLogin(r.Login, proc))
+	}
+	f.code("Service", proc.Service)
+	f.text("Container", proc.Container)
+	f.code("Executable", proc.Exe)
+	if pkg := r.Package; pkg != nil && pkg.Name != "" {
+		f.text("Package", formatPackage(pkg))
+	}
+	f.text("Started", formatStarted(proc))
+	f.text("Resources", formatUsage(proc))
+	if r.Family != nil {
+		f.text("Family", formatFamily(r.Family))
+	}
+	f.code("Listening", listeningAddrs(proc)...)
+	if r.SocketInfo != nil {
+		f.text("Socket", r.SocketInfo.State)
+	}
+	f.code("Working Dir", proc.WorkingDir)
+	if chrooted(proc) {
+		f.code("Root Dir", proc.Root+" (chroot)")
+	}
+	if proc.GitRepo != "" {
+		repo := proc.GitRepo
+		if proc.GitBranch != "" {
+			repo += " (" + proc.GitBranch + ")"
+		}
+		f.text("Git Repo", repo)
+	}
+	if r.RestartCount > 0 {
+		f.text("Restarts", strconv.Itoa(r.RestartCount))
+	}
+	return f
+}
+
+// sourceSummary names the source with its type and confidence
+func sourceSummary(src model.Source) string {
+	label := string(src.Type)
+	if src.Name != "" && src.Name != label {
+		label = src.Name + " (" + label + ")"
+	}
+	if src.Confidence > 0 {
+		label += ", confidence " + strconv.Itoa(int(src.Confidence*100+0.5)) + "% (" + confidenceLevel(src.Confidence) + ")"
+	}
+	return label
+}
+
+// sourceFields lists the source details in display order
+func sourceFields(src model.Source) reportFields {
+	var f reportFields
+	for _, key := range orderedDetailKeys(src.Details) {
+		f.text(strings.TrimSpace(formatDetailLabel(key)), src.Details[key])
+	}
+	return f
+}
+
+// serviceFields lists the systemd unit details
+func serviceFields(s *model.ServiceUnit) reportFields {
+	state := s.ActiveState
+	if s.SubState != "" {
+		state += "/" + s.SubState
+	}
+	if s.ActiveSince != "" {
+		state += " since " + s.ActiveSince
+	}
+	var f reportFields
+	f.code("Unit", s.Unit)
+	f.text("State", state)
+	f.code("ExecStart", s.ExecStart)
+	restart := s.Restart
+	switch {
+	case s.NRestarts == 1:
+		restart += " (restarted once)"
+	case s.NRestarts > 1:
+		restart += " (restarted " + strconv.Itoa(s.NRestarts) + " times)"
+	}
+	f.text("Restart", restart)
+	f.code("Unit File", s.FragmentPath)
+	var dropIns []string
+	for _, d := range s.DropIns {
+		entry := d.Path
+		if len(d.Settings) > 0 {
+			entry += " (" + strings.Join(d.Settings, ", ") + ")"
+		}
+		dropIns = append(dropIns, entry)
+	}
+	f.code("Drop-ins", dropIns...)
+	return f
+}
+
+// containerFields lists the container details
+func containerFields(c *model.Container) reportFields {
+	var f reportFields
+	f.text("Runtime", c.Runtime)
+	f.text("Name", c.Name)
+	f.code("Image", c.Image)
+	f.code("Digest", c.Digest)
+	f.code("Ports", containerPorts(c)...)
+	f.code("Mounts", containerMounts(c)...)
+	return f
+}
@@ -2,6 +2,8 @@ package output
 
 import (
 	"fmt"
+	"io"
+	"strings"
 
 	"github.com/pranshuparmar/witr/pkg/model"
 )
@@ -12,20 +14,48 @@ var (
 	colorBoldShort    = "\033[2m"
 )
 
-func RenderShort(r model.Result, colorEnabled bool) {
+func RenderShort(w io.Writer, r model.Result, colorEnabled bool) {
 	for i, p := range r.Ancestry {
 		if i > 0 {
 			if colorEnabled {
-				fmt.Print(colorMagentaShort + " → " + colorResetShort)
+				fmt.Fprint(w, colorMagentaShort+" "+glyphs.arrow+" "+colorResetShort)
 			} else {
-				fmt.Print(" → ")
+				fmt.Fprint(w, " "+glyphs.arrow+" ")
 			}
 		}
 		if colorEnabled {
-			fmt.Printf("%s (%spid %d%s)", p.Command, colorBoldShort, p.PID, colorResetShort)
+			fmt.Fprintf(w, "%s (%spid %d%s)", p.Command, colorBoldShort, p.PID, colorResetShort)
 		} else {
-			fmt.Printf("%s (pid %d)", p.Command, p.PID)
+			fmt.Fprintf(w, "%s (pid %d)", p.Command, p.PID)
 		}
+		if p.SkippedAncestors > 0 {
+			if colorEnabled {
+				fmt.Fprint(w, colorMagentaShort+" "+glyphs.arrow+" "+colorResetShort+colorBoldShort+skippedLabel(p.SkippedAncestors)+colorResetShort)
+			} else {
+				fmt.Fprint(w, " "+glyphs.arrow+" "+skippedLabel(p.SkippedAncestors))
+			}
+		}
+	}
+	if len(r.Ancestry) > 0 {
+		if usage := shortUsage(r.Ancestry[len(r.Ancestry)-1]); usage != "" {
+			if colorEnabled {
+				fmt.Fprint(w, "  "+colorBoldShort+"["+usage+"]"+colorResetShort)
+			} else {
+				fmt.Fprint(w, "  ["+usage+"]")
+			}
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+// shortUsage is the compact CPU and memory summary of the short output
+func shortUsage(p model.Process) string {
+	var parts []string
+	if p.CPUPercent != nil {
+		parts = append(parts, fmt.Sprintf("%.1f%% cpu", *p.CPUPercent))
+	}
+	if p.RSSBytes > 0 {
+		parts = append(parts, formatBytes(p.RSSBytes))
 	}
-	fmt.Println()
+	return strings.Join(parts, ", ")
 }
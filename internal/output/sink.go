@@ -0,0 +1,118 @@
+package output
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// OpenSink opens an output destination from a URI:
+//
+//	file:<path>     written atomically (temporary file, then rename) on Close
+//	unix:<path>     streamed to a Unix stream socket
+//	journald:       sent to the systemd journal as one entry on Close
+//
+// An empty URI or "-" writes to stdout.
+func OpenSink(uri string) (io.WriteCloser, error) {
+	if uri == "" || uri == "-" {
+		return nopCloser{os.Stdout}, nil
+	}
+	kind, path, ok := strings.Cut(uri, ":")
+	if !ok {
+		return nil, fmt.Errorf("invalid output %q, expected file:<path>, unix:<path> or journald:", uri)
+	}
+	switch kind {
+	case "file":
+		if path == "" {
+			return nil, fmt.Errorf("output file: missing path")
+		}
+		return &fileSink{path: path}, nil
+	case "unix":
+		if path == "" {
+			return nil, fmt.Errorf("output unix: missing socket path")
+		}
+		conn, err := net.Dial("unix", path)
+		if err != nil {
+			return nil, fmt.Errorf("output %s: %w", uri, err)
+		}
+		return conn, nil
+	case "journald":
+		return &journalSink{}, nil
+	default:
+		return nil, fmt.Errorf("unknown output type %q", kind)
+	}
+}
+
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
+
+// fileSink buffers output and replaces the file in one rename, so readers
+// never see a partial result
+type fileSink struct {
+	path string
+	buf  bytes.Buffer
+}
+
+func (f *fileSink) Write(p []byte) (int, error) { return f.buf.Write(p) }
+
+func (f *fileSink) Close() error {
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), "."+filepath.Base(f.path)+".*")
+	if err != nil {
+		return fmt.Errorf("output file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(f.buf.Bytes()); err != nil {
+		tmp.Close()
+		return err
+	}
+	// Keep the mode of the file being replaced; new files stay private (0600)
+	if fi, err := os.Stat(f.path); err == nil {
+		if err := tmp.Chmod(fi.Mode().Perm()); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.path)
+}
+
+// journalSink buffers output and sends it as the MESSAGE of a journal entry
+type journalSink struct {
+	buf bytes.Buffer
+}
+
+func (j *journalSink) Write(p []byte) (int, error) { return j.buf.Write(p) }
+
+func (j *journalSink) Close() error {
+	return sendJournal(journalEntry([][2]string{
+		{"MESSAGE", strings.TrimRight(j.buf.String(), "\n")},
+		{"SYSLOG_IDENTIFIER", "witr"},
+		{"PRIORITY", "6"},
+	}))
+}
+
+// journalEntry encodes fields in the journal's native protocol. Values with
+// newlines use the binary form: NAME, newline, 64-bit little-endian length,
+// value, newline.
+func journalEntry(fields [][2]string) []byte {
+	var b bytes.Buffer
+	for _, f := range fields {
+		name, value := f[0], f[1]
+		if !strings.Contains(value, "\n") {
+			b.WriteString(name + "=" + value + "\n")
+			continue
+		}
+		b.WriteString(name + "\n")
+		binary.Write(&b, binary.LittleEndian, uint64(len(value)))
+		b.WriteString(value + "\n")
+	}
+	return b.Bytes()
+}
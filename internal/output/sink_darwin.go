@@ -0,0 +1,10 @@
+//go:build darwin
+
+package output
+
+import "fmt"
+
+// sendJournal fails: there is no journald on macOS
+func sendJournal(entry []byte) error {
+	return fmt.Errorf("output journald: not supported on macOS")
+}
@@ -0,0 +1,58 @@
+//go:build linux
+
+package output
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+)
+
+const journalSocket = "/run/systemd/journal/socket"
+
+// sendJournal sends one native-protocol entry to journald. Entries too large
+// for a datagram are written to an unlinked file in /dev/shm whose
+// descriptor is passed instead, as sd_journal_send does.
+func sendJournal(entry []byte) error {
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: journalSocket, Net: "unixgram"})
+	if err != nil {
+		return fmt.Errorf("output journald: %w", err)
+	}
+	defer conn.Close()
+	_, err = conn.Write(entry)
+	if errors.Is(err, syscall.EMSGSIZE) || errors.Is(err, syscall.ENOBUFS) {
+		err = sendJournalFile(conn, entry)
+	}
+	if err != nil {
+		return fmt.Errorf("output journald: %w", err)
+	}
+	return nil
+}
+
+// sendJournalFile passes entry to journald as a file descriptor. journald
+// accepts unsealed files only from /dev/shm, /tmp or /var/tmp.
+func sendJournalFile(conn *net.UnixConn, entry []byte) error {
+	f, err := os.CreateTemp("/dev/shm", "witr-journal-*")
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	os.Remove(f.Name())
+	if _, err := f.Write(entry); err != nil {
+		return err
+	}
+	// WriteMsgUnix refuses connected datagram sockets, so send on the raw socket
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		return err
+	}
+	rights := syscall.UnixRights(int(f.Fd()))
+	if cerr := raw.Control(func(fd uintptr) {
+		err = syscall.Sendmsg(int(fd), nil, rights, nil, 0)
+	}); cerr != nil {
+		return cerr
+	}
+	return err
+}
@@ -0,0 +1,53 @@
+//go:build linux
+
+package output
+
+import (
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestSendJournalFile(t *testing.T) {
+	addr := &net.UnixAddr{Name: filepath.Join(t.TempDir(), "journal"), Net: "unixgram"}
+	server, err := net.ListenUnixgram("unixgram", addr)
+	if err != nil {
+		t.Skip(err)
+	}
+	defer server.Close()
+	conn, err := net.DialUnix("unixgram", nil, addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	entry := journalEntry([][2]string{{"MESSAGE", strings.Repeat("x", 1<<20)}})
+	if err := sendJournalFile(conn, entry); err != nil {
+		t.Skip(err)
+	}
+
+	// journald reads the entry from the passed descriptor
+	oob := make([]byte, syscall.CmsgSpace(4))
+	_, oobn, _, _, err := server.ReadMsgUnix(nil, oob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msgs, err := syscall.ParseSocketControlMessage(oob[:oobn])
+	if err != nil || len(msgs) != 1 {
+		t.Fatalf("control messages = %v, %v; want one", msgs, err)
+	}
+	fds, err := syscall.ParseUnixRights(&msgs[0])
+	if err != nil || len(fds) != 1 {
+		t.Fatalf("rights = %v, %v; want one descriptor", fds, err)
+	}
+	f := os.NewFile(uintptr(fds[0]), "entry")
+	defer f.Close()
+	f.Seek(0, io.SeekStart)
+	if got, _ := io.ReadAll(f); string(got) != string(entry) {
+		t.Errorf("passed file holds %d bytes, want the %d-byte entry", len(got), len(entry))
+	}
+}
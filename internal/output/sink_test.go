@@ -0,0 +1,68 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileSinkWritesOnClose(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "last.json")
+	sink, err := OpenSink("file:" + path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink.Write([]byte(`{"PID": 1}`))
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("file exists before Close: %v", err)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != `{"PID": 1}` {
+		t.Errorf("file content = %q", data)
+	}
+	if matches, _ := filepath.Glob(filepath.Join(filepath.Dir(path), ".last.json.*")); len(matches) != 0 {
+		t.Errorf("temporary files left behind: %v", matches)
+	}
+}
+
+func TestFileSinkMode(t *testing.T) {
+	dir := t.TempDir()
+	write := func(path string) os.FileMode {
+		t.Helper()
+		sink, err := OpenSink("file:" + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sink.Write([]byte("{}"))
+		if err := sink.Close(); err != nil {
+			t.Fatal(err)
+		}
+		fi, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return fi.Mode().Perm()
+	}
+
+	if mode := write(filepath.Join(dir, "new.json")); mode != 0o600 {
+		t.Errorf("new file mode = %o, want 600", mode)
+	}
+	shared := filepath.Join(dir, "shared.json")
+	if err := os.WriteFile(shared, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	os.Chmod(shared, 0o644)
+	if mode := write(shared); mode != 0o644 {
+		t.Errorf("replaced file mode = %o, want the existing 644", mode)
+	}
+}
+
+func TestJournalEntry(t *testing.T) {
+	got := string(journalEntry([][2]string{{"PRIORITY", "6"}, {"MESSAGE", "a\nb"}}))
+	want := "PRIORITY=6\nMESSAGE\n\x03\x00\x00\x00\x00\x00\x00\x00a\nb\n"
+	if got != want {
+		t.Errorf("journalEntry() = %q, want %q", got, want)
+	}
+}
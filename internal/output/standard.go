@@ -2,6 +2,12 @@ package output
 
 import (
 	"fmt"
+	"io"
+	"net"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/pranshuparmar/witr/pkg/model"
@@ -21,10 +27,72 @@ var (
 // formatDetailLabel formats a detail key into a padded label for display
 func formatDetailLabel(key string) string {
 	labels := map[string]string{
-		"type":      "              Type",
-		"plist":     "              Plist",
-		"triggers":  "              Trigger",
-		"keepalive": "              KeepAlive",
+		"type":            "              Type",
+		"plist":           "              Plist",
+		"triggers":        "              Trigger",
+		"keepalive":       "              KeepAlive",
+		"app":             "              App",
+		"script":          "              Script",
+		"ecosystem":       "              Ecosystem",
+		"job":             "              Job",
+		"unit":            "              Unit",
+		"schedule":        "              Schedule",
+		"session":         "              Session",
+		"window":          "              Window",
+		"client":          "              Client",
+		"origin":          "              Origin",
+		"remote":          "              Remote",
+		"tty":             "              TTY",
+		"role":            "              Role",
+		"entry":           "              Entry",
+		"command":         "              Command",
+		"user":            "              User",
+		"timer":           "              Timer",
+		"last":            "              Last Run",
+		"next":            "              Next Run",
+		"manager":         "              Manager",
+		"activation":      "              Activation",
+		"logout":          "              On Logout",
+		"desktop":         "              Desktop File",
+		"hint":            "              Hint",
+		"launcher":        "              Launcher",
+		"via":             "              Via",
+		"display":         "              Display",
+		"seat":            "              Seat",
+		"clients":         "              Clients",
+		"container":       "              Container",
+		"compose":         "              Compose File",
+		"image":           "              Image",
+		"entrypoint":      "              Entrypoint",
+		"workload":        "              Workload",
+		"rootless":        "              Rootless",
+		"pod":             "              Pod",
+		"pod_uid":         "              Pod UID",
+		"profiles":        "              Profiles",
+		"storage":         "              Storage",
+		"guest":           "              Guest",
+		"kind":            "              Kind",
+		"revision":        "              Revision",
+		"runtime":         "              Runtime",
+		"instance":        "              Instance",
+		"appimage":        "              AppImage",
+		"mount":           "              Mounted At",
+		"device":          "              Device",
+		"event":           "              Event",
+		"rule":            "              Rule",
+		"unit file":       "              Unit File",
+		"module":          "              NixOS Module",
+		"derivation":      "              Derivation",
+		"distro":          "              Distro",
+		"relay":           "              Relay",
+		"display manager": "              Display Manager",
+		"bus":             "              Bus",
+		"bus name":        "              Bus Name",
+		"service file":    "              Service File",
+		"requested by":    "              Requested By",
+		"socket":          "              Socket",
+		"listen":          "              Listen",
+		"respawn":         "              Respawn",
 	}
 	if label, ok := labels[key]; ok {
 		return label
@@ -32,271 +100,513 @@ func formatDetailLabel(key string) string {
 	return "              " + key
 }
 
+// confidenceLevel describes a detector confidence in words
+func confidenceLevel(c float64) string {
+	switch {
+	case c >= 0.8:
+		return "high"
+	case c >= 0.5:
+		return "medium"
+	default:
+		return "low, best guess"
+	}
+}
+
+// detailKeyOrder lists well-known detail keys in display order
+var detailKeyOrder = []string{
+	"type", "kind", "revision", "origin", "plist", "triggers", "keepalive", "pod", "pod_uid", "container", "image", "entrypoint", "workload", "rootless", "compose", "profiles", "storage", "guest",
+	"appimage", "mount", "app", "runtime", "instance", "script", "ecosystem",
+	"manager", "bus", "bus name", "service file", "requested by", "job", "unit", "unit file", "module", "derivation", "activation", "socket", "listen", "respawn", "logout", "timer", "schedule", "last", "next",
+	"session", "seat", "display manager", "display", "window", "client", "remote", "tty", "distro", "relay",
+	"device", "event", "rule", "role", "clients", "entry", "user", "command", "desktop", "launcher", "via", "hint",
+}
+
+// orderedDetailKeys returns the detail keys in a consistent order: well-known
+// keys first, then any remaining keys sorted alphabetically
+func orderedDetailKeys(details map[string]string) []string {
+	var keys []string
+	known := make(map[string]bool)
+	for _, key := range detailKeyOrder {
+		known[key] = true
+		if _, ok := details[key]; ok {
+			keys = append(keys, key)
+		}
+	}
+	var extra []string
+	for key := range details {
+		if !known[key] {
+			extra = append(extra, key)
+		}
+	}
+	sort.Strings(extra)
+	return append(keys, extra...)
+}
+
 // RenderWarnings prints only the warnings, with color if enabled
-func RenderWarnings(warnings []string, colorEnabled bool) {
+func RenderWarnings(w io.Writer, warnings []string, colorEnabled bool) {
 	if len(warnings) == 0 {
 		if colorEnabled {
-			fmt.Printf("%sNo warnings.%s\n", colorGreen, colorReset)
+			fmt.Fprintf(w, "%sNo warnings.%s\n", colorGreen, colorReset)
 		} else {
-			fmt.Println("No warnings.")
+			fmt.Fprintln(w, "No warnings.")
 		}
 		return
 	}
 	if colorEnabled {
-		fmt.Printf("%sWarnings%s:\n", colorRed, colorReset)
-		for _, w := range warnings {
-			fmt.Printf("  • %s\n", w)
+		fmt.Fprintf(w, "%sWarnings%s:\n", colorRed, colorReset)
+		for _, warning := range warnings {
+			fmt.Fprintf(w, "  • %s\n", warning)
 		}
 	} else {
-		fmt.Println("Warnings:")
-		for _, w := range warnings {
-			fmt.Printf("  • %s\n", w)
+		fmt.Fprintln(w, "Warnings:")
+		for _, warning := range warnings {
+			fmt.Fprintf(w, "  • %s\n", warning)
 		}
 	}
 }
 
-func RenderStandard(r model.Result, colorEnabled bool) {
+func RenderStandard(w io.Writer, r model.Result, colorEnabled bool) {
+	renderStandard(w, r, colorEnabled, false)
+}
+
+// renderStandard prints the report, adding the sections of RenderVerbose
+// when verbose is set
+func renderStandard(w io.Writer, r model.Result, colorEnabled, verbose bool) {
 	// Target
 	target := "unknown"
 	if len(r.Ancestry) > 0 {
 		target = r.Ancestry[len(r.Ancestry)-1].Command
 	}
 	if colorEnabled {
-		fmt.Printf("%sTarget%s      : %s\n\n", colorBlue, colorReset, target)
+		fmt.Fprintf(w, "%sTarget%s      : %s\n\n", colorBlue, colorReset, target)
 	} else {
-		fmt.Printf("Target      : %s\n\n", target)
+		fmt.Fprintf(w, "Target      : %s\n\n", target)
+	}
+
+	if sb := r.Sandbox; sb != nil {
+		note := sb.Runtime
+		if len(sb.Skipped) > 0 {
+			note += "; not inspected: " + strings.Join(sb.Skipped, ", ")
+		}
+		if colorEnabled {
+			fmt.Fprintf(w, "%sSandbox%s     : %s\n\n", colorDimYellow, colorReset, note)
+		} else {
+			fmt.Fprintf(w, "Sandbox     : %s\n\n", note)
+		}
+	}
+
+	// Results from history may describe a process that no longer exists
+	if r.PreviousBoot {
+		note := "recorded during a previous boot"
+		if r.BootID != "" {
+			note += " (boot " + r.BootID + ")"
+		}
+		if colorEnabled {
+			fmt.Fprintf(w, "%sHistory%s     : %s%s%s\n\n", colorDimYellow, colorReset, colorDimYellow, note, colorReset)
+		} else {
+			fmt.Fprintf(w, "History     : %s\n\n", note)
+		}
 	}
 
 	// Process
 	var proc = r.Ancestry[len(r.Ancestry)-1]
 	if colorEnabled {
-		fmt.Printf("%sProcess%s     : %s (%spid %d%s)", colorBlue, colorReset, proc.Command, colorBold, proc.PID, colorReset)
+		fmt.Fprintf(w, "%sProcess%s     : %s (%spid %d%s)", colorBlue, colorReset, proc.Command, colorBold, proc.PID, colorReset)
 	} else {
-		fmt.Printf("Process     : %s (pid %d)", proc.Command, proc.PID)
+		fmt.Fprintf(w, "Process     : %s (pid %d)", proc.Command, proc.PID)
 	}
 	// Health status
 	if proc.Health != "" && proc.Health != "healthy" {
 		healthColor := colorRed
 		if colorEnabled {
-			fmt.Printf(" %s[%s]%s", healthColor, proc.Health, colorReset)
+			fmt.Fprintf(w, " %s[%s]%s", healthColor, proc.Health, colorReset)
 		} else {
-			fmt.Printf(" [%s]", proc.Health)
+			fmt.Fprintf(w, " [%s]", proc.Health)
 		}
 	}
 	// Forked status: only display if forked
 	if proc.Forked == "forked" {
 		forkColor := colorDimYellow
 		if colorEnabled {
-			fmt.Printf(" %s{forked}%s", forkColor, colorReset)
+			fmt.Fprintf(w, " %s{forked}%s", forkColor, colorReset)
+		} else {
+			fmt.Fprintf(w, " {forked}")
+		}
+	}
+	fmt.Fprintln(w, "")
+	if user := formatUser(proc); user != "" {
+		if colorEnabled {
+			fmt.Fprintf(w, "%sUser%s        : %s\n", colorCyan, colorReset, user)
+		} else {
+			fmt.Fprintf(w, "User        : %s\n", user)
+		}
+	}
+	if r.Login != nil {
+		if colorEnabled {
+			fmt.Fprintf(w, "%sLogin%s       : %s\n", colorCyan, colorReset, formatLogin(r.Login, proc))
 		} else {
-			fmt.Printf(" {forked}")
+			fmt.Fprintf(w, "Login       : %s\n", formatLogin(r.Login, proc))
+		}
+	}
+	if id := proc.Identity; id != nil {
+		if colorEnabled {
+			fmt.Fprintf(w, "%sIdentity%s    : %s\n", colorCyan, colorReset, formatIdentity(id))
+			fmt.Fprintf(w, "%sPrivilege%s   : %s\n", colorCyan, colorReset, id.Privilege)
+		} else {
+			fmt.Fprintf(w, "Identity    : %s\n", formatIdentity(id))
+			fmt.Fprintf(w, "Privilege   : %s\n", id.Privilege)
+		}
+		if caps := formatCapabilities(id); caps != "" {
+			if colorEnabled {
+				fmt.Fprintf(w, "%sCaps%s        : %s\n", colorCyan, colorReset, caps)
+			} else {
+				fmt.Fprintf(w, "Caps        : %s\n", caps)
+			}
+		}
+		if seccomp := formatSeccomp(id); seccomp != "" {
+			if colorEnabled {
+				fmt.Fprintf(w, "%sSeccomp%s     : %s\n", colorCyan, colorReset, seccomp)
+			} else {
+				fmt.Fprintf(w, "Seccomp     : %s\n", seccomp)
+			}
 		}
 	}
-	fmt.Println("")
-	if proc.User != "" && proc.User != "unknown" {
+	if sec := proc.Security; sec != nil {
 		if colorEnabled {
-			fmt.Printf("%sUser%s        : %s\n", colorCyan, colorReset, proc.User)
+			fmt.Fprintf(w, "%sConfinement%s : %s\n", colorCyan, colorReset, formatSecurity(sec))
 		} else {
-			fmt.Printf("User        : %s\n", proc.User)
+			fmt.Fprintf(w, "Confinement : %s\n", formatSecurity(sec))
+		}
+	}
+
+	if sched := proc.Scheduling; sched != nil {
+		if colorEnabled {
+			fmt.Fprintf(w, "%sScheduling%s  : %s\n", colorCyan, colorReset, formatScheduling(sched))
+			fmt.Fprintf(w, "%sOOM Score%s   : %s\n", colorCyan, colorReset, formatOOMScore(sched))
+		} else {
+			fmt.Fprintf(w, "Scheduling  : %s\n", formatScheduling(sched))
+			fmt.Fprintf(w, "OOM Score   : %s\n", formatOOMScore(sched))
 		}
 	}
 
 	// Container
 	if proc.Container != "" {
 		if colorEnabled {
-			fmt.Printf("%sContainer%s   : %s\n", colorBlue, colorReset, proc.Container)
+			fmt.Fprintf(w, "%sContainer%s   : %s\n", colorBlue, colorReset, proc.Container)
+		} else {
+			fmt.Fprintf(w, "Container   : %s\n", proc.Container)
+		}
+	}
+	if ns := formatNamespaces(proc); ns != "" {
+		if colorEnabled {
+			fmt.Fprintf(w, "%sNamespaces%s  : %s\n", colorBlue, colorReset, ns)
 		} else {
-			fmt.Printf("Container   : %s\n", proc.Container)
+			fmt.Fprintf(w, "Namespaces  : %s\n", ns)
 		}
 	}
 	// Service
 	if proc.Service != "" {
 		if colorEnabled {
-			fmt.Printf("%sService%s     : %s\n", colorBlue, colorReset, proc.Service)
+			fmt.Fprintf(w, "%sService%s     : %s\n", colorBlue, colorReset, proc.Service)
 		} else {
-			fmt.Printf("Service     : %s\n", proc.Service)
+			fmt.Fprintf(w, "Service     : %s\n", proc.Service)
 		}
 	}
 
+	// The executable, when the process goes by another name
+	if argv0 := proc.Argv0(); proc.Exe != "" && argv0 != "" && filepath.Base(argv0) != filepath.Base(proc.Exe) {
+		if colorEnabled {
+			fmt.Fprintf(w, "%sExecutable%s  : %s\n", colorGreen, colorReset, proc.Exe)
+		} else {
+			fmt.Fprintf(w, "Executable  : %s\n", proc.Exe)
+		}
+	}
 	if proc.Cmdline != "" {
 		if colorEnabled {
-			fmt.Printf("%sCommand%s     : %s\n", colorGreen, colorReset, proc.Cmdline)
+			fmt.Fprintf(w, "%sCommand%s     : %s\n", colorGreen, colorReset, proc.Cmdline)
 		} else {
-			fmt.Printf("Command     : %s\n", proc.Cmdline)
+			fmt.Fprintf(w, "Command     : %s\n", proc.Cmdline)
 		}
 	} else {
 		if colorEnabled {
-			fmt.Printf("%sCommand%s     : %s\n", colorGreen, colorReset, proc.Command)
+			fmt.Fprintf(w, "%sCommand%s     : %s\n", colorGreen, colorReset, proc.Command)
 		} else {
-			fmt.Printf("Command     : %s\n", proc.Command)
+			fmt.Fprintf(w, "Command     : %s\n", proc.Command)
 		}
 	}
-	// Format as: 2 days ago (Mon 2025-02-02 11:42:10 +0530)
-	startedAt := proc.StartedAt
-	now := time.Now()
-	dur := now.Sub(startedAt)
-	var rel string
-	switch {
-	case dur.Hours() >= 48:
-		days := int(dur.Hours()) / 24
-		rel = fmt.Sprintf("%d days ago", days)
-	case dur.Hours() >= 24:
-		rel = "1 day ago"
-	case dur.Hours() >= 2:
-		hours := int(dur.Hours())
-		rel = fmt.Sprintf("%d hours ago", hours)
-	case dur.Minutes() >= 60:
-		rel = "1 hour ago"
-	default:
-		mins := int(dur.Minutes())
-		if mins > 0 {
-			rel = fmt.Sprintf("%d min ago", mins)
-		} else {
-			rel = "just now"
+	if pkg := r.Package; pkg != nil {
+		switch {
+		case pkg.Name != "" && colorEnabled:
+			fmt.Fprintf(w, "%sPackage%s     : %s\n", colorGreen, colorReset, formatPackage(pkg))
+		case pkg.Name != "":
+			fmt.Fprintf(w, "Package     : %s\n", formatPackage(pkg))
+		case colorEnabled:
+			fmt.Fprintf(w, "%sPackage%s     : %snone (not installed by %s)%s\n", colorGreen, colorReset, colorDimYellow, pkg.Manager, colorReset)
+		default:
+			fmt.Fprintf(w, "Package     : none (not installed by %s)\n", pkg.Manager)
 		}
 	}
-	dtStr := startedAt.Format("Mon 2006-01-02 15:04:05 -07:00")
+	// Format as: 2 days ago (Mon 2025-02-02 11:42:10 +0530, 2 min after boot)
 	if colorEnabled {
-		fmt.Printf("%sStarted%s     : %s (%s)\n", colorMagenta, colorReset, rel, dtStr)
+		fmt.Fprintf(w, "%sStarted%s     : %s\n", colorMagenta, colorReset, formatStarted(proc))
 	} else {
-		fmt.Printf("Started     : %s (%s)\n", rel, dtStr)
+		fmt.Fprintf(w, "Started     : %s\n", formatStarted(proc))
+	}
+
+	if usage := formatUsage(proc); usage != "" {
+		if colorEnabled {
+			fmt.Fprintf(w, "%sResources%s   : %s\n", colorCyan, colorReset, usage)
+		} else {
+			fmt.Fprintf(w, "Resources   : %s\n", usage)
+		}
+	}
+
+	if family := formatFamily(r.Family); family != "" {
+		if colorEnabled {
+			fmt.Fprintf(w, "%sFamily%s      : %s\n", colorCyan, colorReset, family)
+		} else {
+			fmt.Fprintf(w, "Family      : %s\n", family)
+		}
+	}
+
+	if r.GPU != nil {
+		gpu := strings.Join(r.GPU.Devices, ", ")
+		if colorEnabled {
+			fmt.Fprintf(w, "%sGPU%s         : %s\n", colorCyan, colorReset, gpu)
+		} else {
+			fmt.Fprintf(w, "GPU         : %s\n", gpu)
+		}
+		for _, g := range r.GPU.GPUs {
+			fmt.Fprintf(w, "              %s\n", formatGPU(g))
+		}
+	}
+
+	for i, l := range r.Limits {
+		switch {
+		case i > 0:
+			fmt.Fprintf(w, "              %s\n", formatLimit(l))
+		case colorEnabled:
+			fmt.Fprintf(w, "%sRlimits%s     : %s\n", colorCyan, colorReset, formatLimit(l))
+		default:
+			fmt.Fprintf(w, "Rlimits     : %s\n", formatLimit(l))
+		}
 	}
 
 	// Restart count
 	if r.RestartCount > 0 {
 		if colorEnabled {
-			fmt.Printf("%sRestarts%s    : %d\n", colorDimYellow, colorReset, r.RestartCount)
+			fmt.Fprintf(w, "%sRestarts%s    : %d\n", colorDimYellow, colorReset, r.RestartCount)
+		} else {
+			fmt.Fprintf(w, "Restarts    : %d\n", r.RestartCount)
+		}
+	}
+
+	// Descendants are only summarized when there are pathologically many
+	if d := r.Descendants; d != nil {
+		count := formatCount(d.Count)
+		if d.Truncated {
+			count = "over " + count
+		}
+		var top []string
+		for _, c := range d.Top {
+			top = append(top, fmt.Sprintf("%s ×%s", c.Command, formatCount(c.Count)))
+		}
+		if len(top) > 0 {
+			count += " (top: " + strings.Join(top, ", ") + ")"
+		}
+		if colorEnabled {
+			fmt.Fprintf(w, "%sDescendants%s : %s%s%s\n", colorDimYellow, colorReset, colorRed, count, colorReset)
+		} else {
+			fmt.Fprintf(w, "Descendants : %s\n", count)
+		}
+	}
+
+	if len(r.Children) > 0 {
+		if colorEnabled {
+			fmt.Fprintf(w, "\n%sChildren%s    :\n", colorCyan, colorReset)
 		} else {
-			fmt.Printf("Restarts    : %d\n", r.RestartCount)
+			fmt.Fprintln(w, "\nChildren    :")
 		}
+		printChildren(w, r.Children, 1, colorEnabled, verbose)
 	}
 
 	// Why It Exists (short chain)
 	if colorEnabled {
-		fmt.Printf("\n%sWhy It Exists%s :\n  ", colorMagenta, colorReset)
+		fmt.Fprintf(w, "\n%sWhy It Exists%s :\n  ", colorMagenta, colorReset)
 		for i, p := range r.Ancestry {
 			name := p.Command
 			if name == "" && p.Cmdline != "" {
 				name = p.Cmdline
 			}
-			fmt.Printf("%s (%spid %d%s)", name, colorBold, p.PID, colorReset)
+			fmt.Fprintf(w, "%s (%spid %d%s)", name, colorBold, p.PID, colorReset)
+			if p.SkippedAncestors > 0 {
+				fmt.Fprintf(w, " %s%s%s %s%s%s", colorMagenta, glyphs.arrow, colorReset, colorBold, skippedLabel(p.SkippedAncestors), colorReset)
+			}
 			if i < len(r.Ancestry)-1 {
-				fmt.Printf(" %s\u2192%s ", colorMagenta, colorReset)
+				fmt.Fprintf(w, " %s%s%s ", colorMagenta, glyphs.arrow, colorReset)
 			}
 		}
-		fmt.Print("\n\n")
+		fmt.Fprint(w, "\n\n")
 	} else {
-		fmt.Printf("\nWhy It Exists :\n  ")
+		fmt.Fprintf(w, "\nWhy It Exists :\n  ")
 		for i, p := range r.Ancestry {
 			name := p.Command
 			if name == "" && p.Cmdline != "" {
 				name = p.Cmdline
 			}
-			fmt.Printf("%s (pid %d)", name, p.PID)
+			fmt.Fprintf(w, "%s (pid %d)", name, p.PID)
+			if p.SkippedAncestors > 0 {
+				fmt.Fprintf(w, " %s %s", glyphs.arrow, skippedLabel(p.SkippedAncestors))
+			}
 			if i < len(r.Ancestry)-1 {
-				fmt.Printf(" \u2192 ")
+				fmt.Fprintf(w, " %s ", glyphs.arrow)
 			}
 		}
-		fmt.Print("\n\n")
+		fmt.Fprint(w, "\n\n")
+	}
+	if verbose {
+		renderAncestry(w, r.Ancestry, colorEnabled)
 	}
 
 	// Source
 	sourceLabel := string(r.Source.Type)
 	if colorEnabled {
 		if r.Source.Name != "" && r.Source.Name != sourceLabel {
-			fmt.Printf("%sSource%s      : %s (%s)\n", colorCyan, colorReset, r.Source.Name, sourceLabel)
+			fmt.Fprintf(w, "%sSource%s      : %s (%s)\n", colorCyan, colorReset, r.Source.Name, sourceLabel)
 		} else {
-			fmt.Printf("%sSource%s      : %s\n", colorCyan, colorReset, sourceLabel)
+			fmt.Fprintf(w, "%sSource%s      : %s\n", colorCyan, colorReset, sourceLabel)
 		}
 	} else {
 		if r.Source.Name != "" && r.Source.Name != sourceLabel {
-			fmt.Printf("Source      : %s (%s)\n", r.Source.Name, sourceLabel)
+			fmt.Fprintf(w, "Source      : %s (%s)\n", r.Source.Name, sourceLabel)
 		} else {
-			fmt.Printf("Source      : %s\n", sourceLabel)
+			fmt.Fprintf(w, "Source      : %s\n", sourceLabel)
 		}
 	}
 
 	// Source details (launchd triggers, plist path, etc.)
 	if len(r.Source.Details) > 0 {
-		// Display in consistent order
-		detailKeys := []string{"type", "plist", "triggers", "keepalive"}
-		for _, key := range detailKeys {
-			if val, ok := r.Source.Details[key]; ok {
-				label := formatDetailLabel(key)
-				if colorEnabled {
-					fmt.Printf("%s%s%s : %s\n", colorBold, label, colorReset, val)
-				} else {
-					fmt.Printf("%s : %s\n", label, val)
-				}
+		for _, key := range orderedDetailKeys(r.Source.Details) {
+			val := r.Source.Details[key]
+			label := formatDetailLabel(key)
+			if colorEnabled {
+				fmt.Fprintf(w, "%s%s%s : %s\n", colorBold, label, colorReset, val)
+			} else {
+				fmt.Fprintf(w, "%s : %s\n", label, val)
 			}
 		}
 	}
 
+	// Confidence and evidence let users judge low-confidence guesses
+	if r.Source.Confidence > 0 {
+		confidence := fmt.Sprintf("%.0f%% (%s)", r.Source.Confidence*100, confidenceLevel(r.Source.Confidence))
+		if colorEnabled {
+			fmt.Fprintf(w, "%s              Confidence%s : %s\n", colorBold, colorReset, confidence)
+		} else {
+			fmt.Fprintf(w, "              Confidence : %s\n", confidence)
+		}
+	}
+	for i, e := range r.Source.Evidence {
+		label := "              Evidence  "
+		if i > 0 {
+			label = "                        "
+		}
+		if colorEnabled {
+			fmt.Fprintf(w, "%s%s%s : %s\n", colorBold, label, colorReset, e)
+		} else {
+			fmt.Fprintf(w, "%s : %s\n", label, e)
+		}
+	}
+
+	if r.Service != nil {
+		renderServiceUnit(w, r.Service, colorEnabled)
+	}
+	if r.Container != nil {
+		renderContainer(w, r.Container, colorEnabled)
+	}
+
 	// Context group
 	if colorEnabled {
 		if proc.WorkingDir != "" {
-			fmt.Printf("\n%sWorking Dir%s : %s\n", colorGreen, colorReset, proc.WorkingDir)
+			fmt.Fprintf(w, "\n%sWorking Dir%s : %s\n", colorGreen, colorReset, proc.WorkingDir)
+		}
+		if chrooted(proc) {
+			fmt.Fprintf(w, "%sRoot Dir%s    : %s %s(chroot)%s\n", colorGreen, colorReset, proc.Root, colorDimYellow, colorReset)
 		}
 		if proc.GitRepo != "" {
 			if proc.GitBranch != "" {
-				fmt.Printf("%sGit Repo%s    : %s (%s)\n", colorCyan, colorReset, proc.GitRepo, proc.GitBranch)
+				fmt.Fprintf(w, "%sGit Repo%s    : %s (%s)\n", colorCyan, colorReset, proc.GitRepo, proc.GitBranch)
 			} else {
-				fmt.Printf("%sGit Repo%s    : %s\n", colorCyan, colorReset, proc.GitRepo)
+				fmt.Fprintf(w, "%sGit Repo%s    : %s\n", colorCyan, colorReset, proc.GitRepo)
 			}
 		}
 	} else {
 		if proc.WorkingDir != "" {
-			fmt.Printf("\nWorking Dir : %s\n", proc.WorkingDir)
+			fmt.Fprintf(w, "\nWorking Dir : %s\n", proc.WorkingDir)
+		}
+		if chrooted(proc) {
+			fmt.Fprintf(w, "Root Dir    : %s (chroot)\n", proc.Root)
 		}
 		if proc.GitRepo != "" {
 			if proc.GitBranch != "" {
-				fmt.Printf("Git Repo    : %s (%s)\n", proc.GitRepo, proc.GitBranch)
+				fmt.Fprintf(w, "Git Repo    : %s (%s)\n", proc.GitRepo, proc.GitBranch)
 			} else {
-				fmt.Printf("Git Repo    : %s\n", proc.GitRepo)
+				fmt.Fprintf(w, "Git Repo    : %s\n", proc.GitRepo)
 			}
 		}
 	}
 
-	// Listening section (address:port)
-	if len(proc.ListeningPorts) > 0 && len(proc.BindAddresses) == len(proc.ListeningPorts) {
-		for i := range proc.ListeningPorts {
-			addr := proc.BindAddresses[i]
-			port := proc.ListeningPorts[i]
-			if addr != "" && port > 0 {
-				if colorEnabled {
-					if i == 0 {
-						fmt.Printf("%sListening%s   : %s:%d\n", colorGreen, colorReset, addr, port)
-					} else {
-						fmt.Printf("              %s:%d\n", addr, port)
-					}
-				} else {
-					if i == 0 {
-						fmt.Printf("Listening   : %s:%d\n", addr, port)
-					} else {
-						fmt.Printf("              %s:%d\n", addr, port)
-					}
-				}
-			}
+	if verbose {
+		renderEnvSummary(w, proc, colorEnabled)
+	}
+
+	// Listening section (address:port and Unix paths, with protocol)
+	for i, l := range listeningAddrs(proc) {
+		switch {
+		case i > 0:
+			fmt.Fprintf(w, "              %s\n", l)
+		case colorEnabled:
+			fmt.Fprintf(w, "%sListening%s   : %s\n", colorGreen, colorReset, l)
+		default:
+			fmt.Fprintf(w, "Listening   : %s\n", l)
 		}
 	}
 
 	// Socket state (for port queries)
 	if r.SocketInfo != nil {
 		if colorEnabled {
-			fmt.Printf("%sSocket%s      : %s\n", colorCyan, colorReset, r.SocketInfo.State)
+			fmt.Fprintf(w, "%sSocket%s      : %s\n", colorCyan, colorReset, r.SocketInfo.State)
 			if r.SocketInfo.Explanation != "" {
-				fmt.Printf("              %s\n", r.SocketInfo.Explanation)
+				fmt.Fprintf(w, "              %s\n", r.SocketInfo.Explanation)
 			}
 			if r.SocketInfo.Workaround != "" {
-				fmt.Printf("              %s%s%s\n", colorDimYellow, r.SocketInfo.Workaround, colorReset)
+				fmt.Fprintf(w, "              %s%s%s\n", colorDimYellow, r.SocketInfo.Workaround, colorReset)
 			}
 		} else {
-			fmt.Printf("Socket      : %s\n", r.SocketInfo.State)
+			fmt.Fprintf(w, "Socket      : %s\n", r.SocketInfo.State)
 			if r.SocketInfo.Explanation != "" {
-				fmt.Printf("              %s\n", r.SocketInfo.Explanation)
+				fmt.Fprintf(w, "              %s\n", r.SocketInfo.Explanation)
 			}
 			if r.SocketInfo.Workaround != "" {
-				fmt.Printf("              %s\n", r.SocketInfo.Workaround)
+				fmt.Fprintf(w, "              %s\n", r.SocketInfo.Workaround)
+			}
+		}
+	}
+
+	if c := r.Cgroup; c != nil {
+		if colorEnabled {
+			fmt.Fprintf(w, "%sCgroup%s      : %s\n", colorCyan, colorReset, c.Path)
+		} else {
+			fmt.Fprintf(w, "Cgroup      : %s\n", c.Path)
+		}
+		for i, limit := range formatCgroupLimits(c) {
+			switch {
+			case i > 0:
+				fmt.Fprintf(w, "              %s\n", limit)
+			case colorEnabled:
+				fmt.Fprintf(w, "%sLimits%s      : %s\n", colorCyan, colorReset, limit)
+			default:
+				fmt.Fprintf(w, "Limits      : %s\n", limit)
 			}
 		}
 	}
@@ -305,16 +615,16 @@ func RenderStandard(r model.Result, colorEnabled bool) {
 	if r.ResourceContext != nil {
 		if r.ResourceContext.PreventsSleep {
 			if colorEnabled {
-				fmt.Printf("%sEnergy%s      : %sPreventing system sleep%s\n", colorRed, colorReset, colorDimYellow, colorReset)
+				fmt.Fprintf(w, "%sEnergy%s      : %sPreventing system sleep%s\n", colorRed, colorReset, colorDimYellow, colorReset)
 			} else {
-				fmt.Printf("Energy      : Preventing system sleep\n")
+				fmt.Fprintf(w, "Energy      : Preventing system sleep\n")
 			}
 		}
 		if r.ResourceContext.ThermalState != "" {
 			if colorEnabled {
-				fmt.Printf("%sThermal%s     : %s%s%s\n", colorRed, colorReset, colorDimYellow, r.ResourceContext.ThermalState, colorReset)
+				fmt.Fprintf(w, "%sThermal%s     : %s%s%s\n", colorRed, colorReset, colorDimYellow, r.ResourceContext.ThermalState, colorReset)
 			} else {
-				fmt.Printf("Thermal     : %s\n", r.ResourceContext.ThermalState)
+				fmt.Fprintf(w, "Thermal     : %s\n", r.ResourceContext.ThermalState)
 			}
 		}
 	}
@@ -325,41 +635,802 @@ func RenderStandard(r model.Result, colorEnabled bool) {
 			usagePercent := float64(r.FileContext.OpenFiles) / float64(r.FileContext.FileLimit) * 100
 			if colorEnabled {
 				if usagePercent > 80 {
-					fmt.Printf("%sOpen Files%s  : %s%d of %d (%.0f%%)%s\n", colorRed, colorReset, colorDimYellow, r.FileContext.OpenFiles, r.FileContext.FileLimit, usagePercent, colorReset)
+					fmt.Fprintf(w, "%sOpen Files%s  : %s%d of %d (%.0f%%)%s\n", colorRed, colorReset, colorDimYellow, r.FileContext.OpenFiles, r.FileContext.FileLimit, usagePercent, colorReset)
 				} else {
-					fmt.Printf("%sOpen Files%s  : %d of %d (%.0f%%)\n", colorCyan, colorReset, r.FileContext.OpenFiles, r.FileContext.FileLimit, usagePercent)
+					fmt.Fprintf(w, "%sOpen Files%s  : %d of %d (%.0f%%)\n", colorCyan, colorReset, r.FileContext.OpenFiles, r.FileContext.FileLimit, usagePercent)
 				}
 			} else {
-				fmt.Printf("Open Files  : %d of %d (%.0f%%)\n", r.FileContext.OpenFiles, r.FileContext.FileLimit, usagePercent)
+				fmt.Fprintf(w, "Open Files  : %d of %d (%.0f%%)\n", r.FileContext.OpenFiles, r.FileContext.FileLimit, usagePercent)
 			}
 		}
 		if len(r.FileContext.LockedFiles) > 0 {
 			if colorEnabled {
-				fmt.Printf("%sLocks%s       : %s\n", colorCyan, colorReset, r.FileContext.LockedFiles[0])
+				fmt.Fprintf(w, "%sLocks%s       : %s\n", colorCyan, colorReset, r.FileContext.LockedFiles[0])
 				for _, f := range r.FileContext.LockedFiles[1:] {
-					fmt.Printf("              %s\n", f)
+					fmt.Fprintf(w, "              %s\n", f)
 				}
 			} else {
-				fmt.Printf("Locks       : %s\n", r.FileContext.LockedFiles[0])
+				fmt.Fprintf(w, "Locks       : %s\n", r.FileContext.LockedFiles[0])
 				for _, f := range r.FileContext.LockedFiles[1:] {
-					fmt.Printf("              %s\n", f)
+					fmt.Fprintf(w, "              %s\n", f)
 				}
 			}
 		}
 	}
 
+	if r.FDs != nil {
+		renderFDs(w, r.FDs, colorEnabled)
+	}
+
+	// Metadata added by wrapper tools
+	if len(r.Metadata) > 0 {
+		keys := make([]string, 0, len(r.Metadata))
+		for k := range r.Metadata {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		if colorEnabled {
+			fmt.Fprintf(w, "\n%sMetadata%s    :\n", colorCyan, colorReset)
+		} else {
+			fmt.Fprintln(w, "\nMetadata    :")
+		}
+		for _, k := range keys {
+			fmt.Fprintf(w, "  %s: %s\n", k, r.Metadata[k])
+		}
+	}
+
+	if r.Launch != nil {
+		renderLaunch(w, r.Launch, colorEnabled)
+	}
+
+	if r.Logs != nil {
+		if colorEnabled {
+			fmt.Fprintf(w, "\n%sLogs%s        : %s%s%s\n", colorCyan, colorReset, colorBold, r.Logs.Command, colorReset)
+		} else {
+			fmt.Fprintf(w, "\nLogs        : %s\n", r.Logs.Command)
+		}
+		if len(r.Logs.Lines) == 0 {
+			fmt.Fprintln(w, "  (no entries)")
+		}
+		for _, line := range r.Logs.Lines {
+			fmt.Fprintf(w, "  %s\n", line)
+		}
+	}
+
+	if len(r.Connections) > 0 {
+		if colorEnabled {
+			fmt.Fprintf(w, "\n%sConnections%s :\n", colorCyan, colorReset)
+		} else {
+			fmt.Fprintln(w, "\nConnections :")
+		}
+		for _, c := range r.Connections {
+			if c.Direction == "in" {
+				fmt.Fprintf(w, "  in  from %s to port %d (%s)", c.RemoteAddr, c.LocalPort, c.Protocol)
+			} else {
+				fmt.Fprintf(w, "  out to   %s:%d (%s)", c.RemoteAddr, c.RemotePort, c.Protocol)
+			}
+			if c.Count > 1 {
+				fmt.Fprintf(w, " x%d", c.Count)
+			}
+			fmt.Fprintln(w)
+		}
+	}
+
+	if r.Integrity != nil {
+		renderIntegrity(w, r.Integrity, colorEnabled)
+	}
+
+	if r.Triage != nil {
+		renderTriage(w, r.Triage, colorEnabled)
+	}
+
 	// Warnings
 	if len(r.Warnings) > 0 {
 		if colorEnabled {
-			fmt.Printf("\n%sWarnings%s    :\n", colorRed, colorReset)
-			for _, w := range r.Warnings {
-				fmt.Printf("  • %s\n", w)
+			fmt.Fprintf(w, "\n%sWarnings%s    :\n", colorRed, colorReset)
+			for _, warning := range r.Warnings {
+				fmt.Fprintf(w, "  • %s\n", warning)
 			}
 		} else {
-			fmt.Println("\nWarnings    :")
-			for _, w := range r.Warnings {
-				fmt.Printf("  • %s\n", w)
+			fmt.Fprintln(w, "\nWarnings    :")
+			for _, warning := range r.Warnings {
+				fmt.Fprintf(w, "  • %s\n", warning)
+			}
+		}
+	}
+}
+
+// renderServiceUnit prints the Service Details section of a systemd unit
+func renderServiceUnit(w io.Writer, s *model.ServiceUnit, colorEnabled bool) {
+	state := s.ActiveState
+	if s.SubState != "" {
+		state += "/" + s.SubState
+	}
+	if s.ActiveSince != "" {
+		state += " since " + s.ActiveSince
+	}
+	if colorEnabled {
+		fmt.Fprintf(w, "\n%sService Details%s :\n", colorCyan, colorReset)
+	} else {
+		fmt.Fprintln(w, "\nService Details :")
+	}
+	line := func(label, value string) {
+		if value != "" {
+			fmt.Fprintf(w, "  %-10s: %s\n", label, value)
+		}
+	}
+	line("Unit", s.Unit)
+	line("State", state)
+	line("ExecStart", s.ExecStart)
+	restart := s.Restart
+	switch {
+	case s.NRestarts == 1:
+		restart += " (restarted once)"
+	case s.NRestarts > 1:
+		restart += fmt.Sprintf(" (restarted %d times)", s.NRestarts)
+	}
+	line("Restart", restart)
+	line("Unit File", s.FragmentPath)
+	for i, d := range s.DropIns {
+		label := ""
+		if i == 0 {
+			label = "Drop-ins"
+		}
+		entry := d.Path
+		if len(d.Settings) > 0 {
+			entry += " (" + strings.Join(d.Settings, ", ") + ")"
+		}
+		fmt.Fprintf(w, "  %-10s: %s\n", label, entry)
+	}
+}
+
+// renderContainer prints the Container Details section: image, published
+// ports, mounts and the container's own labels
+func renderContainer(w io.Writer, c *model.Container, colorEnabled bool) {
+	if colorEnabled {
+		fmt.Fprintf(w, "\n%sContainer Details%s :\n", colorCyan, colorReset)
+	} else {
+		fmt.Fprintln(w, "\nContainer Details :")
+	}
+	list := func(label string, entries []string) {
+		for i, e := range entries {
+			if i > 0 {
+				label = ""
 			}
+			fmt.Fprintf(w, "  %-10s: %s\n", label, e)
 		}
 	}
+	name := c.Name
+	if len(c.ID) >= 12 {
+		name += " (" + c.ID[:12] + ", " + c.Runtime + ")"
+	}
+	list("Name", []string{name})
+	if c.Image != "" {
+		image := c.Image
+		if c.Digest != "" {
+			image += " @ " + c.Digest
+		}
+		list("Image", []string{image})
+	}
+	list("Ports", containerPorts(c))
+	list("Mounts", containerMounts(c))
+	var labels []string
+	for key, val := range c.Labels {
+		labels = append(labels, key+"="+val)
+	}
+	sort.Strings(labels)
+	list("Labels", labels)
+}
+
+// containerPorts lists published ports as host -> container, and exposed
+// ports that are not published
+func containerPorts(c *model.Container) []string {
+	var ports []string
+	for _, p := range c.Ports {
+		if p.HostPort == "" {
+			ports = append(ports, p.ContainerPort+" (not published)")
+			continue
+		}
+		host := p.HostIP
+		if host == "" {
+			host = "0.0.0.0"
+		}
+		ports = append(ports, net.JoinHostPort(host, p.HostPort)+" -> "+p.ContainerPort)
+	}
+	return ports
+}
+
+// containerMounts lists mounts as source -> destination (type), naming
+// volumes rather than their host paths
+func containerMounts(c *model.Container) []string {
+	var mounts []string
+	for _, m := range c.Mounts {
+		src := m.Source
+		if m.Name != "" {
+			src = m.Name
+		}
+		entry := m.Destination + " (" + m.Type
+		if src != "" {
+			entry = src + " -> " + entry
+		}
+		if m.ReadOnly {
+			entry += ", read-only"
+		}
+		mounts = append(mounts, entry+")")
+	}
+	return mounts
+}
+
+// fdTypeOrder lists descriptor types in the order the summary prints them
+var fdTypeOrder = []struct{ key, one, many string }{
+	{"file", "file", "files"}, {"socket", "socket", "sockets"}, {"pipe", "pipe", "pipes"},
+	{"directory", "directory", "directories"}, {"device", "device", "devices"}, {"anon", "anon inode", "anon inodes"},
+}
+
+// renderFDs prints the Open FDs section: usage against the limit, counts by
+// type and the largest open files
+func renderFDs(w io.Writer, s *model.FDSummary, colorEnabled bool) {
+	usage := strconv.Itoa(s.Open)
+	near := false
+	if s.Limit > 0 {
+		usage = fmt.Sprintf("%d of %d (%.0f%%)", s.Open, s.Limit, float64(s.Open)/float64(s.Limit)*100)
+		near = s.Open*10 >= s.Limit*9
+	}
+	switch {
+	case colorEnabled && near:
+		fmt.Fprintf(w, "\n%sOpen FDs%s    : %s%s%s\n", colorRed, colorReset, colorDimYellow, usage, colorReset)
+	case colorEnabled:
+		fmt.Fprintf(w, "\n%sOpen FDs%s    : %s\n", colorCyan, colorReset, usage)
+	default:
+		fmt.Fprintf(w, "\nOpen FDs    : %s\n", usage)
+	}
+	var counts []string
+	for _, t := range fdTypeOrder {
+		switch n := s.Counts[t.key]; {
+		case n == 1:
+			counts = append(counts, "1 "+t.one)
+		case n > 1:
+			counts = append(counts, fmt.Sprintf("%d %s", n, t.many))
+		}
+	}
+	if len(counts) > 0 {
+		fmt.Fprintf(w, "  %-10s: %s\n", "Types", strings.Join(counts, ", "))
+	}
+	for i, f := range s.Largest {
+		label := ""
+		if i == 0 {
+			label = "Largest"
+		}
+		note := "fd " + strconv.Itoa(f.FD)
+		if f.Deleted {
+			note += ", deleted"
+		}
+		fmt.Fprintf(w, "  %-10s: %s %s (%s)\n", label, formatBytes(uint64(f.Size)), f.Path, note)
+	}
+}
+
+// renderLaunch prints the launch record reconstructed from the audit log
+func renderLaunch(w io.Writer, l *model.LaunchRecord, colorEnabled bool) {
+	if colorEnabled {
+		fmt.Fprintf(w, "\n%sLaunch%s      : %s(from audit log)%s\n", colorCyan, colorReset, colorBold, colorReset)
+	} else {
+		fmt.Fprintln(w, "\nLaunch      : (from audit log)")
+	}
+	line := func(label, value string) {
+		if value != "" {
+			fmt.Fprintf(w, "  %-10s: %s\n", label, value)
+		}
+	}
+	line("Time", l.Time.Local().Format("2006-01-02 15:04:05 -07:00"))
+	args := make([]string, len(l.Argv))
+	for i, a := range l.Argv {
+		if a == "" || strings.ContainsAny(a, " \t'\"") {
+			a = strconv.Quote(a)
+		}
+		args[i] = a
+	}
+	line("Argv", strings.Join(args, " "))
+	line("Exe", l.Exe)
+	line("Cwd", l.Cwd)
+	line("Login", l.LoginUser)
+	line("UID", l.UID)
+	tty := l.TTY
+	if l.Session != "" {
+		if tty != "" {
+			tty += ", "
+		}
+		tty += "session " + l.Session
+	}
+	line("TTY", tty)
+	if l.PPID > 0 {
+		line("Parent", "pid "+strconv.Itoa(l.PPID))
+	}
+}
+
+// renderIntegrity prints the security section: kernel integrity state and
+// signature verification of the executable
+func renderIntegrity(w io.Writer, in *model.Integrity, colorEnabled bool) {
+	if colorEnabled {
+		fmt.Fprintf(w, "\n%sSecurity%s    :\n", colorCyan, colorReset)
+	} else {
+		fmt.Fprintln(w, "\nSecurity    :")
+	}
+	line := func(label, value string) {
+		if value != "" {
+			fmt.Fprintf(w, "  %-10s: %s\n", label, value)
+		}
+	}
+	ima := in.IMA
+	if ima != "" {
+		ima = "measured " + ima
+	}
+	if in.IMAAppraisal != "" {
+		if ima != "" {
+			ima += ", "
+		}
+		ima += "appraisal " + in.IMAAppraisal
+	}
+	line("IMA", ima)
+	line("EVM", in.EVM)
+	line("fs-verity", in.FSVerity)
+	if in.Signature != "" && !in.SignatureOK && colorEnabled {
+		fmt.Fprintf(w, "  %-10s: %s%s%s\n", "Signature", colorRed, in.Signature, colorReset)
+	} else {
+		line("Signature", in.Signature)
+	}
+}
+
+// renderTriage prints the raw evidence collected by --investigate
+func renderTriage(w io.Writer, t *model.Triage, colorEnabled bool) {
+	if colorEnabled {
+		fmt.Fprintf(w, "\n%sTriage%s      :\n", colorCyan, colorReset)
+	} else {
+		fmt.Fprintln(w, "\nTriage      :")
+	}
+	list := func(label string, values []string) {
+		if len(values) == 0 {
+			return
+		}
+		fmt.Fprintf(w, "  %-10s: %s\n", label, values[0])
+		for _, v := range values[1:] {
+			fmt.Fprintf(w, "              %s\n", v)
+		}
+	}
+	if !t.StartedAt.IsZero() {
+		fmt.Fprintf(w, "  %-10s: %s\n", "Started", t.StartedAt.Format("2006-01-02 15:04:05 -07:00"))
+	}
+	tty := t.TTY
+	if tty == "" {
+		tty = "none"
+	}
+	fmt.Fprintf(w, "  %-10s: %s\n", "TTY", tty)
+	if len(t.Session) > 0 {
+		keys := make([]string, 0, len(t.Session))
+		for k := range t.Session {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var parts []string
+		for _, k := range keys {
+			parts = append(parts, k+"="+t.Session[k])
+		}
+		fmt.Fprintf(w, "  %-10s: %s\n", "Session", strings.Join(parts, " "))
+	}
+	list("Cgroup", t.Cgroup)
+	list("Env", t.EnvHints)
+	list("FDs", t.FDs)
+}
+
+// formatGPU shows the use of one GPU, e.g. "GPU 0: 35% SM, 2048 MiB (compute)"
+func formatGPU(g model.GPUProcess) string {
+	var parts []string
+	if g.SMPercent != nil {
+		parts = append(parts, strconv.Itoa(*g.SMPercent)+"% SM")
+	}
+	if g.MemoryMiB != nil {
+		parts = append(parts, strconv.Itoa(*g.MemoryMiB)+" MiB")
+	}
+	out := "GPU " + strconv.Itoa(g.Index) + ": " + strings.Join(parts, ", ")
+	if len(parts) == 0 {
+		out += "in use"
+	}
+	if g.Type != "" {
+		out += " (" + g.Type + ")"
+	}
+	return out
+}
+
+// formatLimit shows a resource limit with its usage, e.g.
+// "nofile 14 of 1024 (hard 524288)" or "core unlimited"
+func formatLimit(l model.ResourceLimit) string {
+	value := func(n int64) string {
+		switch {
+		case n == model.Unlimited:
+			return "unlimited"
+		case l.Name == "memlock" || l.Name == "core":
+			return formatBytes(uint64(n))
+		}
+		return strconv.FormatInt(n, 10)
+	}
+	out := l.Name + " " + value(l.Soft)
+	if l.Usage != nil {
+		out = l.Name + " " + value(*l.Usage) + " of " + value(l.Soft)
+	}
+	if l.Hard != l.Soft {
+		out += " (hard " + value(l.Hard) + ")"
+	}
+	return out
+}
+
+// formatFamily summarizes threads and the processes below, e.g.
+// "4 threads, 3 children (12 descendants), 1.2 GB RSS and 52 threads in total",
+// or returns "" for a single-threaded process without children
+func formatFamily(f *model.Family) string {
+	if f == nil || (f.Threads <= 1 && f.Descendants == 0) {
+		return ""
+	}
+	plural := func(n int, one, many string) string {
+		if n == 1 {
+			return "1 " + one
+		}
+		return strconv.Itoa(n) + " " + many
+	}
+	var parts []string
+	if f.Threads > 0 {
+		parts = append(parts, plural(f.Threads, "thread", "threads"))
+	}
+	if f.Descendants == 0 {
+		return strings.Join(append(parts, "no children"), ", ")
+	}
+	children := plural(f.Children, "child", "children")
+	if f.Descendants > f.Children {
+		more := ""
+		if f.Truncated {
+			more = "+"
+		}
+		children += " (" + strconv.Itoa(f.Descendants) + more + " descendants)"
+	}
+	total := formatBytes(f.SubtreeRSS) + " RSS"
+	if f.SubtreeThreads > 0 {
+		total += " and " + plural(f.SubtreeThreads, "thread", "threads")
+	}
+	return strings.Join(append(parts, children, total+" in total"), ", ")
+}
+
+// formatUser describes the user a process runs as and how it got there, or
+// "" when unknown
+func formatUser(p model.Process) string {
+	if p.User == "" || p.User == "unknown" {
+		return ""
+	}
+	user := p.User
+	switch {
+	case p.LaunchUser == "root" && !p.Setuid:
+		user += " (started as root, dropped privileges)"
+	case p.LaunchUser != "" && p.Setuid:
+		user += " (setuid binary, launched by " + p.LaunchUser + ")"
+	case p.LaunchUser != "":
+		user += " (launched by " + p.LaunchUser + ")"
+	case p.Setuid:
+		user += " (setuid binary)"
+	}
+	return user
+}
+
+// formatStarted gives the start time relative to now and to boot
+func formatStarted(p model.Process) string {
+	rel := "just now"
+	if d := formatDuration(time.Since(p.StartedAt)); d != "" {
+		rel = d + " ago"
+	}
+	dtStr := p.StartedAt.Format("Mon 2006-01-02 15:04:05 -07:00")
+	if p.SinceBoot > 0 {
+		if d := formatDuration(p.SinceBoot); d != "" {
+			dtStr += ", " + d + " after boot"
+		} else {
+			dtStr += ", within a minute of boot"
+		}
+	}
+	return rel + " (" + dtStr + ")"
+}
+
+// listeningAddrs lists the addresses and socket paths a process listens on
+func listeningAddrs(p model.Process) []string {
+	var listening []string
+	if len(p.Sockets) > 0 {
+		for _, s := range p.Sockets {
+			switch {
+			case s.Path != "":
+				listening = append(listening, s.Path+" ("+s.Protocol+")")
+			case s.Port > 0:
+				listening = append(listening, fmt.Sprintf("%s:%d (%s)", s.Address, s.Port, s.Protocol))
+			}
+		}
+	} else if len(p.ListeningPorts) > 0 && len(p.BindAddresses) == len(p.ListeningPorts) {
+		for i, port := range p.ListeningPorts {
+			if addr := p.BindAddresses[i]; addr != "" && port > 0 {
+				listening = append(listening, fmt.Sprintf("%s:%d", addr, port))
+			}
+		}
+	}
+	return listening
+}
+
+// formatUsage summarizes CPU and memory use, e.g.
+// "2.5% CPU, 48.2 MB RSS (0.3% of memory), 1m12s CPU time"
+func formatUsage(p model.Process) string {
+	var parts []string
+	if p.CPUPercent != nil {
+		parts = append(parts, fmt.Sprintf("%.1f%% CPU", *p.CPUPercent))
+	}
+	if p.RSSBytes > 0 {
+		rss := formatBytes(p.RSSBytes) + " RSS"
+		if p.MemPercent > 0 {
+			rss += fmt.Sprintf(" (%.1f%% of memory)", p.MemPercent)
+		}
+		parts = append(parts, rss)
+	}
+	if p.CPUTime > 0 {
+		total := "<1s"
+		if d := p.CPUTime.Round(time.Second); d > 0 {
+			total = d.String()
+		}
+		parts = append(parts, total+" CPU time")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatPackage renders the owner of the executable, e.g.
+// "nginx-core 1.24.0-1 (dpkg)"
+func formatPackage(pkg *model.PackageOwner) string {
+	desc := pkg.Name
+	if pkg.Version != "" {
+		desc += " " + pkg.Version
+	}
+	return desc + " (" + pkg.Manager + ")"
+}
+
+// formatCgroupLimits describes the memory and CPU limits of a cgroup, one
+// per line, naming the cgroup that sets each when it is an enclosing slice
+func formatCgroupLimits(c *model.CgroupInfo) []string {
+	setBy := func(dir string) string {
+		if dir == c.Path {
+			return ""
+		}
+		return ", set by " + dir
+	}
+	var limits []string
+	if c.MemoryMax > 0 {
+		limits = append(limits, fmt.Sprintf("memory %s (%s used, %.0f%%%s)", formatBytes(c.MemoryMax),
+			formatBytes(c.MemoryCurrent), float64(c.MemoryCurrent)/float64(c.MemoryMax)*100, setBy(c.MemoryLimitedBy)))
+	}
+	if c.CPUQuota > 0 {
+		cpu := fmt.Sprintf("cpu %.2g CPUs", c.CPUQuota)
+		if c.Periods > 0 {
+			cpu += fmt.Sprintf(" (throttled in %d of %d periods, %s total", c.Throttled, c.Periods, c.ThrottledTime.Round(time.Millisecond))
+			cpu += setBy(c.CPULimitedBy) + ")"
+		} else if by := setBy(c.CPULimitedBy); by != "" {
+			cpu += " (" + by[2:] + ")"
+		}
+		limits = append(limits, cpu)
+	}
+	if c.OOMKills > 0 {
+		limits = append(limits, fmt.Sprintf("%d OOM kills", c.OOMKills))
+	}
+	return limits
+}
+
+// formatBytes renders a byte count with a binary unit, e.g. "48.2 MB"
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	value, suffix := float64(n)/unit, "KB"
+	for _, s := range []string{"MB", "GB", "TB"} {
+		if value < unit {
+			break
+		}
+		value, suffix = value/unit, s
+	}
+	return fmt.Sprintf("%.1f %s", value, suffix)
+}
+
+// formatDuration renders a duration coarsely, e.g. "3 days", "1 hour" or
+// "12 min", and returns "" for less than a minute
+func formatDuration(d time.Duration) string {
+	switch {
+	case d.Hours() >= 48:
+		return fmt.Sprintf("%d days", int(d.Hours())/24)
+	case d.Hours() >= 24:
+		return "1 day"
+	case d.Hours() >= 2:
+		return fmt.Sprintf("%d hours", int(d.Hours()))
+	case d.Minutes() >= 60:
+		return "1 hour"
+	case d.Minutes() >= 1:
+		return fmt.Sprintf("%d min", int(d.Minutes()))
+	}
+	return ""
+}
+
+// formatIdentity renders credentials like id(1), adding effective and saved
+// IDs only when they differ from the real ones
+func formatIdentity(id *model.Identity) string {
+	named := func(n int, name string) string {
+		if name == "" || name == strconv.Itoa(n) {
+			return strconv.Itoa(n)
+		}
+		return strconv.Itoa(n) + "(" + name + ")"
+	}
+	out := "uid=" + named(id.RealUID, id.UserName) + " gid=" + named(id.RealGID, id.GroupName)
+	if id.EffectiveUID != id.RealUID {
+		out += " euid=" + strconv.Itoa(id.EffectiveUID)
+	}
+	if id.EffectiveGID != id.RealGID {
+		out += " egid=" + strconv.Itoa(id.EffectiveGID)
+	}
+	if id.SavedUID != id.EffectiveUID {
+		out += " suid=" + strconv.Itoa(id.SavedUID)
+	}
+	if id.SavedGID != id.EffectiveGID {
+		out += " sgid=" + strconv.Itoa(id.SavedGID)
+	}
+	if len(id.Groups) > 0 {
+		groups := make([]string, len(id.Groups))
+		for i, g := range id.Groups {
+			groups[i] = named(g.ID, g.Name)
+		}
+		out += " groups=" + strings.Join(groups, ",")
+	}
+	return out
+}
+
+// chrooted reports whether a process runs with a root directory other than
+// the host's, as under chroot or systemd RootDirectory=
+func chrooted(p model.Process) bool {
+	return p.Root != "" && p.Root != "/"
+}
+
+// formatScheduling describes niceness, policy and CPU affinity, e.g.
+// "nice 0, SCHED_FIFO priority 50, CPUs 0-1 (pinned)"
+func formatScheduling(s *model.Scheduling) string {
+	out := "nice " + strconv.Itoa(s.Nice) + ", " + s.Policy
+	if s.Realtime() {
+		out += " priority " + strconv.Itoa(s.RTPriority)
+	}
+	if s.CPUs != "" {
+		out += ", CPUs " + s.CPUs
+		if s.Pinned {
+			out += " (pinned)"
+		}
+	}
+	return out
+}
+
+// formatOOMScore shows the OOM killer score and its adjustment
+func formatOOMScore(s *model.Scheduling) string {
+	out := strconv.Itoa(s.OOMScore) + " (adj " + strconv.Itoa(s.OOMScoreAdj)
+	switch {
+	case s.OOMScoreAdj == -1000:
+		out += ", never killed"
+	case s.OOMScoreAdj == 1000:
+		out += ", killed first"
+	}
+	return out + ")"
+}
+
+// formatNamespaces names the namespaces a process does not share with init,
+// or returns "" when it shares all of them
+func formatNamespaces(p model.Process) string {
+	var isolated []string
+	for _, ns := range p.Namespaces {
+		if ns.Isolated {
+			isolated = append(isolated, ns.Type)
+		}
+	}
+	if len(isolated) == 0 {
+		return ""
+	}
+	desc := strings.Join(isolated, ", ") + " differ from init's"
+	switch {
+	case p.Container != "":
+	case len(isolated) == 1 && isolated[0] == "net":
+		desc += " (in a separate network namespace)"
+	default:
+		desc += " (isolated like a container, though no runtime was recognized)"
+	}
+	return desc
+}
+
+// formatSecurity describes the AppArmor or SELinux confinement of a process
+func formatSecurity(sec *model.SecurityLabel) string {
+	if sec.LSM == "selinux" {
+		desc := "SELinux " + sec.Label + " (" + sec.Mode + ")"
+		if !sec.Confined {
+			desc += ", unconfined"
+		}
+		return desc
+	}
+	switch sec.Mode {
+	case "unconfined":
+		if sec.Profile == "unconfined" {
+			return "AppArmor unconfined (no profile)"
+		}
+		return "AppArmor profile " + sec.Profile + " (unconfined mode)"
+	case "":
+		return "AppArmor profile " + sec.Profile
+	}
+	return "AppArmor profile " + sec.Profile + " (" + sec.Mode + ")"
+}
+
+// formatLogin names the login session behind a process, e.g.
+// "alice (uid 1000), session 12 via sshd from 10.0.0.5 on pts/0"
+func formatLogin(l *model.LoginSession, p model.Process) string {
+	out := l.LoginUser + " (uid " + strconv.Itoa(l.LoginUID) + "), session " + l.ID
+	if l.Service != "" {
+		out += " via " + l.Service
+	}
+	if l.RemoteHost != "" {
+		out += " from " + l.RemoteHost
+	}
+	if l.TTY != "" {
+		out += " on " + l.TTY
+	}
+	if l.Leader > 0 {
+		out += ", leader pid " + strconv.Itoa(l.Leader)
+	}
+	if l.State == "closing" {
+		// The user logged out but processes of the session are still running
+		out += " (logged out)"
+	}
+	if p.PPID == 1 {
+		out += "; re-parented to pid 1"
+	}
+	return out
+}
+
+// formatSeccomp describes the seccomp mode and no_new_privs, e.g.
+// "filter (2 filters), no_new_privs"
+func formatSeccomp(id *model.Identity) string {
+	out := id.Seccomp
+	switch {
+	case out == "":
+		return ""
+	case id.SeccompFilters == 1:
+		out += " (1 filter)"
+	case id.SeccompFilters > 1:
+		out += " (" + strconv.Itoa(id.SeccompFilters) + " filters)"
+	}
+	if id.NoNewPrivs {
+		out += ", no_new_privs"
+	}
+	return out
+}
+
+// formatCapabilities lists effective capabilities without their CAP_ prefix.
+// Nearly complete sets, as root in a container holds, list what is missing.
+func formatCapabilities(id *model.Identity) string {
+	if id.AllCapabilities {
+		return "all"
+	}
+	short := func(c string) string { return strings.ToLower(strings.TrimPrefix(c, "CAP_")) }
+	if len(id.Capabilities) > len(model.CapabilityNames)/2 {
+		held := make(map[string]bool)
+		for _, c := range id.Capabilities {
+			held[c] = true
+		}
+		var missing []string
+		for _, c := range model.CapabilityNames {
+			if !held[c] {
+				missing = append(missing, short(c))
+			}
+		}
+		return "all except " + strings.Join(missing, ", ")
+	}
+	caps := make([]string, len(id.Capabilities))
+	for i, c := range id.Capabilities {
+		caps[i] = short(c)
+	}
+	return strings.Join(caps, ", ")
 }
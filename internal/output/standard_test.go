@@ -0,0 +1,25 @@
+package output
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestRenderStandardLimits(t *testing.T) {
+	usage := int64(900)
+	var b strings.Builder
+	RenderStandard(&b, model.Result{
+		Process:  model.Process{PID: 42, Command: "nginx"},
+		Ancestry: []model.Process{{PID: 1, Command: "systemd"}, {PID: 42, Command: "nginx"}},
+		Cgroup:   &model.CgroupInfo{Path: "/system.slice/nginx.service", MemoryMax: 1 << 30, MemoryCurrent: 1 << 29, MemoryLimitedBy: "/system.slice/nginx.service"},
+		Limits:   []model.ResourceLimit{{Name: "nofile", Soft: 1024, Hard: 4096, Usage: &usage}},
+	}, false)
+	out := b.String()
+	for _, want := range []string{"\nRlimits     : nofile 900 of 1024", "\nLimits      : memory 1.0 GB (512.0 MB used, 50%)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output is missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package output
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// templatePrefix marks a format that is a Go template over model.Result
+const templatePrefix = "template:"
+
+// TemplateFormat returns the format that renders each result with the Go
+// template text, e.g. "{{.Process.PID}} {{.Source.Type}}"
+func TemplateFormat(text string) string {
+	return templatePrefix + text
+}
+
+// ParseTemplate parses a --format template. Besides the builtins, templates
+// can use json (compact JSON of a value) and join (strings.Join).
+func ParseTemplate(text string) (*template.Template, error) {
+	return template.New("format").
+		Option("missingkey=zero").
+		Funcs(template.FuncMap{
+			"json": func(v any) (string, error) {
+				data, err := json.Marshal(v)
+				return string(data), err
+			},
+			"join": strings.Join,
+		}).
+		Parse(text)
+}
+
+// templateRenderer executes a template for each result, ending each with a
+// newline unless the template already does
+type templateRenderer struct {
+	w    io.Writer
+	tmpl *template.Template
+}
+
+func (t *templateRenderer) Begin() error { return nil }
+
+func (t *templateRenderer) Emit(r model.Result) error {
+	var b strings.Builder
+	if err := t.tmpl.Execute(&b, forJSON(r)); err != nil {
+		return err
+	}
+	if !strings.HasSuffix(b.String(), "\n") {
+		b.WriteByte('\n')
+	}
+	_, err := io.WriteString(t.w, b.String())
+	return err
+}
+
+func (t *templateRenderer) End() error { return nil }
@@ -0,0 +1,29 @@
+package output
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestTemplateRenderer(t *testing.T) {
+	var b strings.Builder
+	r, err := NewRenderer(TemplateFormat(`{{.Process.PID}} {{.Source.Type}} {{.Source.Details.unit}}[{{join .Warnings ","}}]`), &b, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Emit(model.Result{
+		Process:  model.Process{PID: 42},
+		Source:   model.Source{Type: model.SourceSystemd, Details: map[string]string{"manager": "system"}},
+		Warnings: []string{"a", "b"},
+	})
+	r.Emit(model.Result{Process: model.Process{PID: 7}})
+	want := "42 systemd [a,b]\n7  []\n"
+	if b.String() != want {
+		t.Errorf("output = %q, want %q", b.String(), want)
+	}
+	if _, err := NewRenderer(TemplateFormat("{{.Process.PID"), &b, false); err == nil {
+		t.Error("unterminated template parsed")
+	}
+}
@@ -0,0 +1,62 @@
+package output
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Theme is the escape code the human-readable renderers use for each of
+// their colors. Bold is the faint style used for PIDs and other secondary
+// text, and DimYellow for notes.
+type Theme struct {
+	Red, Green, Blue, Cyan, Magenta, Bold, DimYellow string
+}
+
+// Themes accepted by SetTheme
+var themes = map[string]Theme{
+	// The original palette, for dark backgrounds
+	"default": {
+		Red: "\033[31m", Green: "\033[32m", Blue: "\033[34m", Cyan: "\033[36m",
+		Magenta: "\033[35m", Bold: "\033[2m", DimYellow: "\033[2;33m",
+	},
+	// Avoids cyan, yellow and faint text, which wash out on white
+	"light": {
+		Red: "\033[31m", Green: "\033[32m", Blue: "\033[34m", Cyan: "\033[34m",
+		Magenta: "\033[35m", Bold: "\033[90m", DimYellow: "\033[33m",
+	},
+	// No colors: labels are bold and warnings bold and underlined
+	"monochrome-bold": {
+		Red: "\033[1;4m", Green: "\033[1m", Blue: "\033[1m", Cyan: "\033[1m",
+		Magenta: "", Bold: "", DimYellow: "\033[3m",
+	},
+	// Bright, bold colors and no faint text, for low vision or glare
+	"high-contrast": {
+		Red: "\033[1;91m", Green: "\033[1;92m", Blue: "\033[1;94m", Cyan: "\033[1;96m",
+		Magenta: "\033[1;95m", Bold: "\033[1m", DimYellow: "\033[1;93m",
+	},
+}
+
+// ThemeNames lists the themes SetTheme accepts, sorted
+func ThemeNames() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// SetTheme switches the colors of all human-readable output to a named
+// theme. It only matters when color is enabled.
+func SetTheme(name string) error {
+	t, ok := themes[name]
+	if !ok {
+		return fmt.Errorf("unknown theme %q (want %s)", name, strings.Join(ThemeNames(), ", "))
+	}
+	colorRed, colorGreen, colorBlue, colorCyan = t.Red, t.Green, t.Blue, t.Cyan
+	colorMagenta, colorBold, colorDimYellow = t.Magenta, t.Bold, t.DimYellow
+	colorMagentaTree, colorBoldTree = t.Magenta, t.Bold
+	colorMagentaShort, colorBoldShort = t.Magenta, t.Bold
+	return nil
+}
@@ -2,6 +2,8 @@ package output
 
 import (
 	"fmt"
+	"io"
+	"strings"
 
 	"github.com/pranshuparmar/witr/pkg/model"
 )
@@ -12,7 +14,55 @@ var (
 	colorBoldTree    = "\033[2m"
 )
 
-func PrintTree(chain []model.Process, colorEnabled bool) {
+// treeGlyphs are the characters trees and ancestry chains are drawn with
+type treeGlyphs struct {
+	last, branch, vertical, horizontal, arrow, ellipsis string
+}
+
+var (
+	unicodeGlyphs = treeGlyphs{last: "└", branch: "├", vertical: "│", horizontal: "─", arrow: "→", ellipsis: "…"}
+	asciiGlyphs   = treeGlyphs{last: "`", branch: "+", vertical: "|", horizontal: "-", arrow: "->", ellipsis: "..."}
+)
+
+// Tree style set by SetTreeStyle
+var (
+	glyphs     = unicodeGlyphs
+	treeIndent = 2
+)
+
+// SetTreeStyle draws trees and chains with ASCII characters ("+-", "`-",
+// "|" and "->") instead of box drawing, for terminals and logs that mangle
+// them, and indents each tree level by indent columns (2 by default)
+func SetTreeStyle(ascii bool, indent int) error {
+	if indent < 1 || indent > 8 {
+		return fmt.Errorf("tree indentation must be between 1 and 8, not %d", indent)
+	}
+	glyphs = unicodeGlyphs
+	if ascii {
+		glyphs = asciiGlyphs
+	}
+	treeIndent = indent
+	return nil
+}
+
+// treeBranch is the connector drawn before a process in a tree: the last
+// child of its parent or, when more follow, a branch
+func treeBranch(last bool) string {
+	corner := glyphs.branch
+	if last {
+		corner = glyphs.last
+	}
+	return corner + strings.Repeat(glyphs.horizontal, max(treeIndent-1, 1)) + " "
+}
+
+// RenderTree prints the ancestry as a tree, followed by the subtree below
+// the target when the result has one
+func RenderTree(w io.Writer, r model.Result, colorEnabled bool) {
+	PrintTree(w, r.Ancestry, colorEnabled)
+	PrintChildren(w, r.Children, treeDepth(r.Ancestry), colorEnabled)
+}
+
+func PrintTree(w io.Writer, chain []model.Process, colorEnabled bool) {
 	colorReset := ""
 	colorMagenta := ""
 	colorBold := ""
@@ -21,22 +71,122 @@ func PrintTree(chain []model.Process, colorEnabled bool) {
 		colorMagenta = colorMagentaTree
 		colorBold = colorBoldTree
 	}
+	depth := 0
 	for i, p := range chain {
-		prefix := ""
-		for j := 0; j < i; j++ {
-			prefix += "  "
-		}
+		prefix := strings.Repeat(" ", depth*treeIndent)
+		depth++
 		if i > 0 {
 			if colorEnabled {
-				prefix += colorMagenta + "└─ " + colorReset
+				prefix += colorMagenta + treeBranch(true) + colorReset
 			} else {
-				prefix += "└─ "
+				prefix += treeBranch(true)
 			}
 		}
 		if colorEnabled {
-			fmt.Printf("%s%s (%spid %d%s)\n", prefix, p.Command, colorBold, p.PID, colorReset)
+			fmt.Fprintf(w, "%s%s (%spid %d%s)\n", prefix, p.Command, colorBold, p.PID, colorReset)
 		} else {
-			fmt.Printf("%s%s (pid %d)\n", prefix, p.Command, p.PID)
+			fmt.Fprintf(w, "%s%s (pid %d)\n", prefix, p.Command, p.PID)
+		}
+
+		// Show omitted ancestors as a single level of the tree
+		if p.SkippedAncestors > 0 {
+			indent := strings.Repeat(" ", depth*treeIndent)
+			depth++
+			if colorEnabled {
+				fmt.Fprintf(w, "%s%s%s%s%s%s%s\n", indent, colorMagenta, treeBranch(true), colorReset, colorBold, skippedLabel(p.SkippedAncestors), colorReset)
+			} else {
+				fmt.Fprintf(w, "%s%s%s\n", indent, treeBranch(true), skippedLabel(p.SkippedAncestors))
+			}
+		}
+	}
+}
+
+// treeDepth is the indentation level PrintTree would use for a child of
+// the last process of chain
+func treeDepth(chain []model.Process) int {
+	depth := len(chain)
+	for _, p := range chain {
+		if p.SkippedAncestors > 0 {
+			depth++
+		}
+	}
+	return depth
+}
+
+// PrintChildren prints a subtree of processes below a line indented by
+// depth levels, with each command line after the command when it adds to it
+func PrintChildren(w io.Writer, children []model.ChildProcess, depth int, colorEnabled bool) {
+	printChildren(w, children, depth, colorEnabled, false)
+}
+
+// printChildren is PrintChildren, with command lines in full when full is
+// set
+func printChildren(w io.Writer, children []model.ChildProcess, depth int, colorEnabled, full bool) {
+	indent := strings.Repeat(" ", depth*treeIndent)
+	var walk func(nodes []model.ChildProcess, prefix string)
+	walk = func(nodes []model.ChildProcess, prefix string) {
+		for i, c := range nodes {
+			last := i == len(nodes)-1
+			branch := treeBranch(last)
+			next := glyphs.vertical + strings.Repeat(" ", len([]rune(branch))-1)
+			if last {
+				next = strings.Repeat(" ", len([]rune(branch)))
+			}
+			args := childArgs(c)
+			if full {
+				args = fullChildArgs(c)
+			}
+			if colorEnabled {
+				fmt.Fprintf(w, "%s%s%s%s%s (%spid %d%s)", indent, colorMagentaTree, prefix+branch, colorResetTree, c.Command, colorBoldTree, c.PID, colorResetTree)
+				if args != "" {
+					fmt.Fprintf(w, "  %s%s%s", colorBoldTree, args, colorResetTree)
+				}
+			} else {
+				fmt.Fprintf(w, "%s%s%s (pid %d)", indent, prefix+branch, c.Command, c.PID)
+				if args != "" {
+					fmt.Fprintf(w, "  %s", args)
+				}
+			}
+			fmt.Fprintln(w)
+			walk(c.Children, prefix+next)
 		}
 	}
+	walk(children, "")
+}
+
+// maxChildArgs is the widest command line PrintChildren shows
+const maxChildArgs = 80
+
+// childArgs returns the command line of a child, shortened, or "" when it
+// is just the command
+func childArgs(c model.ChildProcess) string {
+	args := fullChildArgs(c)
+	if r := []rune(args); len(r) > maxChildArgs {
+		args = string(r[:maxChildArgs-len([]rune(glyphs.ellipsis))]) + glyphs.ellipsis
+	}
+	return args
+}
+
+// fullChildArgs returns the whole command line of a child, or "" when it
+// is just the command
+func fullChildArgs(c model.ChildProcess) string {
+	args := strings.Join(strings.Fields(c.Cmdline), " ")
+	if args == c.Command {
+		return ""
+	}
+	return args
+}
+
+// skippedLabel describes ancestors omitted from a very deep chain
+func skippedLabel(n int) string {
+	return fmt.Sprintf("%s %s more %s", glyphs.ellipsis, formatCount(n), glyphs.ellipsis)
+}
+
+// formatCount formats n with thousands separators
+func formatCount(n int) string {
+	s := fmt.Sprint(n)
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
 }
@@ -0,0 +1,31 @@
+package output
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestRenderTreeASCII(t *testing.T) {
+	if err := SetTreeStyle(true, 4); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { SetTreeStyle(false, 2) })
+	var b strings.Builder
+	RenderTree(&b, model.Result{
+		Ancestry: []model.Process{{PID: 1, Command: "systemd"}, {PID: 10, Command: "nginx"}},
+		Children: []model.ChildProcess{
+			{PID: 11, Command: "nginx", Children: []model.ChildProcess{{PID: 13, Command: "php"}}},
+			{PID: 12, Command: "nginx"},
+		},
+	}, false)
+	want := "systemd (pid 1)\n" +
+		"    `--- nginx (pid 10)\n" +
+		"        +--- nginx (pid 11)\n" +
+		"        |    `--- php (pid 13)\n" +
+		"        `--- nginx (pid 12)\n"
+	if b.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", b.String(), want)
+	}
+}
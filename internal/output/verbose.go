@@ -0,0 +1,92 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// RenderVerbose prints the standard report plus everything it leaves out
+// to stay compact: the full command line of every ancestor and child, and a
+// summary of the environment. Sections filled in by enrichment (sockets,
+// connections, fds, cgroup, triage) are printed as usual.
+func RenderVerbose(w io.Writer, r model.Result, colorEnabled bool) {
+	renderStandard(w, r, colorEnabled, true)
+}
+
+// renderAncestry lists each process of the chain with its PID, user and
+// untruncated command line
+func renderAncestry(w io.Writer, chain []model.Process, colorEnabled bool) {
+	if colorEnabled {
+		fmt.Fprintf(w, "%sAncestry%s    :\n", colorMagenta, colorReset)
+	} else {
+		fmt.Fprintln(w, "Ancestry    :")
+	}
+	for _, p := range chain {
+		cmdline := strings.Join(strings.Fields(p.Cmdline), " ")
+		if cmdline == "" {
+			cmdline = p.Command
+		}
+		user := p.User
+		if user == "" {
+			user = "unknown"
+		}
+		if colorEnabled {
+			fmt.Fprintf(w, "  %s%-7d%s %-10s %s\n", colorBold, p.PID, colorReset, user, cmdline)
+		} else {
+			fmt.Fprintf(w, "  %-7d %-10s %s\n", p.PID, user, cmdline)
+		}
+		if p.SkippedAncestors > 0 {
+			fmt.Fprintf(w, "  %s\n", skippedLabel(p.SkippedAncestors))
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+// renderEnvSummary prints how many environment variables the process has
+// and their names; values are left to --env
+func renderEnvSummary(w io.Writer, p model.Process, colorEnabled bool) {
+	if len(p.Env) == 0 {
+		return
+	}
+	names := make([]string, len(p.Env))
+	for i, kv := range p.Env {
+		names[i], _, _ = strings.Cut(kv, "=")
+	}
+	summary := fmt.Sprintf("%d variables", len(p.Env))
+	if colorEnabled {
+		fmt.Fprintf(w, "%sEnvironment%s : %s\n", colorGreen, colorReset, summary)
+	} else {
+		fmt.Fprintf(w, "Environment : %s\n", summary)
+	}
+	for _, line := range wrapList(names, 64) {
+		fmt.Fprintf(w, "              %s\n", line)
+	}
+}
+
+// wrapList joins items with ", " into lines of at most width characters,
+// except for single items longer than that
+func wrapList(items []string, width int) []string {
+	var lines []string
+	line := ""
+	for i, item := range items {
+		if i < len(items)-1 {
+			item += ","
+		}
+		switch {
+		case line == "":
+			line = item
+		case len(line)+1+len(item) > width:
+			lines = append(lines, line)
+			line = item
+		default:
+			line += " " + item
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
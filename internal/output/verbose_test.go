@@ -0,0 +1,14 @@
+package output
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWrapList(t *testing.T) {
+	got := wrapList([]string{"HOME", "PATH", "LANG", "A_VERY_LONG_VARIABLE_NAME", "TERM"}, 16)
+	want := []string{"HOME, PATH,", "LANG,", "A_VERY_LONG_VARIABLE_NAME,", "TERM"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("wrapList = %q, want %q", got, want)
+	}
+}
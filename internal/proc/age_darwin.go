@@ -0,0 +1,47 @@
+//go:build darwin
+
+package proc
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StartTimes returns the start time of every process with one ps call
+func StartTimes() map[int]time.Time {
+	times := make(map[int]time.Time)
+	cmd := exec.Command("ps", "-axo", "pid=,lstart=")
+	cmd.Env = buildEnvForPS()
+	out, err := cmd.Output()
+	if err != nil {
+		return times
+	}
+	for line := range strings.Lines(string(out)) {
+		// lstart is 5 fields: Mon Dec 25 12:00:00 2024
+		fields := strings.Fields(line)
+		if len(fields) < 6 {
+			continue
+		}
+		pid, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+		if t, err := time.Parse("Mon Jan 2 15:04:05 2006", strings.Join(fields[1:6], " ")); err == nil {
+			times[pid] = t
+		}
+	}
+	return times
+}
+
+// IgnoresHangup reports whether a process ignores SIGHUP, as processes
+// started with nohup do
+func IgnoresHangup(pid int) bool {
+	out, err := exec.Command("ps", "-p", strconv.Itoa(pid), "-o", "ignored=").Output()
+	if err != nil {
+		return false
+	}
+	mask, err := strconv.ParseUint(strings.TrimSpace(string(out)), 16, 64)
+	return err == nil && mask&1 != 0
+}
@@ -0,0 +1,46 @@
+//go:build linux
+
+package proc
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StartTimes returns the start time of every process, read from /proc
+func StartTimes() map[int]time.Time {
+	times := make(map[int]time.Time)
+	boot := bootTime()
+	entries, _ := os.ReadDir("/proc")
+	for _, e := range entries {
+		pid, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+		data, err := os.ReadFile("/proc/" + e.Name() + "/stat")
+		if err != nil {
+			continue
+		}
+		stat := string(data)
+		end := strings.LastIndexByte(stat, ')')
+		if end == -1 {
+			continue
+		}
+		fields := strings.Fields(stat[end+1:])
+		if len(fields) < 20 {
+			continue
+		}
+		ticks, _ := strconv.ParseInt(fields[19], 10, 64)
+		times[pid] = boot.Add(time.Duration(ticks) * time.Second / ticksPerSecond())
+	}
+	return times
+}
+
+// IgnoresHangup reports whether a process ignores SIGHUP, as processes
+// started with nohup do
+func IgnoresHangup(pid int) bool {
+	mask, err := strconv.ParseUint(readStatus(pid)["SigIgn"], 16, 64)
+	return err == nil && mask&1 != 0
+}
@@ -6,11 +6,19 @@ import (
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
+const (
+	// maxAncestry is the number of nearest ancestors read in full
+	maxAncestry = 128
+	// maxAncestryWalk caps the cheap walk to the root past maxAncestry
+	maxAncestryWalk = 100000
+)
+
 func ResolveAncestry(pid int) ([]model.Process, error) {
 	var chain []model.Process
 	seen := make(map[int]bool)
 
 	current := pid
+	var targetErr error
 
 	for current > 0 {
 		if seen[current] {
@@ -18,8 +26,26 @@ func ResolveAncestry(pid int) ([]model.Process, error) {
 		}
 		seen[current] = true
 
-		p, err := ReadProcess(current)
+		// A pathologically deep chain (fork bomb) is summarized: skip to the
+		// root through the process table and record how much was left out
+		if len(chain) >= maxAncestry {
+			if root, skipped, ok := skipToRoot(current, seen); ok {
+				root.SkippedAncestors = skipped
+				chain = append([]model.Process{root}, chain...)
+			}
+			break
+		}
+
+		// Only the target is read in full
+		read := readAncestor
+		if current == pid {
+			read = ReadProcess
+		}
+		p, err := read(current)
 		if err != nil {
+			if current == pid {
+				targetErr = err
+			}
 			break
 		}
 
@@ -31,9 +57,35 @@ func ResolveAncestry(pid int) ([]model.Process, error) {
 		current = p.PPID
 	}
 
+	if len(chain) == 0 && targetErr != nil {
+		// Wrapped so callers can tell a permission error from a missing process
+		return nil, fmt.Errorf("no process ancestry found: %w", targetErr)
+	}
 	if len(chain) == 0 {
 		return nil, fmt.Errorf("no process ancestry found")
 	}
 
 	return chain, nil
 }
+
+// skipToRoot walks parent links from pid without reading full process
+// details and returns the root of the chain with the number of ancestors
+// between it and the part of the chain already read
+func skipToRoot(pid int, seen map[int]bool) (model.Process, int, bool) {
+	table := processTable()
+	skipped := 0
+	for steps := 0; steps < maxAncestryWalk; steps++ {
+		e, ok := table[pid]
+		if !ok || e.PPID == 0 || pid == 1 || seen[e.PPID] {
+			break
+		}
+		seen[e.PPID] = true
+		pid = e.PPID
+		skipped++
+	}
+	root, err := readAncestor(pid)
+	if err != nil {
+		return model.Process{}, 0, false
+	}
+	return root, skipped, true
+}
@@ -36,3 +36,12 @@ func bootTime() time.Time {
 func ticksPerSecond() time.Duration {
 	return 100 // macOS default (same as Linux)
 }
+
+// BootID returns the kernel's UUID for the current boot session
+func BootID() string {
+	out, err := exec.Command("sysctl", "-n", "kern.bootsessionuuid").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
@@ -32,3 +32,12 @@ func bootTime() time.Time {
 func ticksPerSecond() time.Duration {
 	return 100 // Linux default; portable enough for now
 }
+
+// BootID returns the kernel's random ID for the current boot
+func BootID() string {
+	data, err := os.ReadFile("/proc/sys/kernel/random/boot_id")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,31 @@
+//go:build linux
+
+package proc
+
+import (
+	"strconv"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// decodeCapabilities returns the names of the capabilities set in a
+// CapEff-style hex mask, e.g. "0000000000003000" is CAP_NET_ADMIN and
+// CAP_NET_RAW. Bits newer than this table are named by number.
+func decodeCapabilities(mask string) []string {
+	bits, err := strconv.ParseUint(mask, 16, 64)
+	if err != nil {
+		return nil
+	}
+	var caps []string
+	for i := 0; i < 64; i++ {
+		if bits&(1<<i) == 0 {
+			continue
+		}
+		if i < len(model.CapabilityNames) {
+			caps = append(caps, model.CapabilityNames[i])
+		} else {
+			caps = append(caps, "CAP_"+strconv.Itoa(i))
+		}
+	}
+	return caps
+}
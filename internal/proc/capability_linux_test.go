@@ -0,0 +1,31 @@
+//go:build linux
+
+package proc
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestDecodeCapabilities(t *testing.T) {
+	tests := []struct {
+		mask string
+		want []string
+	}{
+		{"0000000000000000", nil},
+		{"0000000000003000", []string{"CAP_NET_ADMIN", "CAP_NET_RAW"}},
+		{"0000000000200400", []string{"CAP_NET_BIND_SERVICE", "CAP_SYS_ADMIN"}},
+		{"0000080000000000", []string{"CAP_43"}},
+		{"zz", nil},
+	}
+	for _, tt := range tests {
+		if got := decodeCapabilities(tt.mask); !slices.Equal(got, tt.want) {
+			t.Errorf("decodeCapabilities(%q) = %v, want %v", tt.mask, got, tt.want)
+		}
+	}
+	if got := decodeCapabilities("000001ffffffffff"); len(got) != len(model.CapabilityNames) {
+		t.Errorf("full mask decoded to %d capabilities, want %d", len(got), len(model.CapabilityNames))
+	}
+}
@@ -0,0 +1,10 @@
+//go:build darwin
+
+package proc
+
+import "github.com/pranshuparmar/witr/pkg/model"
+
+// GetCgroup returns nil, as macOS has no cgroups
+func GetCgroup(pid int) *model.CgroupInfo {
+	return nil
+}
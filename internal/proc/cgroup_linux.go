@@ -0,0 +1,116 @@
+//go:build linux
+
+package proc
+
+import (
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+const cgroupRoot = "/sys/fs/cgroup"
+
+// GetCgroup returns the cgroup v2 of a process with its effective memory and
+// CPU limits and throttling counters, or nil on cgroup v1 and hybrid hosts,
+// where the limits live in the v1 hierarchies
+func GetCgroup(pid int) *model.CgroupInfo {
+	if probeSkipped(ProbeCgroup) {
+		return nil
+	}
+	if _, err := os.Stat(cgroupRoot + "/cgroup.controllers"); err != nil {
+		return nil
+	}
+	data, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/cgroup")
+	if err != nil {
+		return nil
+	}
+	cgPath, ok := unifiedCgroupPath(string(data))
+	if !ok {
+		return nil
+	}
+	info := &model.CgroupInfo{Path: cgPath}
+
+	// Limits apply to the whole subtree, so the tightest one on the way to
+	// the root is the effective limit
+	for dir := cgPath; ; dir = path.Dir(dir) {
+		if max, ok := parseMemoryMax(readCgroupFile(dir, "memory.max")); ok && (info.MemoryMax == 0 || max < info.MemoryMax) {
+			info.MemoryMax, info.MemoryLimitedBy = max, dir
+		}
+		if quota := parseCPUMax(readCgroupFile(dir, "cpu.max")); quota > 0 && (info.CPUQuota == 0 || quota < info.CPUQuota) {
+			info.CPUQuota, info.CPULimitedBy = quota, dir
+		}
+		if dir == "/" {
+			break
+		}
+	}
+
+	usageOf := cgPath
+	if info.MemoryLimitedBy != "" {
+		usageOf = info.MemoryLimitedBy
+	}
+	info.MemoryCurrent, _ = strconv.ParseUint(readCgroupFile(usageOf, "memory.current"), 10, 64)
+
+	stat := parseKeyedCounters(readCgroupFile(cgPath, "cpu.stat"))
+	info.Periods = stat["nr_periods"]
+	info.Throttled = stat["nr_throttled"]
+	info.ThrottledTime = time.Duration(stat["throttled_usec"]) * time.Microsecond
+	info.OOMKills = parseKeyedCounters(readCgroupFile(cgPath, "memory.events"))["oom_kill"]
+	return info
+}
+
+func readCgroupFile(dir, name string) string {
+	data, err := os.ReadFile(cgroupRoot + path.Join(dir, name))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// unifiedCgroupPath returns the cgroup v2 path from /proc/<pid>/cgroup (the
+// "0::<path>" line)
+func unifiedCgroupPath(content string) (string, bool) {
+	for line := range strings.Lines(content) {
+		if p, ok := strings.CutPrefix(strings.TrimSpace(line), "0::"); ok && p != "" {
+			return p, true
+		}
+	}
+	return "", false
+}
+
+// parseMemoryMax parses memory.max; "max" and unreadable files are no limit
+func parseMemoryMax(s string) (uint64, bool) {
+	n, err := strconv.ParseUint(s, 10, 64)
+	return n, err == nil
+}
+
+// parseCPUMax parses cpu.max ("<quota> <period>" in microseconds, quota
+// "max" for none) into CPUs
+func parseCPUMax(s string) float64 {
+	quota, period, ok := strings.Cut(s, " ")
+	if !ok {
+		return 0
+	}
+	q, err1 := strconv.ParseFloat(quota, 64)
+	p, err2 := strconv.ParseFloat(period, 64)
+	if err1 != nil || err2 != nil || p == 0 {
+		return 0
+	}
+	return q / p
+}
+
+// parseKeyedCounters parses "key value" lines such as cpu.stat
+func parseKeyedCounters(s string) map[string]uint64 {
+	counters := make(map[string]uint64)
+	for line := range strings.Lines(s) {
+		if key, val, ok := strings.Cut(strings.TrimSpace(line), " "); ok {
+			if n, err := strconv.ParseUint(val, 10, 64); err == nil {
+				counters[key] = n
+			}
+		}
+	}
+	return counters
+}
@@ -0,0 +1,34 @@
+//go:build linux
+
+package proc
+
+import "testing"
+
+func TestUnifiedCgroupPath(t *testing.T) {
+	hybrid := "12:memory:/system.slice/nginx.service\n0::/system.slice/nginx.service\n"
+	if got, ok := unifiedCgroupPath(hybrid); !ok || got != "/system.slice/nginx.service" {
+		t.Errorf("unifiedCgroupPath() = %q, %v", got, ok)
+	}
+	if _, ok := unifiedCgroupPath("4:cpu,cpuacct:/docker/abc\n"); ok {
+		t.Error("unifiedCgroupPath(v1) succeeded")
+	}
+}
+
+func TestParseCgroupLimits(t *testing.T) {
+	if n, ok := parseMemoryMax("536870912"); !ok || n != 536870912 {
+		t.Errorf("parseMemoryMax() = %d, %v", n, ok)
+	}
+	if _, ok := parseMemoryMax("max"); ok {
+		t.Error("parseMemoryMax(max) reported a limit")
+	}
+	if got := parseCPUMax("50000 100000"); got != 0.5 {
+		t.Errorf("parseCPUMax() = %v, want 0.5", got)
+	}
+	if got := parseCPUMax("max 100000"); got != 0 {
+		t.Errorf("parseCPUMax(max) = %v, want 0", got)
+	}
+	stat := parseKeyedCounters("usage_usec 1200\nnr_periods 40\nnr_throttled 10\nthrottled_usec 52000\n")
+	if stat["nr_periods"] != 40 || stat["nr_throttled"] != 10 || stat["throttled_usec"] != 52000 {
+		t.Errorf("parseKeyedCounters() = %v", stat)
+	}
+}
@@ -0,0 +1,47 @@
+package proc
+
+import (
+	"sort"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// connection is one established socket
+type connection struct {
+	Protocol   string
+	LocalPort  int
+	RemoteAddr string
+	RemotePort int
+}
+
+// groupConnections counts connections per remote endpoint, busiest first.
+// Connections whose local port is one of listening are inbound.
+func groupConnections(conns []connection, listening map[int]bool) []model.ConnectionGroup {
+	counts := make(map[model.ConnectionGroup]int)
+	for _, c := range conns {
+		key := model.ConnectionGroup{Protocol: c.Protocol, Direction: "out", RemoteAddr: c.RemoteAddr, RemotePort: c.RemotePort}
+		if listening[c.LocalPort] {
+			key = model.ConnectionGroup{Protocol: c.Protocol, Direction: "in", RemoteAddr: c.RemoteAddr, LocalPort: c.LocalPort}
+		}
+		counts[key]++
+	}
+	groups := make([]model.ConnectionGroup, 0, len(counts))
+	for g, n := range counts {
+		g.Count = n
+		groups = append(groups, g)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		a, b := groups[i], groups[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if a.Direction != b.Direction {
+			return a.Direction > b.Direction
+		}
+		if a.RemoteAddr != b.RemoteAddr {
+			return a.RemoteAddr < b.RemoteAddr
+		}
+		return a.RemotePort < b.RemotePort
+	})
+	return groups
+}
@@ -0,0 +1,62 @@
+//go:build darwin
+
+package proc
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// GetConnections returns the established TCP and connected UDP sockets of a
+// process, grouped by remote endpoint
+func GetConnections(pid int) []model.ConnectionGroup {
+	// -F fPnT prints, per descriptor, f<fd>, P<protocol>, n<local->remote>
+	// and T<TCP info> lines; the TCP state follows the name
+	out, err := exec.Command("lsof", "-a", "-p", strconv.Itoa(pid), "-i", "-n", "-P", "-F", "fPnT").Output()
+	if err != nil {
+		return nil
+	}
+
+	var conns []connection
+	listening := make(map[int]bool)
+	var protocol, name, state string
+	flush := func() {
+		if name == "" {
+			return
+		}
+		local, remote, connected := strings.Cut(name, "->")
+		_, localPort := parseNetstatAddr(local)
+		switch {
+		case state == "LISTEN":
+			listening[localPort] = true
+		case connected && (state == "ESTABLISHED" || protocol == "udp"):
+			if addr, port := parseNetstatAddr(remote); port > 0 {
+				conns = append(conns, connection{Protocol: protocol, LocalPort: localPort, RemoteAddr: addr, RemotePort: port})
+			}
+		}
+		protocol, name, state = "", "", ""
+	}
+	for line := range strings.Lines(string(out)) {
+		line = strings.TrimRight(line, "\n")
+		if line == "" {
+			continue
+		}
+		switch line[0] {
+		case 'f', 'p':
+			flush()
+		case 'P':
+			protocol = strings.ToLower(line[1:])
+		case 'n':
+			name = line[1:]
+		case 'T':
+			if s, ok := strings.CutPrefix(line[1:], "ST="); ok {
+				state = s
+			}
+		}
+	}
+	flush()
+	return groupConnections(conns, listening)
+}
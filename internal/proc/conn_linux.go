@@ -0,0 +1,67 @@
+//go:build linux
+
+package proc
+
+import (
+	"bufio"
+	"os"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// GetConnections returns the established TCP and connected UDP sockets of a
+// process, grouped by remote endpoint
+func GetConnections(pid int) []model.ConnectionGroup {
+	inodes := socketsForPID(pid)
+	if len(inodes) == 0 {
+		return nil
+	}
+	all := make(map[string]connection)
+	// 01 is ESTABLISHED for both TCP and connected UDP sockets
+	for _, table := range []struct {
+		path, protocol string
+		ipv6           bool
+	}{
+		{"/proc/net/tcp", "tcp", false},
+		{"/proc/net/tcp6", "tcp6", true},
+		{"/proc/net/udp", "udp", false},
+		{"/proc/net/udp6", "udp6", true},
+	} {
+		readConnections(all, table.path, table.protocol, table.ipv6)
+	}
+
+	listeners, _ := readListeningSockets()
+	listening := make(map[int]bool)
+	var conns []connection
+	for _, inode := range inodes {
+		if c, ok := all[inode]; ok {
+			conns = append(conns, c)
+		}
+		if s, ok := listeners[inode]; ok {
+			listening[s.Port] = true
+		}
+	}
+	return groupConnections(conns, listening)
+}
+
+func readConnections(conns map[string]connection, path, protocol string, ipv6 bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Scan() // skip header
+	for scanner.Scan() {
+		// sl local_address rem_address st ... inode
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 || fields[3] != "01" {
+			continue
+		}
+		_, local := parseAddr(fields[1], ipv6)
+		addr, port := parseAddr(fields[2], ipv6)
+		conns[fields[9]] = connection{Protocol: protocol, LocalPort: local, RemoteAddr: addr, RemotePort: port}
+	}
+}
@@ -0,0 +1,23 @@
+package proc
+
+import "testing"
+
+func TestGroupConnections(t *testing.T) {
+	conns := []connection{
+		{Protocol: "tcp", LocalPort: 50001, RemoteAddr: "10.0.0.9", RemotePort: 5432},
+		{Protocol: "tcp", LocalPort: 50002, RemoteAddr: "10.0.0.9", RemotePort: 5432},
+		{Protocol: "tcp", LocalPort: 443, RemoteAddr: "203.0.113.7", RemotePort: 61000},
+		{Protocol: "tcp", LocalPort: 443, RemoteAddr: "203.0.113.7", RemotePort: 61001},
+		{Protocol: "tcp", LocalPort: 443, RemoteAddr: "203.0.113.7", RemotePort: 61002},
+	}
+	groups := groupConnections(conns, map[int]bool{443: true})
+	if len(groups) != 2 {
+		t.Fatalf("groupConnections() = %+v, want 2 groups", groups)
+	}
+	if g := groups[0]; g.Direction != "in" || g.RemoteAddr != "203.0.113.7" || g.LocalPort != 443 || g.Count != 3 {
+		t.Errorf("groups[0] = %+v, want 3 inbound on port 443", g)
+	}
+	if g := groups[1]; g.Direction != "out" || g.RemotePort != 5432 || g.Count != 2 {
+		t.Errorf("groups[1] = %+v, want 2 outbound to 5432", g)
+	}
+}
@@ -0,0 +1,145 @@
+package proc
+
+import (
+	"sort"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+const (
+	// LargeDescendantCount is the size at which a descendant set is reported
+	LargeDescendantCount = 1000
+	// maxDescendants caps traversal of the descendant set
+	maxDescendants = 100000
+	// maxChildTree caps the number of processes in a ChildTree
+	maxChildTree = LargeDescendantCount
+	// topOffenders is how many of the most common commands are listed
+	topOffenders = 3
+)
+
+// Family counts the threads, children and descendants of pid with their
+// total RSS, and also returns a summary of the descendants when there are at
+// least LargeDescendantCount of them (nil otherwise)
+func Family(pid int) (*model.Family, *model.DescendantSummary) {
+	table := processTable()
+	self, ok := table[pid]
+	if !ok {
+		return nil, nil
+	}
+	children := childMap(table)
+
+	family := &model.Family{
+		Threads:        self.Threads,
+		Children:       len(children[pid]),
+		SubtreeThreads: self.Threads,
+		SubtreeRSS:     self.RSS,
+	}
+	summary := &model.DescendantSummary{}
+	counts := make(map[string]int)
+	seen := map[int]bool{pid: true}
+	queue := []int{pid}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, child := range children[current] {
+			if seen[child] {
+				continue
+			}
+			if summary.Count >= maxDescendants {
+				summary.Truncated = true
+				queue = nil
+				break
+			}
+			seen[child] = true
+			summary.Count++
+			e := table[child]
+			counts[e.Command]++
+			family.SubtreeThreads += e.Threads
+			family.SubtreeRSS += e.RSS
+			queue = append(queue, child)
+		}
+	}
+	family.Descendants, family.Truncated = summary.Count, summary.Truncated
+	if summary.Count < LargeDescendantCount {
+		return family, nil
+	}
+
+	for cmd, n := range counts {
+		summary.Top = append(summary.Top, model.CommandCount{Command: cmd, Count: n})
+	}
+	sort.Slice(summary.Top, func(i, j int) bool {
+		if summary.Top[i].Count != summary.Top[j].Count {
+			return summary.Top[i].Count > summary.Top[j].Count
+		}
+		return summary.Top[i].Command < summary.Top[j].Command
+	})
+	if len(summary.Top) > topOffenders {
+		summary.Top = summary.Top[:topOffenders]
+	}
+	return family, summary
+}
+
+// Descendants returns the PIDs of every descendant of pid, parents before
+// their children. ok is false once pid itself has exited.
+func Descendants(pid int) (pids []int, ok bool) {
+	table := processTable()
+	if _, ok := table[pid]; !ok {
+		return nil, false
+	}
+	children := childMap(table)
+	seen := map[int]bool{pid: true}
+	queue := []int{pid}
+	for len(queue) > 0 && len(pids) < maxDescendants {
+		current := queue[0]
+		queue = queue[1:]
+		for _, child := range children[current] {
+			if !seen[child] {
+				seen[child] = true
+				pids = append(pids, child)
+				queue = append(queue, child)
+			}
+		}
+	}
+	return pids, true
+}
+
+// ChildTree returns the subtree below pid, children ordered by PID, down to
+// depth levels (0 for all of them). Very large trees are cut off at
+// maxChildTree processes; Family summarizes those instead.
+func ChildTree(pid, depth int) []model.ChildProcess {
+	table := processTable()
+	children := childMap(table)
+	for _, pids := range children {
+		sort.Ints(pids)
+	}
+	count := 0
+	var walk func(pid, level int) []model.ChildProcess
+	walk = func(pid, level int) []model.ChildProcess {
+		if depth > 0 && level > depth {
+			return nil
+		}
+		var nodes []model.ChildProcess
+		for _, child := range children[pid] {
+			if count >= maxChildTree {
+				break
+			}
+			count++
+			node := model.ChildProcess{PID: child, Command: table[child].Command, Cmdline: GetCmdline(child)}
+			node.Children = walk(child, level+1)
+			nodes = append(nodes, node)
+		}
+		return nodes
+	}
+	return walk(pid, 1)
+}
+
+// childMap indexes a process table by parent PID
+func childMap(table map[int]tableEntry) map[int][]int {
+	children := make(map[int][]int)
+	for child, e := range table {
+		if child != e.PPID {
+			children[e.PPID] = append(children[e.PPID], child)
+		}
+	}
+	return children
+}
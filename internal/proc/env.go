@@ -0,0 +1,35 @@
+package proc
+
+import "strings"
+
+// Environment variables whose names contain these markers usually carry
+// secrets, so their values are redacted
+var secretEnvMarkers = []string{"TOKEN", "SECRET", "PASSWORD", "KEY"}
+
+// RedactEnv returns a copy of env with the values of secret-looking
+// variables replaced by <redacted>
+func RedactEnv(env []string) []string {
+	if env == nil {
+		return nil
+	}
+	redacted := make([]string, len(env))
+	for i, e := range env {
+		redacted[i] = redactEnvEntry(e)
+	}
+	return redacted
+}
+
+// redactEnvEntry redacts a single NAME=value entry if its name looks secret
+func redactEnvEntry(e string) string {
+	name, _, ok := strings.Cut(e, "=")
+	if !ok {
+		return e
+	}
+	upper := strings.ToUpper(name)
+	for _, marker := range secretEnvMarkers {
+		if strings.Contains(upper, marker) {
+			return name + "=<redacted>"
+		}
+	}
+	return e
+}
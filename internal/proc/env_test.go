@@ -0,0 +1,32 @@
+package proc
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRedactEnv(t *testing.T) {
+	env := []string{
+		"HOME=/home/alice",
+		"GITHUB_TOKEN=ghp_abc",
+		"db_password=hunter2",
+		"AWS_SECRET_ACCESS_KEY=xyz",
+		"SSH_AUTH_SOCK=/run/user/1000/ssh",
+		"MALFORMED",
+	}
+	want := []string{
+		"HOME=/home/alice",
+		"GITHUB_TOKEN=<redacted>",
+		"db_password=<redacted>",
+		"AWS_SECRET_ACCESS_KEY=<redacted>",
+		"SSH_AUTH_SOCK=/run/user/1000/ssh",
+		"MALFORMED",
+	}
+	got := RedactEnv(env)
+	if !slices.Equal(got, want) {
+		t.Errorf("RedactEnv() = %q, want %q", got, want)
+	}
+	if env[1] != "GITHUB_TOKEN=ghp_abc" {
+		t.Errorf("RedactEnv modified its input: %q", env[1])
+	}
+}
@@ -0,0 +1,49 @@
+//go:build linux
+
+package proc
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// exeState compares the executable a process is running with the file now
+// at its path: removed, replaced by a different file (as package upgrades
+// do), or modified after the process started
+func exeState(pid int, exe string, started time.Time) string {
+	return exeStateIn("/proc/"+strconv.Itoa(pid), exe, started)
+}
+
+// exeStateIn is exeState for the process whose /proc directory is dir. The
+// path is looked up under the process's own root (dir/root), so processes
+// in containers or chroots are compared with their file, not the host's.
+func exeStateIn(dir, exe string, started time.Time) string {
+	if exe == "" {
+		return ""
+	}
+	path, deleted := strings.CutSuffix(exe, " (deleted)")
+	onDisk, err := os.Stat(filepath.Join(dir, "root", path))
+	if deleted {
+		if err == nil {
+			return model.ExeReplaced
+		}
+		return model.ExeDeleted
+	}
+	running, err2 := os.Stat(filepath.Join(dir, "exe"))
+	if err != nil || err2 != nil {
+		return ""
+	}
+	if !os.SameFile(running, onDisk) {
+		return model.ExeReplaced
+	}
+	// Start times have clock-tick resolution
+	if onDisk.ModTime().After(started.Add(time.Second)) {
+		return model.ExeModified
+	}
+	return ""
+}
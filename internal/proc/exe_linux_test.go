@@ -0,0 +1,51 @@
+//go:build linux
+
+package proc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// fakeProcDir lays out a /proc/<pid> directory whose root is a separate
+// tree, as for a process in a container, with exe linking to binary there
+func fakeProcDir(t *testing.T, binary string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, name := range []string{"/usr/bin/app", "/usr/bin/other"} {
+		p := filepath.Join(dir, "root", name)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink(filepath.Join(dir, "root", binary), filepath.Join(dir, "exe")); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestExeStateUsesProcessRoot(t *testing.T) {
+	later := time.Now().Add(time.Hour)
+	tests := []struct {
+		name, binary, exe, want string
+	}{
+		// /usr/bin/app does not exist on the host, only in the process's root
+		{"same file", "/usr/bin/app", "/usr/bin/app", ""},
+		{"replaced", "/usr/bin/other", "/usr/bin/app", model.ExeReplaced},
+		{"deleted", "/usr/bin/app", "/usr/bin/gone (deleted)", model.ExeDeleted},
+		{"deleted and reinstalled", "/usr/bin/app", "/usr/bin/app (deleted)", model.ExeReplaced},
+	}
+	for _, tt := range tests {
+		dir := fakeProcDir(t, tt.binary)
+		if got := exeStateIn(dir, tt.exe, later); got != tt.want {
+			t.Errorf("%s: exeStateIn = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+//go:build darwin
+
+package proc
+
+import "errors"
+
+// ExecWatcher reports processes as they call exec; macOS has no process
+// events connector, so WatchExecs always fails
+type ExecWatcher struct{}
+
+// WatchExecs returns an error; exec events are read from the Linux
+// process events connector
+func WatchExecs() (*ExecWatcher, error) {
+	return nil, errors.New("exec events are not available on macOS")
+}
+
+func (w *ExecWatcher) Next() (int, error) {
+	return 0, errors.ErrUnsupported
+}
+
+func (w *ExecWatcher) Close() error { return nil }
@@ -0,0 +1,109 @@
+//go:build linux
+
+package proc
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// Process events connector constants (linux/connector.h, linux/cn_proc.h)
+const (
+	cnIdxProc         = 1
+	cnValProc         = 1
+	procCnMcastListen = 1
+	procEventExec     = 0x2
+
+	// cn_msg header, then the event type, CPU and timestamp of proc_event
+	cnMsgLen       = 20
+	procEventHdLen = 16
+)
+
+// ExecWatcher reports processes as they call exec, from the kernel's
+// process events connector
+type ExecWatcher struct {
+	f       *os.File
+	buf     []byte
+	pending []int
+}
+
+// WatchExecs subscribes to exec events. It needs CAP_NET_ADMIN and a
+// kernel built with CONFIG_PROC_EVENTS.
+func WatchExecs() (*ExecWatcher, error) {
+	fd, err := syscall.Socket(syscall.AF_NETLINK, syscall.SOCK_DGRAM|syscall.SOCK_CLOEXEC|syscall.SOCK_NONBLOCK, syscall.NETLINK_CONNECTOR)
+	if err != nil {
+		return nil, fmt.Errorf("process events connector: %w", err)
+	}
+	if err := syscall.Bind(fd, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK, Groups: cnIdxProc}); err != nil {
+		syscall.Close(fd)
+		return nil, fmt.Errorf("process events connector: %w", err)
+	}
+	if err := syscall.Sendto(fd, listenMessage(), 0, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK}); err != nil {
+		syscall.Close(fd)
+		return nil, fmt.Errorf("subscribing to process events: %w", err)
+	}
+	// A non-blocking file uses the runtime poller, so Close interrupts Next
+	return &ExecWatcher{f: os.NewFile(uintptr(fd), "cn_proc"), buf: make([]byte, os.Getpagesize())}, nil
+}
+
+// Next blocks until a process calls exec and returns its PID. Events the
+// kernel dropped because witr fell behind are skipped.
+func (w *ExecWatcher) Next() (int, error) {
+	for len(w.pending) == 0 {
+		n, err := w.f.Read(w.buf)
+		if errors.Is(err, syscall.ENOBUFS) {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+		w.pending = execPIDs(w.buf[:n])
+	}
+	pid := w.pending[0]
+	w.pending = w.pending[1:]
+	return pid, nil
+}
+
+// Close unsubscribes, ending a blocked Next
+func (w *ExecWatcher) Close() error {
+	return w.f.Close()
+}
+
+// listenMessage builds the netlink message that subscribes to process
+// events: a cn_msg for the proc connector carrying PROC_CN_MCAST_LISTEN
+func listenMessage() []byte {
+	msg := make([]byte, syscall.NLMSG_HDRLEN+cnMsgLen+4)
+	ne := binary.NativeEndian
+	ne.PutUint32(msg[0:], uint32(len(msg)))
+	ne.PutUint16(msg[4:], syscall.NLMSG_DONE)
+	ne.PutUint32(msg[12:], uint32(os.Getpid()))
+	cn := msg[syscall.NLMSG_HDRLEN:]
+	ne.PutUint32(cn[0:], cnIdxProc)
+	ne.PutUint32(cn[4:], cnValProc)
+	ne.PutUint16(cn[16:], 4)
+	ne.PutUint32(cn[cnMsgLen:], procCnMcastListen)
+	return msg
+}
+
+// execPIDs returns the processes (thread group IDs) of the exec events in
+// a datagram from the connector
+func execPIDs(data []byte) []int {
+	msgs, err := syscall.ParseNetlinkMessage(data)
+	if err != nil {
+		return nil
+	}
+	ne := binary.NativeEndian
+	var pids []int
+	for _, m := range msgs {
+		ev := m.Data
+		if len(ev) < cnMsgLen+procEventHdLen+8 || ne.Uint32(ev[cnMsgLen:]) != procEventExec {
+			continue
+		}
+		// exec data is the process_pid (thread) and process_tgid
+		pids = append(pids, int(ne.Uint32(ev[cnMsgLen+procEventHdLen+4:])))
+	}
+	return pids
+}
@@ -0,0 +1,35 @@
+//go:build linux
+
+package proc
+
+import (
+	"encoding/binary"
+	"reflect"
+	"syscall"
+	"testing"
+)
+
+// procEvent builds a connector datagram carrying one proc_event
+func procEvent(what uint32, data ...uint32) []byte {
+	msg := make([]byte, syscall.NLMSG_HDRLEN+cnMsgLen+procEventHdLen+4*len(data))
+	ne := binary.NativeEndian
+	ne.PutUint32(msg[0:], uint32(len(msg)))
+	ne.PutUint16(msg[4:], syscall.NLMSG_DONE)
+	ev := msg[syscall.NLMSG_HDRLEN+cnMsgLen:]
+	ne.PutUint32(ev[0:], what)
+	for i, v := range data {
+		ne.PutUint32(ev[procEventHdLen+4*i:], v)
+	}
+	return msg
+}
+
+func TestExecPIDs(t *testing.T) {
+	// exec in thread 4242 of process 4200, then a fork, which is ignored
+	data := append(procEvent(procEventExec, 4242, 4200), procEvent(0x1, 1, 1, 4300, 4300)...)
+	if got, want := execPIDs(data), []int{4200}; !reflect.DeepEqual(got, want) {
+		t.Errorf("execPIDs() = %v, want %v", got, want)
+	}
+	if got := execPIDs(listenMessage()[:8]); got != nil {
+		t.Errorf("execPIDs(truncated) = %v, want nil", got)
+	}
+}
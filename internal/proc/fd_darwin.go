@@ -6,6 +6,8 @@ import (
 	"os/exec"
 	"strconv"
 	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
 )
 
 // socketsForPID returns socket inodes/identifiers for a given PID
@@ -43,3 +45,84 @@ func socketsForPID(pid int) []string {
 
 	return inodes
 }
+
+// FDSummary counts the open descriptors of a process by type and lists the
+// largest regular files it holds open, from lsof
+func FDSummary(pid int) *model.FDSummary {
+	out, err := exec.Command("lsof", "-p", strconv.Itoa(pid), "-F", "ftsn").Output()
+	if err != nil {
+		return nil
+	}
+	s := lsofFDSummary(string(out))
+	s.Limit = getFileLimit(pid)
+	return s
+}
+
+// lsofFDSummary parses lsof -F ftsn output: an f line per descriptor
+// followed by its t (type), s (size) and n (name) lines. Entries such as cwd
+// and txt are not descriptors and are skipped.
+func lsofFDSummary(out string) *model.FDSummary {
+	s := &model.FDSummary{Counts: make(map[string]int)}
+	var files []model.OpenFile
+	var cur *model.OpenFile
+	kind := ""
+	flush := func() {
+		if cur == nil {
+			return
+		}
+		s.Open++
+		s.Counts[kind]++
+		if kind == FDFile {
+			files = append(files, *cur)
+		}
+		cur = nil
+	}
+	for line := range strings.Lines(out) {
+		line = strings.TrimRight(line, "\n")
+		if line == "" {
+			continue
+		}
+		val := line[1:]
+		switch line[0] {
+		case 'f':
+			flush()
+			// Access mode suffixes, e.g. "3u"
+			if fd, err := strconv.Atoi(strings.TrimRight(val, "rwuNRWUxX- ")); err == nil {
+				cur = &model.OpenFile{FD: fd}
+				kind = FDFile
+			}
+		case 't':
+			if cur != nil {
+				kind = lsofType(val)
+			}
+		case 's':
+			if cur != nil {
+				cur.Size, _ = strconv.ParseInt(val, 10, 64)
+			}
+		case 'n':
+			if cur != nil {
+				cur.Path = val
+			}
+		}
+	}
+	flush()
+	s.Largest = keepLargest(files)
+	return s
+}
+
+// lsofType maps an lsof TYPE to a descriptor type
+func lsofType(t string) string {
+	switch t {
+	case "REG":
+		return FDFile
+	case "DIR":
+		return FDDirectory
+	case "IPv4", "IPv6", "unix", "sock", "systm", "ndrv":
+		return FDSocket
+	case "PIPE", "FIFO":
+		return FDPipe
+	case "CHR", "BLK":
+		return FDDevice
+	}
+	return FDAnon
+}
@@ -3,10 +3,13 @@
 package proc
 
 import (
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
 )
 
 func socketsForPID(pid int) []string {
@@ -32,3 +35,58 @@ func socketsForPID(pid int) []string {
 
 	return inodes
 }
+
+// FDSummary counts the open descriptors of a process by type and lists the
+// largest regular files it holds open, or returns nil when /proc/<pid>/fd
+// cannot be read
+func FDSummary(pid int) *model.FDSummary {
+	fdPath := "/proc/" + strconv.Itoa(pid) + "/fd"
+	entries, err := os.ReadDir(fdPath)
+	if err != nil {
+		return nil
+	}
+	s := &model.FDSummary{Counts: make(map[string]int), Limit: openFileLimit(pid)}
+	var files []model.OpenFile
+	for _, e := range entries {
+		link, err := os.Readlink(filepath.Join(fdPath, e.Name()))
+		if err != nil {
+			continue
+		}
+		s.Open++
+		kind := fdType(link)
+		if kind == FDFile || kind == FDDirectory {
+			// Stat the descriptor, which also works for deleted files
+			fi, err := os.Stat(filepath.Join(fdPath, e.Name()))
+			switch {
+			case err != nil:
+			case fi.IsDir():
+				kind = FDDirectory
+			case fi.Mode()&fs.ModeDevice != 0:
+				kind = FDDevice
+			case fi.Mode().IsRegular():
+				fd, _ := strconv.Atoi(e.Name())
+				path, deleted := strings.CutSuffix(link, " (deleted)")
+				files = append(files, model.OpenFile{FD: fd, Path: path, Size: fi.Size(), Deleted: deleted})
+			}
+		}
+		s.Counts[kind]++
+	}
+	s.Largest = keepLargest(files)
+	return s
+}
+
+// fdType classifies a descriptor by its /proc/<pid>/fd link target, e.g.
+// "socket:[123]", "pipe:[456]", "anon_inode:[eventpoll]" or a path
+func fdType(link string) string {
+	switch {
+	case strings.HasPrefix(link, "socket:["):
+		return FDSocket
+	case strings.HasPrefix(link, "pipe:["):
+		return FDPipe
+	case strings.HasPrefix(link, "anon_inode:"):
+		return FDAnon
+	case strings.HasPrefix(link, "/dev/") && !strings.HasPrefix(link, "/dev/shm/"):
+		return FDDevice
+	}
+	return FDFile
+}
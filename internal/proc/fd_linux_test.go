@@ -0,0 +1,45 @@
+//go:build linux
+
+package proc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFDType(t *testing.T) {
+	for link, want := range map[string]string{
+		"socket:[48213]":         FDSocket,
+		"pipe:[48214]":           FDPipe,
+		"anon_inode:[eventpoll]": FDAnon,
+		"/dev/null":              FDDevice,
+		"/dev/shm/sem.lock":      FDFile,
+		"/var/log/app.log":       FDFile,
+	} {
+		if got := fdType(link); got != want {
+			t.Errorf("fdType(%q) = %q, want %q", link, got, want)
+		}
+	}
+}
+
+func TestFDSummaryDeletedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "held.log")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.Write(make([]byte, 1<<20)); err != nil {
+		t.Fatal(err)
+	}
+	os.Remove(path)
+
+	s := FDSummary(os.Getpid())
+	if s == nil || s.Open == 0 || s.Limit == 0 {
+		t.Fatalf("FDSummary() = %+v", s)
+	}
+	if len(s.Largest) == 0 || s.Largest[0].Path != path || !s.Largest[0].Deleted || s.Largest[0].Size != 1<<20 {
+		t.Errorf("largest = %+v", s.Largest)
+	}
+}
@@ -0,0 +1,35 @@
+package proc
+
+import (
+	"slices"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// Descriptor types counted in an FDSummary
+const (
+	FDFile      = "file"
+	FDDirectory = "directory"
+	FDSocket    = "socket"
+	FDPipe      = "pipe"
+	FDDevice    = "device"
+	// FDAnon covers eventfd, epoll, timerfd, inotify and kqueue descriptors
+	FDAnon = "anon"
+)
+
+// largestOpenFiles is how many of the biggest open files a summary lists
+const largestOpenFiles = 5
+
+// keepLargest sorts files by size and keeps the biggest
+func keepLargest(files []model.OpenFile) []model.OpenFile {
+	slices.SortStableFunc(files, func(a, b model.OpenFile) int {
+		switch {
+		case a.Size > b.Size:
+			return -1
+		case a.Size < b.Size:
+			return 1
+		}
+		return 0
+	})
+	return files[:min(len(files), largestOpenFiles)]
+}
@@ -0,0 +1,11 @@
+//go:build darwin
+
+package proc
+
+import "github.com/pranshuparmar/witr/pkg/model"
+
+// GetGPU returns nil; macOS does not attribute GPU use to processes through
+// device nodes
+func GetGPU(_ int) *model.GPUUsage {
+	return nil
+}
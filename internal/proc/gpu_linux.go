@@ -0,0 +1,111 @@
+//go:build linux
+
+package proc
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// GetGPU returns the GPU device nodes a process holds open, or nil when it
+// holds none. For NVIDIA devices the per-GPU utilization and memory come from
+// nvidia-smi pmon when it is installed.
+func GetGPU(pid int) *model.GPUUsage {
+	fdPath := "/proc/" + strconv.Itoa(pid) + "/fd"
+	entries, err := os.ReadDir(fdPath)
+	if err != nil {
+		return nil
+	}
+	var devices []string
+	for _, e := range entries {
+		link, err := os.Readlink(filepath.Join(fdPath, e.Name()))
+		if err == nil && gpuDevice(link) && !slices.Contains(devices, link) {
+			devices = append(devices, link)
+		}
+	}
+	if len(devices) == 0 {
+		return nil
+	}
+	slices.Sort(devices)
+	gpu := &model.GPUUsage{Devices: devices}
+	if slices.ContainsFunc(devices, func(d string) bool { return strings.HasPrefix(d, "/dev/nvidia") }) {
+		if out, err := exec.Command("nvidia-smi", "pmon", "-c", "1", "-s", "um").Output(); err == nil {
+			gpu.GPUs = parsePmon(string(out), pid)
+		}
+	}
+	return gpu
+}
+
+// gpuDevice reports whether a device node belongs to a GPU: NVIDIA's
+// character devices, DRM cards and render nodes, and AMD's compute device
+func gpuDevice(path string) bool {
+	return strings.HasPrefix(path, "/dev/nvidia") || strings.HasPrefix(path, "/dev/dri/") || path == "/dev/kfd"
+}
+
+// parsePmon reads the rows of a process from nvidia-smi pmon output. Columns
+// differ between driver versions, so they are located by the header line,
+// e.g. "# gpu  pid  type  sm  mem  enc  dec  fb  command". Unreported values
+// are printed as "-".
+func parsePmon(out string, pid int) []model.GPUProcess {
+	var columns []string
+	var gpus []model.GPUProcess
+	for line := range strings.Lines(out) {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if fields[0] == "#" {
+			if columns == nil {
+				columns = fields[1:]
+			}
+			continue
+		}
+		col := func(name string) string {
+			if i := slices.Index(columns, name); i >= 0 && i < len(fields) {
+				return fields[i]
+			}
+			return "-"
+		}
+		if col("pid") != strconv.Itoa(pid) {
+			continue
+		}
+		index, err := strconv.Atoi(col("gpu"))
+		if err != nil {
+			continue
+		}
+		gpus = append(gpus, model.GPUProcess{
+			Index:     index,
+			Type:      pmonType(col("type")),
+			SMPercent: pmonValue(col("sm")),
+			MemoryMiB: pmonValue(col("fb")),
+		})
+	}
+	return gpus
+}
+
+// pmonType expands the pmon type column: C (compute), G (graphics) or C+G
+func pmonType(t string) string {
+	switch t {
+	case "C":
+		return "compute"
+	case "G":
+		return "graphics"
+	case "C+G":
+		return "compute+graphics"
+	}
+	return ""
+}
+
+func pmonValue(s string) *int {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return nil
+	}
+	return &n
+}
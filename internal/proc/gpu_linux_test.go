@@ -0,0 +1,42 @@
+//go:build linux
+
+package proc
+
+import "testing"
+
+func TestParsePmon(t *testing.T) {
+	out := `# gpu         pid   type     sm    mem    enc    dec    jpg    ofa     fb   command 
+# Idx           #    C/G      %      %      %      %      %      %     MB   name 
+    0        4211     C      35     12      -      -      -      -   2048   python3 
+    0        1870     G       2      1      -      -      -      -    112   Xorg 
+    1        4211     C       -      -      -      -      -      -    512   python3 
+`
+	gpus := parsePmon(out, 4211)
+	if len(gpus) != 2 {
+		t.Fatalf("parsePmon() = %+v", gpus)
+	}
+	if g := gpus[0]; g.Index != 0 || g.Type != "compute" || g.SMPercent == nil || *g.SMPercent != 35 || *g.MemoryMiB != 2048 {
+		t.Errorf("gpu 0 = %+v", g)
+	}
+	if g := gpus[1]; g.Index != 1 || g.SMPercent != nil || g.MemoryMiB == nil || *g.MemoryMiB != 512 {
+		t.Errorf("gpu 1 = %+v", g)
+	}
+	if gpus := parsePmon(out, 999); len(gpus) != 0 {
+		t.Errorf("other pid = %+v", gpus)
+	}
+}
+
+func TestGPUDevice(t *testing.T) {
+	for path, want := range map[string]bool{
+		"/dev/nvidia0":           true,
+		"/dev/nvidia-uvm":        true,
+		"/dev/dri/renderD128":    true,
+		"/dev/kfd":               true,
+		"/dev/null":              false,
+		"/home/u/nvidia0.driver": false,
+	} {
+		if got := gpuDevice(path); got != want {
+			t.Errorf("gpuDevice(%q) = %v", path, got)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package proc
+
+// Privilege summaries reported in model.Identity
+const (
+	privilegeRoot       = "root"
+	privilegeSetuidRoot = "elevated to root by a setuid binary"
+	privilegeElevated   = "elevated to root"
+	privilegeRegainable = "dropped privileges, can regain root through the saved uid"
+	privilegeDropped    = "dropped privileges from root"
+	privilegeNone       = "unprivileged"
+)
+
+// privilegeSummary describes real, effective and saved UIDs in a few words
+func privilegeSummary(ruid, euid, suid int, setuid bool) string {
+	switch {
+	case euid == 0 && ruid == 0:
+		return privilegeRoot
+	case euid == 0 && setuid:
+		return privilegeSetuidRoot
+	case euid == 0:
+		return privilegeElevated
+	case suid == 0:
+		return privilegeRegainable
+	case ruid == 0:
+		return privilegeDropped
+	}
+	return privilegeNone
+}
@@ -0,0 +1,48 @@
+//go:build darwin
+
+package proc
+
+import (
+	"os/exec"
+	"os/user"
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// readIdentity reads the credentials of a process with ps. Supplementary
+// groups of another process are not exposed on macOS.
+func readIdentity(pid int, setuid bool) *model.Identity {
+	out, err := exec.Command("ps", "-p", strconv.Itoa(pid), "-o", "ruid=,uid=,svuid=,rgid=,gid=,svgid=").Output()
+	if err != nil {
+		return nil
+	}
+	var ids []int
+	for _, f := range strings.Fields(string(out)) {
+		n, err := strconv.Atoi(f)
+		if err != nil {
+			return nil
+		}
+		ids = append(ids, n)
+	}
+	if len(ids) < 6 {
+		return nil
+	}
+	return &model.Identity{
+		RealUID: ids[0], EffectiveUID: ids[1], SavedUID: ids[2],
+		RealGID: ids[3], EffectiveGID: ids[4], SavedGID: ids[5],
+		UserName:  resolveUID(ids[0]),
+		GroupName: resolveGID(ids[3]),
+		Privilege: privilegeSummary(ids[0], ids[1], ids[2], setuid),
+	}
+}
+
+// resolveGID returns the name of a group, or "" if unknown
+func resolveGID(gid int) string {
+	g, err := user.LookupGroupId(strconv.Itoa(gid))
+	if err != nil {
+		return ""
+	}
+	return g.Name
+}
@@ -0,0 +1,59 @@
+//go:build linux
+
+package proc
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// readIdentity builds the credentials of a process from its status fields
+func readIdentity(status map[string]string, setuid bool) *model.Identity {
+	uids, gids := statusIDs(status["Uid"]), statusIDs(status["Gid"])
+	if len(uids) < 3 || len(gids) < 3 {
+		return nil
+	}
+	id := &model.Identity{
+		RealUID: uids[0], EffectiveUID: uids[1], SavedUID: uids[2],
+		RealGID: gids[0], EffectiveGID: gids[1], SavedGID: gids[2],
+		UserName:  resolveUID(uids[0]),
+		GroupName: resolveGID(gids[0]),
+		Privilege: privilegeSummary(uids[0], uids[1], uids[2], setuid),
+	}
+	id.Capabilities = decodeCapabilities(status["CapEff"])
+	id.AllCapabilities = len(id.Capabilities) >= len(model.CapabilityNames)
+	for _, g := range statusIDs(status["Groups"]) {
+		id.Groups = append(id.Groups, model.GroupRef{ID: g, Name: resolveGID(g)})
+	}
+	// Seccomp: 0, 1 or 2 (linux/seccomp.h)
+	switch status["Seccomp"] {
+	case "0":
+		id.Seccomp = model.SeccompDisabled
+	case "1":
+		id.Seccomp = model.SeccompStrict
+	case "2":
+		id.Seccomp = model.SeccompFilter
+		id.SeccompFilters, _ = strconv.Atoi(status["Seccomp_filters"])
+	}
+	id.NoNewPrivs = status["NoNewPrivs"] == "1"
+	return id
+}
+
+// resolveGID returns the name of a group from /etc/group, or "" if unknown
+func resolveGID(gid int) string {
+	data, err := os.ReadFile("/etc/group")
+	if err != nil {
+		return ""
+	}
+	gidStr := strconv.Itoa(gid)
+	for line := range strings.Lines(string(data)) {
+		fields := strings.Split(line, ":")
+		if len(fields) > 2 && fields[2] == gidStr {
+			return fields[0]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,26 @@
+//go:build linux
+
+package proc
+
+import (
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestReadIdentitySeccomp(t *testing.T) {
+	status := map[string]string{
+		"Uid": "101\t101\t101\t101", "Gid": "101\t101\t101\t101",
+		"Seccomp": "2", "Seccomp_filters": "3", "NoNewPrivs": "1",
+	}
+	id := readIdentity(status, false)
+	if id.Seccomp != model.SeccompFilter || id.SeccompFilters != 3 || !id.NoNewPrivs {
+		t.Errorf("filtered = %+v", id)
+	}
+
+	status["Seccomp"], status["NoNewPrivs"] = "0", "0"
+	id = readIdentity(status, false)
+	if id.Seccomp != model.SeccompDisabled || id.SeccompFilters != 0 || id.NoNewPrivs {
+		t.Errorf("unfiltered = %+v", id)
+	}
+}
@@ -0,0 +1,23 @@
+package proc
+
+import "testing"
+
+func TestPrivilegeSummary(t *testing.T) {
+	tests := []struct {
+		ruid, euid, suid int
+		setuid           bool
+		want             string
+	}{
+		{0, 0, 0, false, privilegeRoot},
+		{1000, 0, 0, true, privilegeSetuidRoot},
+		{1000, 0, 1000, false, privilegeElevated},
+		{33, 33, 0, false, privilegeRegainable},
+		{0, 33, 33, false, privilegeDropped},
+		{1000, 1000, 1000, false, privilegeNone},
+	}
+	for _, tt := range tests {
+		if got := privilegeSummary(tt.ruid, tt.euid, tt.suid, tt.setuid); got != tt.want {
+			t.Errorf("privilegeSummary(%d, %d, %d, %v) = %q, want %q", tt.ruid, tt.euid, tt.suid, tt.setuid, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,11 @@
+//go:build darwin
+
+package proc
+
+import "github.com/pranshuparmar/witr/pkg/model"
+
+// GetIntegrity reports kernel integrity state; IMA, EVM and fs-verity are
+// Linux-only
+func GetIntegrity(_ string) *model.Integrity {
+	return nil
+}
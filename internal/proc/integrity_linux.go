@@ -0,0 +1,137 @@
+//go:build linux
+
+package proc
+
+import (
+	"bufio"
+	"encoding/hex"
+	"os"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+const imaMeasurements = "/sys/kernel/security/ima/ascii_runtime_measurements"
+
+// ioctl numbers from linux/fs.h and linux/fsverity.h
+const (
+	fsIocGetFlags      = 0x80086601
+	fsIocMeasureVerity = 0xc0046686
+	fsVerityFlag       = 0x00100000
+)
+
+// GetIntegrity reports the IMA, EVM and fs-verity state of an executable,
+// or nil when none of them apply
+func GetIntegrity(exe string) *model.Integrity {
+	if exe == "" || probeSkipped(ProbeIntegrity) {
+		return nil
+	}
+	var in model.Integrity
+	in.IMA = imaMeasurement(exe)
+	if kind := readXattrKind(exe, "security.ima"); kind != 0 {
+		in.IMAAppraisal = imaXattrKind(kind)
+	}
+	if kind := readXattrKind(exe, "security.evm"); kind != 0 {
+		in.EVM = evmXattrKind(kind)
+	}
+	in.FSVerity = fsVerityDigest(exe)
+	if in == (model.Integrity{}) {
+		return nil
+	}
+	return &in
+}
+
+// imaMeasurement returns the latest digest IMA measured for path
+func imaMeasurement(path string) string {
+	f, err := os.Open(imaMeasurements)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	digest := ""
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if d, ok := parseIMAMeasurement(scanner.Text(), path); ok {
+			digest = d
+		}
+	}
+	return digest
+}
+
+// parseIMAMeasurement returns the file digest of an ima-ng/ima-sig
+// measurement line for path: "<pcr> <template hash> ima-ng <algo:digest> <path> ..."
+func parseIMAMeasurement(line, path string) (string, bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 5 || fields[4] != path {
+		return "", false
+	}
+	return fields[3], true
+}
+
+func readXattrKind(path, name string) byte {
+	buf := make([]byte, 1024)
+	n, err := syscall.Getxattr(path, name, buf)
+	if err != nil || n == 0 {
+		return 0
+	}
+	return buf[0]
+}
+
+// imaXattrKind names the security.ima xattr type (enum evm_ima_xattr_type)
+func imaXattrKind(kind byte) string {
+	switch kind {
+	case 0x01, 0x04:
+		return "file hash"
+	case 0x03:
+		return "signature"
+	case 0x06:
+		return "fs-verity signature"
+	}
+	return "unknown type"
+}
+
+// evmXattrKind names the security.evm xattr type
+func evmXattrKind(kind byte) string {
+	switch kind {
+	case 0x02:
+		return "HMAC"
+	case 0x03:
+		return "signature"
+	case 0x05:
+		return "portable signature"
+	}
+	return "unknown type"
+}
+
+// fsVerityDigest returns "<algorithm>:<digest>" for files with fs-verity
+// enabled
+func fsVerityDigest(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	var flags int
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), fsIocGetFlags, uintptr(unsafe.Pointer(&flags))); errno != 0 || flags&fsVerityFlag == 0 {
+		return ""
+	}
+
+	// struct fsverity_digest { __u16 digest_algorithm; __u16 digest_size; __u8 digest[]; }
+	buf := make([]byte, 4+64)
+	buf[2] = 64
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), fsIocMeasureVerity, uintptr(unsafe.Pointer(&buf[0]))); errno != 0 {
+		return "enabled"
+	}
+	alg := "sha256"
+	if buf[0] == 2 {
+		alg = "sha512"
+	}
+	size := int(buf[2]) | int(buf[3])<<8
+	if size > 64 {
+		size = 64
+	}
+	return alg + ":" + hex.EncodeToString(buf[4:4+size])
+}
@@ -0,0 +1,18 @@
+//go:build linux
+
+package proc
+
+import "testing"
+
+func TestParseIMAMeasurement(t *testing.T) {
+	line := "10 91f34b5c671d73504b274a919661cf80dab1e127 ima-ng sha256:2c7020ad8cab6b7419e4973171cb704bdbf52f77ea3a6eba25e8d5b4c9e5c1a8 /usr/sbin/nginx"
+	if got, ok := parseIMAMeasurement(line, "/usr/sbin/nginx"); !ok || got != "sha256:2c7020ad8cab6b7419e4973171cb704bdbf52f77ea3a6eba25e8d5b4c9e5c1a8" {
+		t.Errorf("parseIMAMeasurement() = %q, %v", got, ok)
+	}
+	if _, ok := parseIMAMeasurement(line, "/usr/sbin/sshd"); ok {
+		t.Error("parseIMAMeasurement() matched another path")
+	}
+	if got := imaXattrKind(0x03); got != "signature" {
+		t.Errorf("imaXattrKind(0x03) = %q, want signature", got)
+	}
+}
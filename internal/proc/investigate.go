@@ -0,0 +1,33 @@
+package proc
+
+import (
+	"sort"
+	"strings"
+)
+
+// Environment variables set by launchers, session managers and service
+// managers, matched by prefix
+var triageEnvPrefixes = []string{
+	"INVOCATION_ID=", "JOURNAL_STREAM=", "SYSTEMD_EXEC_PID=", "MANAGERPID=", "LISTEN_",
+	"SSH_", "SUDO_", "TMUX", "STY=", "TERM_PROGRAM", "XDG_SESSION_", "XDG_SEAT=",
+	"XDG_CURRENT_DESKTOP=", "DISPLAY=", "WAYLAND_DISPLAY=", "container=", "KUBERNETES_",
+	"PM2_", "SUPERVISOR_", "CRON", "GIO_LAUNCHED_", "DESKTOP_STARTUP_ID=", "FLATPAK_",
+	"SNAP_NAME=", "APPIMAGE=", "WSL_DISTRO_NAME=", "XPC_SERVICE_NAME=", "LAUNCH_",
+}
+
+// envHints returns the environment entries that hint at how a process was
+// launched, sorted, with secret-looking values redacted
+func envHints(env []string) []string {
+	var hints []string
+	for _, e := range env {
+		for _, prefix := range triageEnvPrefixes {
+			if !strings.HasPrefix(e, prefix) {
+				continue
+			}
+			hints = append(hints, redactEnvEntry(e))
+			break
+		}
+	}
+	sort.Strings(hints)
+	return hints
+}
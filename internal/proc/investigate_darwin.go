@@ -0,0 +1,56 @@
+//go:build darwin
+
+package proc
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// Only the first descriptors are listed; the rest are counted
+const maxTriageFDs = 32
+
+// Investigate collects the raw evidence witr has about a process, for
+// triaging processes no detector could classify
+func Investigate(p model.Process) *model.Triage {
+	t := &model.Triage{StartedAt: p.StartedAt, EnvHints: envHints(p.Env)}
+
+	if out, err := exec.Command("ps", "-o", "tty=", "-p", strconv.Itoa(p.PID)).Output(); err == nil {
+		if tty := strings.TrimSpace(string(out)); tty != "" && tty != "??" {
+			t.TTY = tty
+		}
+	}
+
+	// lsof -F fn prints "f<fd>" and "n<name>" lines per descriptor
+	out, err := exec.Command("lsof", "-a", "-p", strconv.Itoa(p.PID), "-d", "0-9999", "-F", "fn").Output()
+	if err != nil {
+		return t
+	}
+	fd, count := "", 0
+	for line := range strings.Lines(string(out)) {
+		line = strings.TrimRight(line, "\n")
+		if line == "" {
+			continue
+		}
+		switch line[0] {
+		case 'f':
+			fd = line[1:]
+		case 'n':
+			if fd == "" {
+				continue
+			}
+			count++
+			if count <= maxTriageFDs {
+				t.FDs = append(t.FDs, fd+" -> "+line[1:])
+			}
+			fd = ""
+		}
+	}
+	if count > maxTriageFDs {
+		t.FDs = append(t.FDs, "... "+strconv.Itoa(count-maxTriageFDs)+" more")
+	}
+	return t
+}
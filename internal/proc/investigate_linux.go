@@ -0,0 +1,123 @@
+//go:build linux
+
+package proc
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// Only the first descriptors are listed; the rest are counted
+const maxTriageFDs = 32
+
+// Investigate collects the raw evidence witr has about a process, for
+// triaging processes no detector could classify
+func Investigate(p model.Process) *model.Triage {
+	dir := "/proc/" + strconv.Itoa(p.PID)
+	t := &model.Triage{StartedAt: p.StartedAt, EnvHints: envHints(p.Env)}
+
+	if stat, err := os.ReadFile(dir + "/stat"); err == nil {
+		raw := string(stat)
+		if end := strings.LastIndex(raw, ")"); end != -1 && end+2 < len(raw) {
+			if fields := strings.Fields(raw[end+2:]); len(fields) > 4 {
+				nr, _ := strconv.Atoi(fields[4])
+				t.TTY = ttyName(nr)
+			}
+		}
+	}
+
+	if data, err := os.ReadFile(dir + "/cgroup"); err == nil {
+		for line := range strings.Lines(string(data)) {
+			if line = strings.TrimSpace(line); line != "" {
+				t.Cgroup = append(t.Cgroup, line)
+			}
+		}
+	}
+
+	t.Session = loginSession(dir)
+	t.FDs = listFDs(dir + "/fd")
+	return t
+}
+
+// ttyName decodes the tty_nr field of /proc/<pid>/stat
+func ttyName(nr int) string {
+	if nr == 0 {
+		return ""
+	}
+	major := (nr >> 8) & 0xfff
+	minor := (nr & 0xff) | ((nr >> 12) & 0xfff00)
+	switch {
+	case major >= 136 && major <= 143:
+		return "pts/" + strconv.Itoa((major-136)*256+minor)
+	case major == 4 && minor < 64:
+		return "tty" + strconv.Itoa(minor)
+	case major == 4:
+		return "ttyS" + strconv.Itoa(minor-64)
+	}
+	return strconv.Itoa(major) + ":" + strconv.Itoa(minor)
+}
+
+// loginSession reports the audit session and login uid of a process and,
+// when logind knows the session, its properties
+func loginSession(dir string) map[string]string {
+	session := make(map[string]string)
+	const unset = "4294967295"
+	if data, err := os.ReadFile(dir + "/loginuid"); err == nil {
+		if uid := strings.TrimSpace(string(data)); uid != unset {
+			session["loginuid"] = uid
+		}
+	}
+	data, err := os.ReadFile(dir + "/sessionid")
+	if err != nil {
+		return session
+	}
+	id := strings.TrimSpace(string(data))
+	if id == unset {
+		return session
+	}
+	session["id"] = id
+	out, err := exec.Command("loginctl", "show-session", id, "-p", "Name", "-p", "Seat", "-p", "Type", "-p", "Class", "-p", "Service", "-p", "Remote", "-p", "RemoteHost", "-p", "Leader", "-p", "TTY", "-p", "State").Output()
+	if err != nil {
+		return session
+	}
+	for line := range strings.Lines(string(out)) {
+		if key, val, ok := strings.Cut(strings.TrimSpace(line), "="); ok && val != "" {
+			session[strings.ToLower(key)] = val
+		}
+	}
+	return session
+}
+
+func listFDs(fdDir string) []string {
+	entries, err := os.ReadDir(fdDir)
+	if err != nil {
+		return nil
+	}
+	var fds []int
+	for _, e := range entries {
+		if n, err := strconv.Atoi(e.Name()); err == nil {
+			fds = append(fds, n)
+		}
+	}
+	sort.Ints(fds)
+
+	var out []string
+	for i, n := range fds {
+		if i == maxTriageFDs {
+			out = append(out, "... "+strconv.Itoa(len(fds)-maxTriageFDs)+" more")
+			break
+		}
+		link, err := os.Readlink(filepath.Join(fdDir, strconv.Itoa(n)))
+		if err != nil {
+			continue
+		}
+		out = append(out, strconv.Itoa(n)+" -> "+link)
+	}
+	return out
+}
@@ -0,0 +1,40 @@
+//go:build linux
+
+package proc
+
+import (
+	"os"
+	"testing"
+)
+
+func TestTTYName(t *testing.T) {
+	tests := []struct {
+		nr   int
+		want string
+	}{
+		{0, ""},
+		{136<<8 | 3, "pts/3"},
+		{137<<8 | 1, "pts/257"},
+		{4<<8 | 1, "tty1"},
+		{4<<8 | 64, "ttyS0"},
+	}
+	for _, tt := range tests {
+		if got := ttyName(tt.nr); got != tt.want {
+			t.Errorf("ttyName(%d) = %q, want %q", tt.nr, got, tt.want)
+		}
+	}
+}
+
+func TestLoginSessionIDs(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(dir+"/loginuid", []byte("1000"), 0o644)
+	os.WriteFile(dir+"/sessionid", []byte("4294967295"), 0o644)
+	if s := loginSession(dir); s["loginuid"] != "1000" || s["id"] != "" {
+		t.Errorf("unset session = %v", s)
+	}
+	os.WriteFile(dir+"/loginuid", []byte("4294967295"), 0o644)
+	os.WriteFile(dir+"/sessionid", []byte("12"), 0o644)
+	if s := loginSession(dir); s["loginuid"] != "" || s["id"] != "12" {
+		t.Errorf("unset login uid = %v", s)
+	}
+}
@@ -0,0 +1,10 @@
+//go:build darwin
+
+package proc
+
+import "github.com/pranshuparmar/witr/pkg/model"
+
+// GetLimits returns nil; macOS does not expose another process's limits
+func GetLimits(_ model.Process) []model.ResourceLimit {
+	return nil
+}
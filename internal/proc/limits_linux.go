@@ -0,0 +1,108 @@
+//go:build linux
+
+package proc
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// notableLimits maps the /proc/<pid>/limits rows witr reports to their
+// ulimit names
+var notableLimits = []struct{ row, name string }{
+	{"Max open files", "nofile"},
+	{"Max processes", "nproc"},
+	{"Max locked memory", "memlock"},
+	{"Max core file size", "core"},
+}
+
+// GetLimits returns the nofile, nproc, memlock and core limits of a process
+// with the usage of the first three, or nil when the limits are unreadable
+func GetLimits(p model.Process) []model.ResourceLimit {
+	data, err := os.ReadFile("/proc/" + strconv.Itoa(p.PID) + "/limits")
+	if err != nil {
+		return nil
+	}
+	limits := parseLimits(string(data))
+	for i := range limits {
+		l := &limits[i]
+		switch l.Name {
+		case "nofile":
+			if entries, err := os.ReadDir("/proc/" + strconv.Itoa(p.PID) + "/fd"); err == nil {
+				l.Usage = usage(int64(len(entries)))
+			}
+		case "nproc":
+			// The limit counts every task of the real user; root is exempt
+			if p.Identity != nil && p.Identity.RealUID != 0 {
+				l.Usage = usage(userTasks(p.Identity.RealUID))
+			}
+		case "memlock":
+			if kb, err := strconv.ParseInt(strings.TrimSuffix(readStatus(p.PID)["VmLck"], " kB"), 10, 64); err == nil {
+				l.Usage = usage(kb * 1024)
+			}
+		}
+	}
+	return limits
+}
+
+// openFileLimit returns the soft nofile limit of a process, or 0
+func openFileLimit(pid int) int {
+	data, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/limits")
+	if err != nil {
+		return 0
+	}
+	for _, l := range parseLimits(string(data)) {
+		if l.Name == "nofile" && l.Soft > 0 {
+			return int(l.Soft)
+		}
+	}
+	return 0
+}
+
+func usage(n int64) *int64 {
+	return &n
+}
+
+// parseLimits reads the notable rows of /proc/<pid>/limits, whose columns
+// are "Limit  Soft Limit  Hard Limit  Units"
+func parseLimits(content string) []model.ResourceLimit {
+	var limits []model.ResourceLimit
+	for _, want := range notableLimits {
+		for line := range strings.Lines(content) {
+			rest, ok := strings.CutPrefix(line, want.row)
+			if !ok {
+				continue
+			}
+			fields := strings.Fields(rest)
+			if len(fields) < 2 {
+				break
+			}
+			limits = append(limits, model.ResourceLimit{Name: want.name, Soft: limitValue(fields[0]), Hard: limitValue(fields[1])})
+			break
+		}
+	}
+	return limits
+}
+
+func limitValue(s string) int64 {
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return model.Unlimited
+	}
+	return n
+}
+
+// userTasks counts the threads of every process owned by uid, which is what
+// RLIMIT_NPROC limits
+func userTasks(uid int) int64 {
+	var tasks int64
+	for _, e := range processTable() {
+		if e.UID == uid {
+			tasks += int64(e.Threads)
+		}
+	}
+	return tasks
+}
@@ -0,0 +1,53 @@
+//go:build linux
+
+package proc
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestParseLimits(t *testing.T) {
+	content := `Limit                     Soft Limit           Hard Limit           Units     
+Max cpu time              unlimited            unlimited            seconds   
+Max core file size        0                    unlimited            bytes     
+Max processes             63432                63432                processes 
+Max open files            1024                 524288               files     
+Max locked memory         8388608              8388608              bytes     
+`
+	limits := parseLimits(content)
+	want := []struct {
+		name       string
+		soft, hard int64
+	}{
+		{"nofile", 1024, 524288},
+		{"nproc", 63432, 63432},
+		{"memlock", 8388608, 8388608},
+		{"core", 0, -1},
+	}
+	if len(limits) != len(want) {
+		t.Fatalf("parseLimits() = %+v", limits)
+	}
+	for i, w := range want {
+		if l := limits[i]; l.Name != w.name || l.Soft != w.soft || l.Hard != w.hard {
+			t.Errorf("limit %d = %+v, want %+v", i, l, w)
+		}
+	}
+}
+
+func TestUserTasksCachedTable(t *testing.T) {
+	release := CacheTable()
+	defer release()
+	first := processTable()
+	if e, ok := first[os.Getpid()]; !ok || e.UID != os.Geteuid() {
+		t.Fatalf("processTable()[self] = %+v, %v; want owned by uid %d", e, ok, os.Geteuid())
+	}
+	if userTasks(os.Geteuid()) < 1 {
+		t.Errorf("userTasks() = %d, want at least this process", userTasks(os.Geteuid()))
+	}
+	// The cached table is shared, not reread
+	if second := processTable(); reflect.ValueOf(second).Pointer() != reflect.ValueOf(first).Pointer() {
+		t.Error("processTable() reread the table while cached")
+	}
+}
@@ -0,0 +1,32 @@
+//go:build darwin
+
+package proc
+
+import (
+	"fmt"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ListPIDs returns the PIDs of running processes, sorted. A uid of -1 lists
+// every process; otherwise only processes owned by uid are returned.
+func ListPIDs(uid int) ([]int, error) {
+	args := []string{"-axo", "pid="}
+	if uid >= 0 {
+		args = []string{"-U", strconv.Itoa(uid), "-o", "pid="}
+	}
+	out, err := exec.Command("ps", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list processes: %w", err)
+	}
+	var pids []int
+	for _, field := range strings.Fields(string(out)) {
+		if pid, err := strconv.Atoi(field); err == nil {
+			pids = append(pids, pid)
+		}
+	}
+	sort.Ints(pids)
+	return pids, nil
+}
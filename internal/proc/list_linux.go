@@ -0,0 +1,40 @@
+//go:build linux
+
+package proc
+
+import (
+	"os"
+	"sort"
+	"strconv"
+	"syscall"
+)
+
+// ListPIDs returns the PIDs of running processes, sorted. A uid of -1 lists
+// every process; otherwise only processes owned by uid are returned, which
+// needs no privileges beyond that user's own.
+func ListPIDs(uid int) ([]int, error) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, err
+	}
+	var pids []int
+	for _, e := range entries {
+		pid, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+		if uid >= 0 {
+			info, err := os.Stat("/proc/" + e.Name())
+			if err != nil {
+				continue
+			}
+			stat, ok := info.Sys().(*syscall.Stat_t)
+			if !ok || int(stat.Uid) != uid {
+				continue
+			}
+		}
+		pids = append(pids, pid)
+	}
+	sort.Ints(pids)
+	return pids, nil
+}
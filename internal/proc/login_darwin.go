@@ -0,0 +1,10 @@
+//go:build darwin
+
+package proc
+
+import "github.com/pranshuparmar/witr/pkg/model"
+
+// LoginSession returns nil; ps does not report audit sessions on macOS
+func LoginSession(_ int) *model.LoginSession {
+	return nil
+}
@@ -0,0 +1,31 @@
+//go:build linux
+
+package proc
+
+import (
+	"strconv"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// LoginSession returns the login session a process was started from, or nil
+// for processes outside any login (system services have no login uid)
+func LoginSession(pid int) *model.LoginSession {
+	props := loginSession("/proc/" + strconv.Itoa(pid))
+	uid, err := strconv.Atoi(props["loginuid"])
+	if err != nil || props["id"] == "" {
+		return nil
+	}
+	s := &model.LoginSession{
+		LoginUID:   uid,
+		LoginUser:  resolveUID(uid),
+		ID:         props["id"],
+		Type:       props["type"],
+		Service:    props["service"],
+		TTY:        props["tty"],
+		RemoteHost: props["remotehost"],
+		State:      props["state"],
+	}
+	s.Leader, _ = strconv.Atoi(props["leader"])
+	return s
+}
@@ -0,0 +1,53 @@
+//go:build linux
+
+package proc
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+var namespaceTypes = []string{"pid", "net", "mnt", "user", "uts", "ipc", "cgroup"}
+
+// readNamespaces returns the namespaces of a process, marking those that
+// differ from init's. It returns nil when either side cannot be read
+// (reading another user's namespaces needs ptrace access).
+func readNamespaces(pid int) []model.Namespace {
+	if pid == 1 || probeSkipped(ProbeNamespaces) {
+		return nil
+	}
+	var namespaces []model.Namespace
+	for _, t := range namespaceTypes {
+		own, ok := namespaceInode(pid, t)
+		if !ok {
+			continue
+		}
+		initNS, ok := namespaceInode(1, t)
+		if !ok {
+			return nil
+		}
+		namespaces = append(namespaces, model.Namespace{Type: t, Inode: own, Isolated: own != initNS})
+	}
+	return namespaces
+}
+
+func namespaceInode(pid int, t string) (uint64, bool) {
+	link, err := os.Readlink("/proc/" + strconv.Itoa(pid) + "/ns/" + t)
+	if err != nil {
+		return 0, false
+	}
+	return parseNamespaceLink(link)
+}
+
+// parseNamespaceLink parses an ns symlink target such as "net:[4026531840]"
+func parseNamespaceLink(link string) (uint64, bool) {
+	_, rest, ok := strings.Cut(link, ":[")
+	if !ok || !strings.HasSuffix(rest, "]") {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(strings.TrimSuffix(rest, "]"), 10, 64)
+	return n, err == nil
+}
@@ -0,0 +1,16 @@
+//go:build linux
+
+package proc
+
+import "testing"
+
+func TestParseNamespaceLink(t *testing.T) {
+	if n, ok := parseNamespaceLink("net:[4026531840]"); !ok || n != 4026531840 {
+		t.Errorf("parseNamespaceLink(net) = %d, %v", n, ok)
+	}
+	for _, bad := range []string{"", "net:4026531840", "net:[x]", "pipe:[12"} {
+		if _, ok := parseNamespaceLink(bad); ok {
+			t.Errorf("parseNamespaceLink(%q) succeeded", bad)
+		}
+	}
+}
@@ -44,9 +44,10 @@ func readListeningSockets() (map[string]Socket, error) {
 				// Use PID:port as pseudo-inode
 				inode := currentPID + ":" + strconv.Itoa(port)
 				sockets[inode] = Socket{
-					Inode:   inode,
-					Port:    port,
-					Address: address,
+					Inode:    inode,
+					Protocol: "tcp",
+					Port:     port,
+					Address:  address,
 				}
 			}
 		}
@@ -79,9 +80,10 @@ func readListeningSocketsNetstat() (map[string]Socket, error) {
 			// Generate a unique key
 			inode := "netstat:" + localAddr
 			sockets[inode] = Socket{
-				Inode:   inode,
-				Port:    port,
-				Address: address,
+				Inode:    inode,
+				Protocol: "tcp",
+				Port:     port,
+				Address:  address,
 			}
 		}
 	}
@@ -89,6 +91,31 @@ func readListeningSocketsNetstat() (map[string]Socket, error) {
 	return sockets, nil
 }
 
+// boundSocketsForPID returns the bound UDP and Unix sockets of a process
+func boundSocketsForPID(pid int) []Socket {
+	var sockets []Socket
+	if out, err := exec.Command("lsof", "-a", "-p", strconv.Itoa(pid), "-i", "UDP", "-n", "-P", "-F", "n").Output(); err == nil {
+		for line := range strings.Lines(string(out)) {
+			// Connected UDP sockets print local->remote
+			if len(line) < 2 || line[0] != 'n' || strings.Contains(line, "->") {
+				continue
+			}
+			if address, port := parseNetstatAddr(strings.TrimSpace(line[1:])); port > 0 {
+				sockets = append(sockets, Socket{Protocol: "udp", Port: port, Address: address})
+			}
+		}
+	}
+	if out, err := exec.Command("lsof", "-a", "-p", strconv.Itoa(pid), "-U", "-F", "n").Output(); err == nil {
+		for line := range strings.Lines(string(out)) {
+			// Bound sockets print their path; peers print ->0x<addr>
+			if path := strings.TrimSpace(line); strings.HasPrefix(path, "n/") {
+				sockets = append(sockets, Socket{Protocol: "unix", Path: path[1:]})
+			}
+		}
+	}
+	return sockets
+}
+
 // parseNetstatAddr parses addresses like "*.8080", "127.0.0.1.8080", "[::1].8080"
 func parseNetstatAddr(addr string) (string, int) {
 	// Handle IPv6 format [::]:port or [::1]:port
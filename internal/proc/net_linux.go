@@ -13,45 +13,73 @@ import (
 
 func readListeningSockets() (map[string]Socket, error) {
 	sockets := make(map[string]Socket)
+	readInetSockets(sockets, "/proc/net/tcp", "tcp", false, "0A")
+	readInetSockets(sockets, "/proc/net/tcp6", "tcp6", true, "0A")
+	return sockets, nil
+}
 
-	parse := func(path string, ipv6 bool) {
-		f, err := os.Open(path)
-		if err != nil {
-			return
+// readBoundSockets returns listening Unix sockets and bound UDP sockets,
+// keyed by inode
+func readBoundSockets() map[string]Socket {
+	sockets := make(map[string]Socket)
+	// 07 = TCP_CLOSE, the state of an unconnected UDP socket
+	readInetSockets(sockets, "/proc/net/udp", "udp", false, "07")
+	readInetSockets(sockets, "/proc/net/udp6", "udp6", true, "07")
+
+	data, err := os.ReadFile("/proc/net/unix")
+	if err != nil {
+		return sockets
+	}
+	lines := strings.Split(string(data), "\n")
+	for _, line := range lines[1:] {
+		// Num RefCount Protocol Flags Type St Inode Path
+		fields := strings.Fields(line)
+		if len(fields) < 8 {
+			continue
 		}
-		defer f.Close()
-
-		scanner := bufio.NewScanner(f)
-		scanner.Scan() // skip header
-
-		for scanner.Scan() {
-			fields := strings.Fields(scanner.Text())
-			if len(fields) < 10 {
-				continue
-			}
-
-			local := fields[1]
-			state := fields[3]
-			inode := fields[9]
-
-			// 0A = LISTEN
-			if state != "0A" {
-				continue
-			}
-
-			addr, port := parseAddr(local, ipv6)
-			sockets[inode] = Socket{
-				Inode:   inode,
-				Port:    port,
-				Address: addr,
-			}
+		// __SO_ACCEPTCON marks sockets in listen(2)
+		flags, _ := strconv.ParseUint(fields[3], 16, 32)
+		if flags&0x10000 == 0 {
+			continue
 		}
+		sockets[fields[6]] = Socket{Inode: fields[6], Protocol: "unix", Path: fields[7]}
+	}
+	return sockets
+}
+
+// readInetSockets adds the sockets of a /proc/net table in the given state
+func readInetSockets(sockets map[string]Socket, path, protocol string, ipv6 bool, wantState string) {
+	f, err := os.Open(path)
+	if err != nil {
+		return
 	}
+	defer f.Close()
 
-	parse("/proc/net/tcp", false)
-	parse("/proc/net/tcp6", true)
+	scanner := bufio.NewScanner(f)
+	scanner.Scan() // skip header
 
-	return sockets, nil
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 {
+			continue
+		}
+
+		local := fields[1]
+		state := fields[3]
+		inode := fields[9]
+
+		if state != wantState {
+			continue
+		}
+
+		addr, port := parseAddr(local, ipv6)
+		sockets[inode] = Socket{
+			Inode:    inode,
+			Protocol: protocol,
+			Port:     port,
+			Address:  addr,
+		}
+	}
 }
 
 func parseAddr(raw string, ipv6 bool) (string, int) {
@@ -0,0 +1,43 @@
+//go:build darwin
+
+package proc
+
+import (
+	"os/exec"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// ExePackage reports the Homebrew formula or installer package that owns an
+// executable. macOS keeps no complete file database, so nil is returned
+// rather than flagging binaries that neither knows about.
+func ExePackage(exe string) *model.PackageOwner {
+	for _, cellar := range []string{"/opt/homebrew/Cellar/", "/usr/local/Cellar/", "/opt/homebrew/Caskroom/", "/usr/local/Caskroom/"} {
+		if rest, ok := strings.CutPrefix(exe, cellar); ok {
+			parts := strings.SplitN(rest, "/", 3)
+			if len(parts) >= 2 {
+				return &model.PackageOwner{Manager: "homebrew", Name: parts[0], Version: parts[1]}
+			}
+		}
+	}
+	out, err := exec.Command("pkgutil", "--file-info", exe).Output()
+	if err != nil {
+		return nil
+	}
+	owner := &model.PackageOwner{Manager: "pkgutil"}
+	for line := range strings.Lines(string(out)) {
+		key, val, ok := strings.Cut(strings.TrimSpace(line), ": ")
+		switch {
+		case !ok:
+		case key == "pkgid" && owner.Name == "":
+			owner.Name = val
+		case key == "pkg-version" && owner.Version == "":
+			owner.Version = val
+		}
+	}
+	if owner.Name == "" {
+		return nil
+	}
+	return owner
+}
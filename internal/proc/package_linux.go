@@ -0,0 +1,136 @@
+//go:build linux
+
+package proc
+
+import (
+	"os/exec"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// ExePackage asks the installed package managers which package owns an
+// executable. It returns an owner with an empty Name when a package manager
+// exists but none owns the file, and nil when there is no package manager
+// or the path is managed some other way (Nix, snaps, deleted files).
+func ExePackage(exe string) *model.PackageOwner {
+	if exe == "" || strings.HasSuffix(exe, " (deleted)") || strings.HasPrefix(exe, "/nix/store/") || strings.HasPrefix(exe, "/snap/") {
+		return nil
+	}
+	var asked *model.PackageOwner
+	for _, m := range []struct {
+		name  string
+		owner func(string) (string, string)
+	}{
+		{"dpkg", dpkgOwner},
+		{"rpm", rpmOwner},
+		{"pacman", pacmanOwner},
+		{"apk", apkOwner},
+	} {
+		if _, err := exec.LookPath(m.name); err != nil {
+			continue
+		}
+		// With merged /usr the database may record /bin/x for /usr/bin/x
+		for _, path := range usrMergeAliases(exe) {
+			if name, version := m.owner(path); name != "" {
+				return &model.PackageOwner{Manager: m.name, Name: name, Version: version}
+			}
+		}
+		if asked == nil {
+			asked = &model.PackageOwner{Manager: m.name}
+		}
+	}
+	return asked
+}
+
+// usrMergeAliases returns path and its alias across the /usr merge
+func usrMergeAliases(path string) []string {
+	for _, dir := range []string{"/bin/", "/sbin/", "/lib/", "/lib64/"} {
+		if rest, ok := strings.CutPrefix(path, "/usr"+dir); ok {
+			return []string{path, dir + rest}
+		}
+		if rest, ok := strings.CutPrefix(path, dir); ok {
+			return []string{path, "/usr" + dir + rest}
+		}
+	}
+	return []string{path}
+}
+
+func dpkgOwner(path string) (string, string) {
+	out, err := exec.Command("dpkg-query", "-S", path).Output()
+	if err != nil {
+		return "", ""
+	}
+	name := parseDpkgSearch(string(out), path)
+	if name == "" {
+		return "", ""
+	}
+	version, _ := exec.Command("dpkg-query", "-W", "-f=${Version}", name).Output()
+	return name, strings.TrimSpace(string(version))
+}
+
+// parseDpkgSearch returns the first package of the "pkg[, pkg]: path" line
+// for an exact path, skipping diversion notes
+func parseDpkgSearch(out, path string) string {
+	for line := range strings.Lines(out) {
+		pkgs, p, ok := strings.Cut(strings.TrimSpace(line), ": ")
+		if !ok || p != path || strings.HasPrefix(pkgs, "diversion") {
+			continue
+		}
+		name, _, _ := strings.Cut(pkgs, ",")
+		// Multi-arch packages are listed as name:arch
+		name, _, _ = strings.Cut(name, ":")
+		return strings.TrimSpace(name)
+	}
+	return ""
+}
+
+func rpmOwner(path string) (string, string) {
+	out, err := exec.Command("rpm", "-qf", "--qf", "%{NAME} %{VERSION}-%{RELEASE}\n", path).Output()
+	if err != nil {
+		return "", ""
+	}
+	name, version, _ := strings.Cut(strings.TrimSpace(firstLine(string(out))), " ")
+	return name, version
+}
+
+func pacmanOwner(path string) (string, string) {
+	out, err := exec.Command("pacman", "-Qo", path).Output()
+	if err != nil {
+		return "", ""
+	}
+	return parseOwnedBy(string(out), false)
+}
+
+func apkOwner(path string) (string, string) {
+	out, err := exec.Command("apk", "info", "--who-owns", path).Output()
+	if err != nil {
+		return "", ""
+	}
+	return parseOwnedBy(string(out), true)
+}
+
+// parseOwnedBy parses "<path> is owned by <owner>", where pacman prints
+// "<name> <version>" and apk "<name>-<version>-r<n>"
+func parseOwnedBy(out string, apk bool) (string, string) {
+	_, owner, ok := strings.Cut(firstLine(out), " is owned by ")
+	if !ok {
+		return "", ""
+	}
+	owner = strings.TrimSpace(owner)
+	if !apk {
+		name, version, _ := strings.Cut(owner, " ")
+		return name, version
+	}
+	// The version starts at the last two dash-separated fields
+	parts := strings.Split(owner, "-")
+	if len(parts) < 3 {
+		return owner, ""
+	}
+	return strings.Join(parts[:len(parts)-2], "-"), strings.Join(parts[len(parts)-2:], "-")
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
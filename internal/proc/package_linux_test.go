@@ -0,0 +1,36 @@
+//go:build linux
+
+package proc
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseDpkgSearch(t *testing.T) {
+	out := "diversion by dash from: /bin/sh\nnginx-core:amd64, nginx-light: /usr/sbin/nginx\n"
+	if got := parseDpkgSearch(out, "/usr/sbin/nginx"); got != "nginx-core" {
+		t.Errorf("parseDpkgSearch() = %q, want nginx-core", got)
+	}
+	if got := parseDpkgSearch("bash: /bin/bash\nbash: /bin/bashbug\n", "/bin/sh"); got != "" {
+		t.Errorf("parseDpkgSearch(no exact match) = %q", got)
+	}
+}
+
+func TestParseOwnedBy(t *testing.T) {
+	if name, version := parseOwnedBy("/usr/bin/nginx is owned by nginx 1.24.0-1\n", false); name != "nginx" || version != "1.24.0-1" {
+		t.Errorf("parseOwnedBy(pacman) = %q, %q", name, version)
+	}
+	if name, version := parseOwnedBy("/usr/sbin/nginx is owned by nginx-mod-http-1.24.0-r0\n", true); name != "nginx-mod-http" || version != "1.24.0-r0" {
+		t.Errorf("parseOwnedBy(apk) = %q, %q", name, version)
+	}
+}
+
+func TestUsrMergeAliases(t *testing.T) {
+	if got := usrMergeAliases("/usr/bin/bash"); !slices.Equal(got, []string{"/usr/bin/bash", "/bin/bash"}) {
+		t.Errorf("usrMergeAliases(/usr/bin/bash) = %v", got)
+	}
+	if got := usrMergeAliases("/opt/app/bin/app"); !slices.Equal(got, []string{"/opt/app/bin/app"}) {
+		t.Errorf("usrMergeAliases(/opt) = %v", got)
+	}
+}
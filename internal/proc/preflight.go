@@ -0,0 +1,80 @@
+package proc
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// Preflight check outcomes
+const (
+	PreflightOK      = "ok"
+	PreflightDenied  = "denied"
+	PreflightMissing = "missing"
+)
+
+// PreflightCheck is one file, directory or tool witr needs for a target
+type PreflightCheck struct {
+	Resource string `json:"resource"`
+	Purpose  string `json:"purpose"`
+	Status   string `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+// accessStatus maps an open error to a preflight status
+func accessStatus(err error) string {
+	switch {
+	case err == nil:
+		return PreflightOK
+	case errors.Is(err, os.ErrPermission):
+		return PreflightDenied
+	default:
+		return PreflightMissing
+	}
+}
+
+// CheckFile reports whether a file can be opened for reading
+func CheckFile(path, purpose string) PreflightCheck {
+	f, err := os.Open(path)
+	if err == nil {
+		f.Close()
+	}
+	return PreflightCheck{Resource: path, Purpose: purpose, Status: accessStatus(err)}
+}
+
+// checkDir reports whether a directory can be listed
+func checkDir(path, purpose string) PreflightCheck {
+	_, err := os.ReadDir(path)
+	return PreflightCheck{Resource: path, Purpose: purpose, Status: accessStatus(err)}
+}
+
+// checkLink reports whether a symlink (such as /proc/PID/exe) can be read
+func checkLink(path, purpose string) PreflightCheck {
+	_, err := os.Readlink(path)
+	return PreflightCheck{Resource: path, Purpose: purpose, Status: accessStatus(err)}
+}
+
+// CheckCommand reports whether a helper tool is installed
+func CheckCommand(name, purpose string) PreflightCheck {
+	c := PreflightCheck{Resource: name, Purpose: purpose, Status: PreflightOK}
+	if path, err := exec.LookPath(name); err != nil {
+		c.Status = PreflightMissing
+	} else {
+		c.Detail = path
+	}
+	return c
+}
+
+// checkSocket reports whether a Unix socket can be connected to, which needs
+// write permission. Absent sockets are skipped: the runtime is not installed.
+func checkSocket(path, purpose string) (PreflightCheck, bool) {
+	if _, err := os.Stat(path); err != nil {
+		return PreflightCheck{}, false
+	}
+	c := PreflightCheck{Resource: path, Purpose: purpose, Status: PreflightOK}
+	if err := syscall.Access(path, 2); err != nil {
+		c.Status = PreflightDenied
+	}
+	return c, true
+}
@@ -0,0 +1,50 @@
+//go:build darwin
+
+package proc
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// Preflight lists what explaining a target needs and whether the current
+// user can access it. pid is the resolved process, or 0 when resolving the
+// target is itself the access-sensitive step (ports and sockets).
+func Preflight(t model.Target, pid int) []PreflightCheck {
+	checks := []PreflightCheck{
+		CheckCommand("ps", "process list, parent, user and start time"),
+		CheckCommand("lsof", "sockets, open files and working directory"),
+		CheckCommand("launchctl", "launchd service detection"),
+	}
+	root := os.Geteuid() == 0
+
+	if t.Type == model.TargetPort || t.Type == model.TargetSocket {
+		c := PreflightCheck{Resource: "lsof (all processes)", Purpose: "map the socket to its owning process", Status: PreflightOK}
+		if !root {
+			c.Status = PreflightDenied
+			c.Detail = "sockets of other users' processes are only visible to root"
+		}
+		checks = append(checks, c)
+	}
+
+	if pid > 0 {
+		// Another user's environment and descriptors need root
+		status, detail := PreflightOK, ""
+		out, _ := exec.Command("ps", "-p", strconv.Itoa(pid), "-o", "uid=").Output()
+		if uid, err := strconv.Atoi(strings.TrimSpace(string(out))); err == nil && uid != os.Getuid() && !root {
+			status, detail = PreflightDenied, "process belongs to uid "+strconv.Itoa(uid)
+		}
+		for _, c := range []PreflightCheck{
+			{Resource: "ps -E -p " + strconv.Itoa(pid), Purpose: "environment (launcher detection, --env)"},
+			{Resource: "lsof -p " + strconv.Itoa(pid), Purpose: "sockets, open files and locks"},
+		} {
+			c.Status, c.Detail = status, detail
+			checks = append(checks, c)
+		}
+	}
+	return checks
+}
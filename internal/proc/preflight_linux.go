@@ -0,0 +1,84 @@
+//go:build linux
+
+package proc
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// Preflight lists what explaining a target needs and whether the current
+// user can access it. pid is the resolved process, or 0 when resolving the
+// target is itself the access-sensitive step (ports and sockets).
+func Preflight(t model.Target, pid int) []PreflightCheck {
+	var checks []PreflightCheck
+
+	switch t.Type {
+	case model.TargetPort:
+		for _, table := range []string{"tcp", "tcp6", "udp", "udp6"} {
+			checks = append(checks, CheckFile("/proc/net/"+table, "socket table to find the listener"))
+		}
+		checks = append(checks, fdOwnership("map the socket to its owning process"))
+	case model.TargetSocket:
+		checks = append(checks, CheckFile("/proc/net/unix", "Unix socket table"))
+		checks = append(checks, fdOwnership("map the socket to its owning process"))
+	case model.TargetName:
+		checks = append(checks, checkDir("/proc", "process list to match the name"))
+	}
+
+	if pid > 0 {
+		dir := "/proc/" + strconv.Itoa(pid)
+		checks = append(checks,
+			CheckFile(dir+"/stat", "process state, parent and start time"),
+			CheckFile(dir+"/status", "user and launch identity"),
+			CheckFile(dir+"/cmdline", "command line"),
+			CheckFile(dir+"/environ", "environment (launcher detection, --env)"),
+			checkLink(dir+"/cwd", "working directory and git repository"),
+			checkLink(dir+"/exe", "executable path and integrity"),
+			checkDir(dir+"/fd", "sockets, open files and locks"),
+			CheckFile(dir+"/cgroup", "container and systemd unit"),
+		)
+	}
+
+	checks = append(checks, CheckCommand("systemctl", "systemd unit, timer and socket detection"))
+	for _, s := range []struct{ path, purpose string }{
+		{"/var/run/docker.sock", "docker container names and images"},
+		{"/run/podman/podman.sock", "podman container names and images"},
+		{"/run/containerd/containerd.sock", "containerd container names"},
+	} {
+		if c, ok := checkSocket(s.path, s.purpose); ok {
+			checks = append(checks, c)
+		}
+	}
+	return checks
+}
+
+// fdOwnership reports how many processes' fd directories can be read; the
+// owner of a socket is only found if its fds are visible
+func fdOwnership(purpose string) PreflightCheck {
+	entries, _ := os.ReadDir("/proc")
+	total, denied := 0, 0
+	for _, e := range entries {
+		pid, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+		// Kernel threads have no command line and own no sockets
+		if cmdline, err := os.ReadFile("/proc/" + e.Name() + "/cmdline"); err != nil || len(cmdline) == 0 {
+			continue
+		}
+		total++
+		if _, err := os.ReadDir("/proc/" + strconv.Itoa(pid) + "/fd"); os.IsPermission(err) {
+			denied++
+		}
+	}
+	c := PreflightCheck{Resource: "/proc/*/fd", Purpose: purpose, Status: PreflightOK}
+	if denied > 0 {
+		c.Status = PreflightDenied
+		c.Detail = fmt.Sprintf("%d of %d processes are not readable", denied, total)
+	}
+	return c
+}
@@ -10,17 +10,30 @@ import (
 	"strings"
 	"time"
 
+	"github.com/pranshuparmar/witr/internal/runtimeapi"
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
+// ReadProcess reads a process's details, including its sockets
 func ReadProcess(pid int) (model.Process, error) {
+	return readProcess(pid, true)
+}
+
+// readAncestor reads a process for the ancestry of another, leaving out the
+// sockets only the target's result shows
+func readAncestor(pid int) (model.Process, error) {
+	return readProcess(pid, false)
+}
+
+func readProcess(pid int, full bool) (model.Process, error) {
 	// Read process info using ps command on macOS
 	// LC_ALL=C TZ=UTC ps -p <pid> -o pid=,ppid=,uid=,lstart=,state=,ucomm=
 	cmd := exec.Command("ps", "-p", strconv.Itoa(pid), "-o", "pid=,ppid=,uid=,lstart=,state=,ucomm=")
 	cmd.Env = buildEnvForPS()
 	out, err := cmd.Output()
 	if err != nil {
-		return model.Process{}, fmt.Errorf("process %d not found: %w", pid, err)
+		// ps exits non-zero when no process has the PID
+		return model.Process{}, fmt.Errorf("process %d not found: %w", pid, os.ErrNotExist)
 	}
 
 	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
@@ -84,6 +97,25 @@ func ReadProcess(pid int) (model.Process, error) {
 	// Get user from UID
 	user := readUserByUID(uid)
 
+	// Executable and launch identity (setuid binaries)
+	exe := getExecutablePath(pid)
+	setuid := false
+	if exe != "" {
+		if fi, err := os.Stat(exe); err == nil {
+			setuid = fi.Mode()&os.ModeSetuid != 0
+		}
+	}
+	launchUser := ""
+	identity := readIdentity(pid, setuid)
+	if id := identity; id != nil {
+		switch {
+		case id.RealUID != id.EffectiveUID:
+			launchUser = readUserByUID(id.RealUID)
+		case id.SavedUID == 0 && id.EffectiveUID != 0:
+			launchUser = "root"
+		}
+	}
+
 	// Container detection on macOS (Docker for Mac)
 	container := detectContainer(pid)
 
@@ -98,29 +130,29 @@ func ReadProcess(pid int) (model.Process, error) {
 	gitRepo, gitBranch := detectGitInfo(cwd)
 
 	// Get listening ports for this process
-	sockets, _ := readListeningSockets()
-	inodes := socketsForPID(pid)
-
 	var ports []int
 	var addrs []string
-
-	for _, inode := range inodes {
-		if s, ok := sockets[inode]; ok {
-			ports = append(ports, s.Port)
-			addrs = append(addrs, s.Address)
-		}
+	var listening []model.ListenSocket
+	if full {
+		ports, addrs, listening = processSockets(pid)
 	}
 
 	// Check for high resource usage
-	health = checkResourceUsage(pid, health)
+	health, usage := checkResourceUsage(pid, health)
 
 	return model.Process{
 		PID:            pid,
 		PPID:           ppid,
 		Command:        comm,
 		Cmdline:        cmdline,
+		Exe:            exe,
 		StartedAt:      startedAt,
+		Uptime:         time.Since(startedAt),
+		SinceBoot:      max(startedAt.Sub(bootTime()), 0),
 		User:           user,
+		LaunchUser:     launchUser,
+		Setuid:         setuid,
+		Identity:       identity,
 		WorkingDir:     cwd,
 		GitRepo:        gitRepo,
 		GitBranch:      gitBranch,
@@ -128,12 +160,39 @@ func ReadProcess(pid int) (model.Process, error) {
 		Service:        service,
 		ListeningPorts: ports,
 		BindAddresses:  addrs,
+		Sockets:        listening,
+		RSSBytes:       usage.rss,
+		MemPercent:     usage.mem,
+		CPUTime:        usage.cpu,
 		Health:         health,
 		Forked:         forked,
 		Env:            env,
 	}, nil
 }
 
+// processSockets returns the ports, addresses and sockets a process listens
+// on or has bound
+func processSockets(pid int) ([]int, []string, []model.ListenSocket) {
+	sockets, _ := readListeningSockets()
+	inodes := socketsForPID(pid)
+
+	var ports []int
+	var addrs []string
+	var listening []model.ListenSocket
+
+	for _, inode := range inodes {
+		if s, ok := sockets[inode]; ok {
+			ports = append(ports, s.Port)
+			addrs = append(addrs, s.Address)
+			listening = append(listening, s.model())
+		}
+	}
+	for _, s := range boundSocketsForPID(pid) {
+		listening = append(listening, s.model())
+	}
+	return ports, addrs, listening
+}
+
 func getCommandLine(pid int) string {
 	// Use ps to get full command line
 	out, err := exec.Command("ps", "-p", strconv.Itoa(pid), "-o", "args=").Output()
@@ -143,6 +202,19 @@ func getCommandLine(pid int) string {
 	return strings.TrimSpace(string(out))
 }
 
+// getExecutablePath returns the full executable path (ps reports it as comm on macOS)
+func getExecutablePath(pid int) string {
+	out, err := exec.Command("ps", "-p", strconv.Itoa(pid), "-o", "comm=").Output()
+	if err != nil {
+		return ""
+	}
+	path := strings.TrimSpace(string(out))
+	if !strings.HasPrefix(path, "/") {
+		return ""
+	}
+	return path
+}
+
 func getEnvironment(pid int) []string {
 	var env []string
 
@@ -299,32 +371,43 @@ func buildEnvForPS() []string {
 	return env
 }
 
-func checkResourceUsage(pid int, currentHealth string) string {
+// resourceUsage is the memory and CPU use ps reports for a process
+type resourceUsage struct {
+	rss uint64
+	mem float64
+	cpu time.Duration
+}
+
+func checkResourceUsage(pid int, currentHealth string) (string, resourceUsage) {
 	// Use ps to get CPU and memory usage
-	out, err := exec.Command("ps", "-p", strconv.Itoa(pid), "-o", "pcpu=,rss=").Output()
+	var usage resourceUsage
+	out, err := exec.Command("ps", "-p", strconv.Itoa(pid), "-o", "pcpu=,rss=,pmem=,time=").Output()
 	if err != nil {
-		return currentHealth
+		return currentHealth, usage
 	}
 
 	fields := strings.Fields(strings.TrimSpace(string(out)))
-	if len(fields) < 2 {
-		return currentHealth
+	if len(fields) < 4 {
+		return currentHealth, usage
 	}
+	rssKB, _ := strconv.ParseFloat(fields[1], 64)
+	usage.rss = uint64(rssKB * 1024)
+	usage.mem, _ = strconv.ParseFloat(fields[2], 64)
+	usage.cpu = parsePSTime(fields[3])
 
 	// Check CPU percentage
 	cpuPct, _ := strconv.ParseFloat(fields[0], 64)
 	if cpuPct > 90 {
-		return "high-cpu"
+		return "high-cpu", usage
 	}
 
 	// Check RSS memory in KB
-	rssKB, _ := strconv.ParseFloat(fields[1], 64)
 	rssMB := rssKB / 1024
 	if rssMB > 1024 { // > 1GB
-		return "high-mem"
+		return "high-mem", usage
 	}
 
-	return currentHealth
+	return currentHealth, usage
 }
 
 func resolveDockerProxyContainer(cmdline string) string {
@@ -340,8 +423,8 @@ func resolveDockerProxyContainer(cmdline string) string {
 		return ""
 	}
 
-	out, err := exec.Command("docker", "network", "inspect", "bridge",
-		"--format", "{{range .Containers}}{{.Name}}:{{.IPv4Address}}{{\"\\n\"}}{{end}}").Output()
+	out, err := runtimeapi.Output("docker", "network", "inspect", "bridge",
+		"--format", "{{range .Containers}}{{.Name}}:{{.IPv4Address}}{{\"\\n\"}}{{end}}")
 	if err != nil {
 		return ""
 	}
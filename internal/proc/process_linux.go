@@ -3,6 +3,7 @@
 package proc
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -10,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/pranshuparmar/witr/internal/runtimeapi"
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
@@ -38,18 +40,33 @@ func isValidSymlinkTarget(target string) bool {
 	return true
 }
 
+// ReadProcess reads a process's details, including its sockets, security
+// label, namespaces, scheduling and executable state
 func ReadProcess(pid int) (model.Process, error) {
+	return readProcess(pid, true)
+}
+
+// readAncestor reads a process for the ancestry of another, leaving out the
+// details only the target's result shows
+func readAncestor(pid int) (model.Process, error) {
+	return readProcess(pid, false)
+}
+
+func readProcess(pid int, full bool) (model.Process, error) {
 	// Verify process still exists before reading
 	if _, err := os.Stat(fmt.Sprintf("/proc/%d", pid)); os.IsNotExist(err) {
-		return model.Process{}, fmt.Errorf("process %d does not exist", pid)
+		return model.Process{}, fmt.Errorf("process %d does not exist: %w", pid, err)
 	}
 
 	// Read all proc files in a logical order to minimize TOCTOU issues
 	// Start with stat file which is most likely to fail if process disappears
 	statPath := fmt.Sprintf("/proc/%d/stat", pid)
 	stat, err := os.ReadFile(statPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return model.Process{}, fmt.Errorf("process %d disappeared during read: %w", pid, err)
+	}
 	if err != nil {
-		return model.Process{}, fmt.Errorf("process %d disappeared during read", pid)
+		return model.Process{}, fmt.Errorf("reading process %d: %w", pid, err)
 	}
 
 	// Read environment variables
@@ -168,7 +185,8 @@ func ReadProcess(pid int) (model.Process, error) {
 		forked = "not-forked"
 	}
 
-	startedAt := bootTime().Add(time.Duration(startTicks) * time.Second / ticksPerSecond())
+	sinceBoot := time.Duration(startTicks) * time.Second / ticksPerSecond()
+	startedAt := bootTime().Add(sinceBoot)
 
 	// Health: zombie/stopped
 	switch state {
@@ -189,6 +207,10 @@ func ReadProcess(pid int) (model.Process, error) {
 	}
 	pageSize := float64(os.Getpagesize())
 	memBytes := rssPages * pageSize
+	memPercent := 0.0
+	if total := memTotal(); total > 0 {
+		memPercent = memBytes / float64(total) * 100
+	}
 	memMB := memBytes / (1024 * 1024)
 	if memMB > 1024 {
 		health = "high-mem"
@@ -196,17 +218,29 @@ func ReadProcess(pid int) (model.Process, error) {
 
 	user := readUser(pid)
 
-	sockets, _ := readListeningSockets()
-	inodes := socketsForPID(pid)
+	// Executable and launch identity (setuid binaries, dropped privileges)
+	exe, _ := os.Readlink(fmt.Sprintf("/proc/%d/exe", pid))
+	setuid := false
+	if fi, err := os.Stat(fmt.Sprintf("/proc/%d/exe", pid)); err == nil {
+		setuid = fi.Mode()&os.ModeSetuid != 0
+	}
+	launchUser := ""
+	status := readStatus(pid)
+	if uids := statusIDs(status["Uid"]); len(uids) >= 3 {
+		realUID, effUID, savedUID := uids[0], uids[1], uids[2]
+		switch {
+		case realUID != effUID:
+			launchUser = resolveUID(realUID)
+		case savedUID == 0 && effUID != 0:
+			launchUser = "root"
+		}
+	}
 
 	var ports []int
 	var addrs []string
-
-	for _, inode := range inodes {
-		if s, ok := sockets[inode]; ok {
-			ports = append(ports, s.Port)
-			addrs = append(addrs, s.Address)
-		}
+	var listening []model.ListenSocket
+	if full {
+		ports, addrs, listening = processSockets(pid)
 	}
 	// Full command line
 	cmdline := ""
@@ -220,24 +254,70 @@ func ReadProcess(pid int) (model.Process, error) {
 		container = resolveDockerProxyContainer(cmdline)
 	}
 
-	return model.Process{
+	p := model.Process{
 		PID:            pid,
 		PPID:           ppid,
 		Command:        comm,
 		Cmdline:        cmdline,
+		Exe:            exe,
 		StartedAt:      startedAt,
+		Uptime:         time.Since(startedAt),
+		SinceBoot:      sinceBoot,
 		User:           user,
+		LaunchUser:     launchUser,
+		Setuid:         setuid,
+		Identity:       readIdentity(status, setuid),
 		WorkingDir:     cwd,
+		Root:           readRoot(pid),
 		GitRepo:        gitRepo,
 		GitBranch:      gitBranch,
 		Container:      container,
 		Service:        service,
 		ListeningPorts: ports,
 		BindAddresses:  addrs,
+		Sockets:        listening,
+		RSSBytes:       uint64(memBytes),
+		MemPercent:     memPercent,
+		CPUTime:        time.Duration(totalCPU * float64(time.Second)),
 		Health:         health,
 		Forked:         forked,
 		Env:            env,
-	}, nil
+	}
+	if full {
+		p.ExeState = exeState(pid, exe, startedAt)
+		p.Security = readSecurityLabel(pid)
+		p.Namespaces = readNamespaces(pid)
+		p.Scheduling = readScheduling(pid, fields, status)
+	}
+	return p, nil
+}
+
+// processSockets returns the ports, addresses and sockets a process listens
+// on or has bound
+func processSockets(pid int) ([]int, []string, []model.ListenSocket) {
+	sockets, _ := readListeningSockets()
+	inodes := socketsForPID(pid)
+
+	var ports []int
+	var addrs []string
+	var listening []model.ListenSocket
+
+	for _, inode := range inodes {
+		if s, ok := sockets[inode]; ok {
+			ports = append(ports, s.Port)
+			addrs = append(addrs, s.Address)
+			listening = append(listening, s.model())
+		}
+	}
+	if len(inodes) > 0 {
+		bound := readBoundSockets()
+		for _, inode := range inodes {
+			if s, ok := bound[inode]; ok {
+				listening = append(listening, s.model())
+			}
+		}
+	}
+	return ports, addrs, listening
 }
 
 func resolveDockerProxyContainer(cmdline string) string {
@@ -253,8 +333,8 @@ func resolveDockerProxyContainer(cmdline string) string {
 		return ""
 	}
 
-	out, err := exec.Command("docker", "network", "inspect", "bridge",
-		"--format", "{{range .Containers}}{{.Name}}:{{.IPv4Address}}{{\"\\n\"}}{{end}}").Output()
+	out, err := runtimeapi.Output("docker", "network", "inspect", "bridge",
+		"--format", "{{range .Containers}}{{.Name}}:{{.IPv4Address}}{{\"\\n\"}}{{end}}")
 	if err != nil {
 		return ""
 	}
@@ -287,3 +367,13 @@ func processState(fields []string) string {
 	}
 	return state[:1]
 }
+
+// readRoot returns the root directory of a process. Processes in another
+// mount namespace show "/" unless they have also been chrooted.
+func readRoot(pid int) string {
+	root, err := os.Readlink(fmt.Sprintf("/proc/%d/root", pid))
+	if err != nil || !isValidSymlinkTarget(root) {
+		return ""
+	}
+	return root
+}
@@ -0,0 +1,17 @@
+package proc
+
+import "slices"
+
+// Probes that are skipped under sandboxes where their data is misleading
+const (
+	ProbeNamespaces  = "namespaces"
+	ProbeConfinement = "confinement"
+	ProbeCgroup      = "cgroup"
+	ProbeIntegrity   = "integrity"
+)
+
+// probeSkipped reports whether the current sandbox rules out a probe
+func probeSkipped(probe string) bool {
+	sb := Sandbox()
+	return sb != nil && slices.Contains(sb.Skipped, probe)
+}
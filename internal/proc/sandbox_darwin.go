@@ -0,0 +1,10 @@
+//go:build darwin
+
+package proc
+
+import "github.com/pranshuparmar/witr/pkg/model"
+
+// Sandbox returns nil; sandboxed kernels are only detected on Linux
+func Sandbox() *model.Sandbox {
+	return nil
+}
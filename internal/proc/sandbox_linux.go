@@ -0,0 +1,112 @@
+//go:build linux
+
+package proc
+
+import (
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// gvisorVersion is the fixed build line gVisor reports in /proc/version
+const gvisorVersion = "#1 SMP Sun Jan 10 15:06:54 PST 2016"
+
+// sandboxFacts are the files a sandbox is recognized from
+type sandboxFacts struct {
+	version   string // /proc/version
+	cmdline   string // /proc/cmdline
+	dmiVendor string // /sys/class/dmi/id/sys_vendor
+	uidMap    string // /proc/self/uid_map
+	container string // $container of pid 1 or /run/systemd/container
+}
+
+// Sandbox returns the sandbox witr is running under, or nil on a regular
+// host or VM. It is detected once per run.
+var Sandbox = sync.OnceValue(func() *model.Sandbox {
+	return classifySandbox(sandboxFacts{
+		version:   readTrimmed("/proc/version"),
+		cmdline:   readTrimmed("/proc/cmdline"),
+		dmiVendor: readTrimmed("/sys/class/dmi/id/sys_vendor"),
+		uidMap:    readTrimmed("/proc/self/uid_map"),
+		container: containerManager(),
+	})
+})
+
+func classifySandbox(f sandboxFacts) *model.Sandbox {
+	switch {
+	case strings.Contains(f.version, gvisorVersion):
+		// runsc emulates cgroups and namespaces and has no LSM or IMA
+		return &model.Sandbox{
+			Runtime:  "gVisor",
+			Evidence: "/proc/version reports the gVisor kernel build",
+			Skipped:  []string{ProbeNamespaces, ProbeConfinement, ProbeCgroup, ProbeIntegrity},
+		}
+	case strings.Contains(f.dmiVendor, "Firecracker"):
+		return &model.Sandbox{Runtime: "Firecracker microVM", Evidence: "DMI vendor is " + f.dmiVendor}
+	case strings.Contains(f.cmdline, "firecracker") || strings.Contains(f.cmdline, "virtio_mmio.device="):
+		return &model.Sandbox{Runtime: "Firecracker microVM", Evidence: "kernel command line names Firecracker or virtio-mmio devices"}
+	}
+	if rootUID, ok := userNamespaceRoot(f.uidMap); ok {
+		runtime := "user namespace"
+		if f.container != "" {
+			runtime += " (" + f.container + ")"
+		}
+		return &model.Sandbox{
+			Runtime:  runtime,
+			Evidence: "/proc/self/uid_map maps only part of the host uid range",
+			RootUID:  rootUID,
+		}
+	}
+	return nil
+}
+
+// userNamespaceRoot reports whether uid_map describes a user namespace
+// other than the initial one, and the host uid root maps to in it
+func userNamespaceRoot(uidMap string) (string, bool) {
+	if uidMap == "" {
+		return "", false
+	}
+	rootUID := ""
+	for line := range strings.Lines(uidMap) {
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			continue
+		}
+		// The initial namespace maps the full range onto itself
+		if fields[0] == "0" && fields[1] == "0" && fields[2] == "4294967295" {
+			return "", false
+		}
+		if fields[0] == "0" {
+			rootUID = fields[1]
+		}
+	}
+	return rootUID, true
+}
+
+// containerManager returns the container manager systemd or the init
+// process advertises, such as "lxc"
+func containerManager() string {
+	if name := readTrimmed("/run/systemd/container"); name != "" {
+		return name
+	}
+	data, err := os.ReadFile("/proc/1/environ")
+	if err != nil {
+		return ""
+	}
+	for kv := range strings.SplitSeq(string(data), "\x00") {
+		if name, ok := strings.CutPrefix(kv, "container="); ok {
+			return name
+		}
+	}
+	return ""
+}
+
+func readTrimmed(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,49 @@
+//go:build linux
+
+package proc
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestClassifySandbox(t *testing.T) {
+	host := "         0          0 4294967295\n"
+	tests := []struct {
+		name    string
+		facts   sandboxFacts
+		runtime string
+	}{
+		{"host", sandboxFacts{version: "Linux version 6.8.0 #1 SMP", uidMap: host}, ""},
+		{"gvisor", sandboxFacts{version: "Linux version 4.4.0 " + gvisorVersion, uidMap: host}, "gVisor"},
+		{"firecracker dmi", sandboxFacts{dmiVendor: "Firecracker", uidMap: host}, "Firecracker microVM"},
+		{"firecracker mmio", sandboxFacts{cmdline: "console=ttyS0 reboot=k virtio_mmio.device=4K@0xd0000000:5", uidMap: host}, "Firecracker microVM"},
+		{"lxc", sandboxFacts{uidMap: "0 100000 65536\n", container: "lxc"}, "user namespace (lxc)"},
+	}
+	for _, tt := range tests {
+		sb := classifySandbox(tt.facts)
+		got := ""
+		if sb != nil {
+			got = sb.Runtime
+		}
+		if got != tt.runtime {
+			t.Errorf("%s: runtime = %q, want %q", tt.name, got, tt.runtime)
+		}
+	}
+	if sb := classifySandbox(sandboxFacts{version: gvisorVersion}); !slices.Contains(sb.Skipped, ProbeCgroup) {
+		t.Errorf("gVisor skips %v, want cgroup among them", sb.Skipped)
+	}
+}
+
+func TestUserNamespaceRoot(t *testing.T) {
+	if uid, ok := userNamespaceRoot("0 100000 65536\n"); !ok || uid != "100000" {
+		t.Errorf("userNamespaceRoot(lxc) = %q, %v", uid, ok)
+	}
+	// Rootless podman maps the user to root and subuids above it
+	if uid, ok := userNamespaceRoot("0 1000 1\n1 100000 65536\n"); !ok || uid != "1000" {
+		t.Errorf("userNamespaceRoot(rootless) = %q, %v", uid, ok)
+	}
+	if _, ok := userNamespaceRoot("0 0 4294967295\n"); ok {
+		t.Error("initial namespace reported as a user namespace")
+	}
+}
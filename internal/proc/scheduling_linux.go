@@ -0,0 +1,52 @@
+//go:build linux
+
+package proc
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// Scheduling policies by number (linux/sched.h)
+var schedPolicies = map[int]string{
+	0: "SCHED_OTHER", 1: "SCHED_FIFO", 2: "SCHED_RR", 3: "SCHED_BATCH", 5: "SCHED_IDLE", 6: "SCHED_DEADLINE",
+}
+
+// readScheduling reads the niceness, policy and realtime priority from the
+// stat fields after the command, the affinity from status and the OOM score
+func readScheduling(pid int, fields []string, status map[string]string) *model.Scheduling {
+	base := "/proc/" + strconv.Itoa(pid) + "/"
+	adj, err := os.ReadFile(base + "oom_score_adj")
+	if err != nil {
+		return nil
+	}
+	score, _ := os.ReadFile(base + "oom_score")
+	online, _ := os.ReadFile("/sys/devices/system/cpu/online")
+	return parseScheduling(fields, status["Cpus_allowed_list"], strings.TrimSpace(string(online)),
+		strings.TrimSpace(string(adj)), strings.TrimSpace(string(score)))
+}
+
+// parseScheduling builds the scheduling settings; in fields (stat after the
+// command) nice is at 16, rt_priority at 37 and policy at 38
+func parseScheduling(fields []string, cpus, online, oomScoreAdj, oomScore string) *model.Scheduling {
+	s := &model.Scheduling{CPUs: cpus}
+	if len(fields) > 16 {
+		s.Nice, _ = strconv.Atoi(fields[16])
+	}
+	if len(fields) > 38 {
+		policy, _ := strconv.Atoi(fields[38])
+		if s.Policy = schedPolicies[policy]; s.Policy == "" {
+			s.Policy = "policy " + fields[38]
+		}
+		if s.Realtime() {
+			s.RTPriority, _ = strconv.Atoi(fields[37])
+		}
+	}
+	s.Pinned = cpus != "" && online != "" && cpus != online
+	s.OOMScoreAdj, _ = strconv.Atoi(oomScoreAdj)
+	s.OOMScore, _ = strconv.Atoi(oomScore)
+	return s
+}
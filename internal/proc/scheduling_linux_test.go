@@ -0,0 +1,27 @@
+//go:build linux
+
+package proc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseScheduling(t *testing.T) {
+	// stat of a SCHED_FIFO process at priority 50 and nice 0, after "(comm) "
+	stat := "S 1 812 812 0 -1 4194560 230 0 0 0 12 4 0 0 -51 0 1 0 1840 12288000 410 18446744073709551615 1 1 0 0 0 0 0 4096 0 0 0 0 17 1 50 1 0 0 0 0 0 0 0 0 0 0 0"
+	s := parseScheduling(strings.Fields(stat), "0-1", "0-7", "-1000", "0")
+	if s.Policy != "SCHED_FIFO" || s.RTPriority != 50 || s.Nice != 0 || !s.Realtime() {
+		t.Errorf("policy = %+v", s)
+	}
+	if !s.Pinned || s.OOMScoreAdj != -1000 {
+		t.Errorf("affinity, oom = %+v", s)
+	}
+
+	// nice 10 under SCHED_OTHER on all CPUs
+	stat = "S 1 900 900 0 -1 4194560 0 0 0 0 0 0 0 0 30 10 1 0 1840 0 0 0 1 1 0 0 0 0 0 0 0 0 0 0 17 0 0 0 0 0 0 0 0 0 0 0 0 0 0"
+	s = parseScheduling(strings.Fields(stat), "0-7", "0-7", "200", "867")
+	if s.Policy != "SCHED_OTHER" || s.RTPriority != 0 || s.Nice != 10 || s.Pinned || s.OOMScore != 867 {
+		t.Errorf("other = %+v", s)
+	}
+}
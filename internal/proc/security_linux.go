@@ -0,0 +1,107 @@
+//go:build linux
+
+package proc
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// readSecurityLabel returns the AppArmor or SELinux label of a process, or
+// nil when neither module is active
+func readSecurityLabel(pid int) *model.SecurityLabel {
+	if probeSkipped(ProbeConfinement) {
+		return nil
+	}
+	base := "/proc/" + strconv.Itoa(pid) + "/attr/"
+	lsm := activeLSM()
+	switch lsm {
+	case "apparmor":
+		// Kernels with LSM stacking keep the AppArmor label in its own file
+		label := readAttr(base + "apparmor/current")
+		if label == "" {
+			label = readAttr(base + "current")
+		}
+		if label == "" {
+			return nil
+		}
+		profile, mode := parseAppArmorLabel(label)
+		return &model.SecurityLabel{
+			LSM:      lsm,
+			Label:    label,
+			Profile:  profile,
+			Mode:     mode,
+			Confined: mode == "enforce" || mode == "kill",
+		}
+	case "selinux":
+		label := readAttr(base + "current")
+		if label == "" {
+			return nil
+		}
+		mode := "permissive"
+		if data, err := os.ReadFile("/sys/fs/selinux/enforce"); err == nil && strings.TrimSpace(string(data)) == "1" {
+			mode = "enforcing"
+		}
+		domain := selinuxType(label)
+		return &model.SecurityLabel{
+			LSM:      lsm,
+			Label:    label,
+			Profile:  domain,
+			Mode:     mode,
+			Confined: mode == "enforcing" && !strings.Contains(domain, "unconfined"),
+		}
+	}
+	return nil
+}
+
+// activeLSM names the major security module in use, preferring the list the
+// kernel publishes in securityfs
+func activeLSM() string {
+	if data, err := os.ReadFile("/sys/kernel/security/lsm"); err == nil {
+		for name := range strings.SplitSeq(strings.TrimSpace(string(data)), ",") {
+			if name == "apparmor" || name == "selinux" {
+				return name
+			}
+		}
+		return ""
+	}
+	if _, err := os.Stat("/sys/fs/selinux/enforce"); err == nil {
+		return "selinux"
+	}
+	if data, err := os.ReadFile("/sys/module/apparmor/parameters/enabled"); err == nil && strings.TrimSpace(string(data)) == "Y" {
+		return "apparmor"
+	}
+	return ""
+}
+
+func readAttr(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimRight(string(data), "\x00"))
+}
+
+// parseAppArmorLabel splits a label such as "/usr/sbin/cupsd (enforce)" into
+// the profile and its mode; "unconfined" has no profile
+func parseAppArmorLabel(label string) (profile, mode string) {
+	if label == "unconfined" {
+		return "unconfined", "unconfined"
+	}
+	if i := strings.LastIndex(label, " ("); i >= 0 && strings.HasSuffix(label, ")") {
+		return label[:i], label[i+2 : len(label)-1]
+	}
+	return label, ""
+}
+
+// selinuxType returns the type of a user:role:type:level context
+func selinuxType(context string) string {
+	parts := strings.SplitN(context, ":", 4)
+	if len(parts) < 3 {
+		return context
+	}
+	return parts[2]
+}
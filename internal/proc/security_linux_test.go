@@ -0,0 +1,31 @@
+//go:build linux
+
+package proc
+
+import "testing"
+
+func TestParseAppArmorLabel(t *testing.T) {
+	tests := []struct {
+		label, profile, mode string
+	}{
+		{"unconfined", "unconfined", "unconfined"},
+		{"/usr/sbin/cupsd (enforce)", "/usr/sbin/cupsd", "enforce"},
+		{"snap.lxd.daemon (complain)", "snap.lxd.daemon", "complain"},
+		{"docker-default", "docker-default", ""},
+	}
+	for _, tt := range tests {
+		profile, mode := parseAppArmorLabel(tt.label)
+		if profile != tt.profile || mode != tt.mode {
+			t.Errorf("parseAppArmorLabel(%q) = %q, %q; want %q, %q", tt.label, profile, mode, tt.profile, tt.mode)
+		}
+	}
+}
+
+func TestSELinuxType(t *testing.T) {
+	if got := selinuxType("system_u:system_r:httpd_t:s0"); got != "httpd_t" {
+		t.Errorf("selinuxType() = %q, want httpd_t", got)
+	}
+	if got := selinuxType("unconfined_u:unconfined_r:unconfined_t:s0-s0:c0.c1023"); got != "unconfined_t" {
+		t.Errorf("selinuxType(mls) = %q, want unconfined_t", got)
+	}
+}
@@ -0,0 +1,71 @@
+package proc
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// Keyless verification contacts the transparency log, so allow it some time
+const signatureTimeout = 20 * time.Second
+
+// VerifySignature checks a detached Sigstore signature shipped next to exe
+// (exe.sig with exe.pem or exe.bundle, or exe.sigstore.json) with cosign,
+// recording the result in in. It returns in, allocating it when nil and a
+// signature was found.
+func VerifySignature(in *model.Integrity, exe string) *model.Integrity {
+	if exe == "" {
+		return in
+	}
+	var args []string
+	switch {
+	case exists(exe + ".sigstore.json"):
+		args = []string{"--bundle", exe + ".sigstore.json"}
+	case exists(exe + ".bundle"):
+		args = []string{"--bundle", exe + ".bundle"}
+	case exists(exe+".sig") && exists(exe+".pem"):
+		args = []string{"--signature", exe + ".sig", "--certificate", exe + ".pem"}
+	case exists(exe + ".sig"):
+		args = []string{"--signature", exe + ".sig"}
+	default:
+		return in
+	}
+	if in == nil {
+		in = &model.Integrity{}
+	}
+	if _, err := exec.LookPath("cosign"); err != nil {
+		in.Signature = "signature found (" + args[1] + ") but cosign is not installed"
+		return in
+	}
+
+	// Only the signature is checked; pinning the signer identity is left to
+	// dedicated policy tooling
+	args = append([]string{"verify-blob"}, args...)
+	args = append(args, "--certificate-identity-regexp", ".*", "--certificate-oidc-issuer-regexp", ".*", exe)
+	ctx, cancel := context.WithTimeout(context.Background(), signatureTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "cosign", args...).CombinedOutput()
+	if err != nil {
+		msg := strings.TrimSpace(string(out))
+		if i := strings.LastIndexByte(msg, '\n'); i != -1 {
+			msg = msg[i+1:]
+		}
+		if msg == "" {
+			msg = err.Error()
+		}
+		in.Signature = "verification failed: " + msg
+		return in
+	}
+	in.Signature = "verified by cosign (" + args[2] + ")"
+	in.SignatureOK = true
+	return in
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
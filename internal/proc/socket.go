@@ -1,7 +1,16 @@
 package proc
 
+import "github.com/pranshuparmar/witr/pkg/model"
+
 type Socket struct {
-	Inode   string
-	Port    int
-	Address string // 0.0.0.0, 127.0.0.1, ::
+	Inode    string
+	Protocol string // tcp, tcp6, udp, udp6, unix
+	Port     int
+	Address  string // 0.0.0.0, 127.0.0.1, ::
+	// Path is set for Unix sockets
+	Path string
+}
+
+func (s Socket) model() model.ListenSocket {
+	return model.ListenSocket{Protocol: s.Protocol, Address: s.Address, Port: s.Port, Path: s.Path}
 }
@@ -0,0 +1,44 @@
+//go:build linux
+
+package proc
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// readStatus parses /proc/<pid>/status into a map of field name to value
+func readStatus(pid int) map[string]string {
+	status := make(map[string]string)
+
+	f, err := os.Open("/proc/" + strconv.Itoa(pid) + "/status")
+	if err != nil {
+		return status
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, val, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		status[key] = strings.TrimSpace(val)
+	}
+	return status
+}
+
+// statusIDs parses a Uid/Gid status line: real, effective, saved, filesystem
+func statusIDs(val string) []int {
+	var ids []int
+	for _, f := range strings.Fields(val) {
+		id, err := strconv.Atoi(f)
+		if err != nil {
+			return nil
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
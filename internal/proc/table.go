@@ -0,0 +1,41 @@
+package proc
+
+import "sync"
+
+// tableCache holds the process table while CacheTable is in effect
+var tableCache struct {
+	sync.Mutex
+	active bool
+	table  map[int]tableEntry
+}
+
+// CacheTable makes every walk of the process tree (ancestry, family, task
+// counts) share one read of the process table until release is called, so
+// explaining every process on a host reads the table once rather than once
+// per process. Processes started after the first read are not in it.
+func CacheTable() (release func()) {
+	tableCache.Lock()
+	tableCache.active, tableCache.table = true, nil
+	tableCache.Unlock()
+	return func() {
+		tableCache.Lock()
+		tableCache.active, tableCache.table = false, nil
+		tableCache.Unlock()
+	}
+}
+
+// processTable returns the process table, read once while CacheTable is in
+// effect and afresh otherwise. Callers must not modify it.
+func processTable() map[int]tableEntry {
+	tableCache.Lock()
+	if !tableCache.active {
+		tableCache.Unlock()
+		return readProcessTable()
+	}
+	// Concurrent first callers wait for a single read
+	defer tableCache.Unlock()
+	if tableCache.table == nil {
+		tableCache.table = readProcessTable()
+	}
+	return tableCache.table
+}
@@ -0,0 +1,44 @@
+//go:build darwin
+
+package proc
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// tableEntry is the minimal per-process data needed to walk the process tree
+type tableEntry struct {
+	PPID    int
+	Command string
+	// Threads is not listed by ps -o and stays 0
+	Threads int
+	RSS     uint64
+}
+
+// readProcessTable lists the parent, command and RSS of every process with
+// one ps call
+func readProcessTable() map[int]tableEntry {
+	table := make(map[int]tableEntry)
+	out, err := exec.Command("ps", "-axo", "pid=,ppid=,rss=,comm=").Output()
+	if err != nil {
+		return table
+	}
+	for line := range strings.Lines(string(out)) {
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			continue
+		}
+		pid, err1 := strconv.Atoi(fields[0])
+		ppid, err2 := strconv.Atoi(fields[1])
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		// rss is in KiB
+		rss, _ := strconv.ParseUint(fields[2], 10, 64)
+		table[pid] = tableEntry{PPID: ppid, Command: filepath.Base(strings.Join(fields[3:], " ")), RSS: rss * 1024}
+	}
+	return table
+}
@@ -0,0 +1,66 @@
+//go:build linux
+
+package proc
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// tableEntry is the minimal per-process data needed to walk the process tree
+type tableEntry struct {
+	PPID    int
+	Command string
+	Threads int
+	RSS     uint64
+	// UID owns the process's /proc entry, its effective user
+	UID int
+}
+
+var pageSize = uint64(os.Getpagesize())
+
+// readProcessTable reads the parent, command, thread count, RSS and owner of
+// every process from /proc
+func readProcessTable() map[int]tableEntry {
+	table := make(map[int]tableEntry)
+	entries, _ := os.ReadDir("/proc")
+	for _, e := range entries {
+		pid, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+		f, err := os.Open("/proc/" + e.Name() + "/stat")
+		if err != nil {
+			continue
+		}
+		fi, statErr := f.Stat()
+		data, err := io.ReadAll(f)
+		f.Close()
+		if err != nil || statErr != nil {
+			continue
+		}
+		uid := -1
+		if st, ok := fi.Sys().(*syscall.Stat_t); ok {
+			uid = int(st.Uid)
+		}
+		// pid (comm) state ppid ...; comm may contain spaces and parentheses
+		stat := string(data)
+		open := strings.IndexByte(stat, '(')
+		end := strings.LastIndexByte(stat, ')')
+		if open == -1 || end < open {
+			continue
+		}
+		fields := strings.Fields(stat[end+1:])
+		if len(fields) < 22 {
+			continue
+		}
+		ppid, _ := strconv.Atoi(fields[1])
+		threads, _ := strconv.Atoi(fields[17])
+		rssPages, _ := strconv.ParseUint(fields[21], 10, 64)
+		table[pid] = tableEntry{PPID: ppid, Command: stat[open+1 : end], Threads: threads, RSS: rssPages * pageSize, UID: uid}
+	}
+	return table
+}
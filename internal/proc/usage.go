@@ -0,0 +1,24 @@
+package proc
+
+import "time"
+
+// CPUSampleInterval is how long SampleCPU watches a process
+const CPUSampleInterval = 200 * time.Millisecond
+
+// SampleCPU returns the share of one CPU a process used over interval, as
+// top reports it (above 100% for multi-threaded processes). It returns nil
+// if the process could not be read.
+func SampleCPU(pid int, interval time.Duration) *float64 {
+	before, ok := cpuTime(pid)
+	if !ok {
+		return nil
+	}
+	start := time.Now()
+	time.Sleep(interval)
+	after, ok := cpuTime(pid)
+	if !ok {
+		return nil
+	}
+	pct := float64(after-before) / float64(time.Since(start)) * 100
+	return &pct
+}
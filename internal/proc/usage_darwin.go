@@ -0,0 +1,40 @@
+//go:build darwin
+
+package proc
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cpuTime returns the user plus system CPU time a process has used
+func cpuTime(pid int) (time.Duration, bool) {
+	out, err := exec.Command("ps", "-p", strconv.Itoa(pid), "-o", "time=").Output()
+	if err != nil {
+		return 0, false
+	}
+	field := strings.TrimSpace(string(out))
+	return parsePSTime(field), field != ""
+}
+
+// parsePSTime parses the ps time column, [[dd-]hh:]mm:ss[.ss]
+func parsePSTime(s string) time.Duration {
+	var d time.Duration
+	if days, rest, ok := strings.Cut(s, "-"); ok {
+		n, _ := strconv.Atoi(days)
+		d += time.Duration(n) * 24 * time.Hour
+		s = rest
+	}
+	parts := strings.Split(s, ":")
+	secs, _ := strconv.ParseFloat(parts[len(parts)-1], 64)
+	d += time.Duration(secs * float64(time.Second))
+	unit := time.Minute
+	for i := len(parts) - 2; i >= 0; i-- {
+		n, _ := strconv.Atoi(parts[i])
+		d += time.Duration(n) * unit
+		unit *= 60
+	}
+	return d
+}
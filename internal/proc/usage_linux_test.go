@@ -0,0 +1,15 @@
+//go:build linux
+
+package proc
+
+import "testing"
+
+func TestParseMemTotal(t *testing.T) {
+	meminfo := "MemTotal:       16311864 kB\nMemFree:         1024000 kB\n"
+	if got, want := parseMemTotal(meminfo), uint64(16311864*1024); got != want {
+		t.Errorf("parseMemTotal() = %d, want %d", got, want)
+	}
+	if got := parseMemTotal("MemFree: 1 kB\n"); got != 0 {
+		t.Errorf("parseMemTotal() without MemTotal = %d, want 0", got)
+	}
+}
@@ -22,7 +22,10 @@ func readUser(pid int) string {
 		return "unknown"
 	}
 
-	uid := int(stat.Uid)
+	return resolveUID(int(stat.Uid))
+}
+
+func resolveUID(uid int) string {
 	if uid == 0 {
 		return "root"
 	}
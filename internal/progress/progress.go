@@ -0,0 +1,139 @@
+// Package progress reports the progress of long operations (scans,
+// multi-target and remote lookups) on stderr, either as a spinner for people
+// or as JSON events for wrappers and TUIs.
+package progress
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Modes accepted by New
+const (
+	ModeAuto  = "auto"
+	ModeNone  = "none"
+	ModeHuman = "human"
+	ModeJSON  = "json"
+)
+
+// interval throttles redraws and progress events
+const interval = 100 * time.Millisecond
+
+var spinner = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// Event is one line of --progress json output
+type Event struct {
+	Event string    `json:"event"` // start, progress, end
+	Op    string    `json:"op"`
+	Done  int       `json:"done"`
+	Total int       `json:"total,omitempty"`
+	Item  string    `json:"item,omitempty"`
+	Time  time.Time `json:"time"`
+	// ElapsedMS is set on end events
+	ElapsedMS int64 `json:"elapsed_ms,omitempty"`
+}
+
+// Reporter reports the progress of one operation at a time. A nil Reporter
+// reports nothing, and Step may be called from several goroutines.
+type Reporter struct {
+	w    io.Writer
+	json bool
+
+	mu      sync.Mutex
+	op      string
+	total   int
+	done    int
+	started time.Time
+	last    time.Time
+	frame   int
+}
+
+// New returns a reporter writing to w in mode. Auto draws a spinner when w
+// is a terminal and stays quiet otherwise; none returns nil.
+func New(mode string, w io.Writer) (*Reporter, error) {
+	switch mode {
+	case ModeAuto, "":
+		if f, ok := w.(*os.File); !ok || !isTerminal(f) {
+			return nil, nil
+		}
+		return &Reporter{w: w}, nil
+	case ModeNone:
+		return nil, nil
+	case ModeHuman:
+		return &Reporter{w: w}, nil
+	case ModeJSON:
+		return &Reporter{w: w, json: true}, nil
+	}
+	return nil, fmt.Errorf("unknown progress mode %q (want auto, none, human or json)", mode)
+}
+
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// Start begins an operation over total items (0 when unknown)
+func (r *Reporter) Start(op string, total int) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.op, r.total, r.done = op, total, 0
+	r.started = time.Now()
+	r.last = time.Time{}
+	r.emit("start", "")
+}
+
+// Step records that one more item, described by item, is finished
+func (r *Reporter) Step(item string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.done++
+	if now := time.Now(); now.Sub(r.last) >= interval || r.done == r.total {
+		r.last = now
+		r.emit("progress", item)
+	}
+}
+
+// Finish ends the operation, clearing the spinner line
+func (r *Reporter) Finish() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.emit("end", "")
+}
+
+func (r *Reporter) emit(event, item string) {
+	if r.json {
+		e := Event{Event: event, Op: r.op, Done: r.done, Total: r.total, Item: item, Time: time.Now()}
+		if event == "end" {
+			e.ElapsedMS = time.Since(r.started).Milliseconds()
+		}
+		data, _ := json.Marshal(e)
+		fmt.Fprintf(r.w, "%s\n", data)
+		return
+	}
+	if event == "end" {
+		fmt.Fprint(r.w, "\r\033[K")
+		return
+	}
+	line := fmt.Sprintf("%s %s", spinner[r.frame%len(spinner)], r.op)
+	r.frame++
+	if r.total > 0 {
+		line += fmt.Sprintf(" %d/%d", r.done, r.total)
+	}
+	if item != "" {
+		line += " (" + item + ")"
+	}
+	fmt.Fprintf(r.w, "\r\033[K%s", line)
+}
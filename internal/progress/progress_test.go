@@ -0,0 +1,51 @@
+package progress
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestJSONEvents(t *testing.T) {
+	var buf bytes.Buffer
+	r, err := New(ModeJSON, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Start("scan", 3)
+	r.Step("pid 10")
+	r.Step("pid 11")
+	r.Step("pid 12")
+	r.Finish()
+
+	var events []Event
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("invalid event %q: %v", scanner.Text(), err)
+		}
+		events = append(events, e)
+	}
+	// Steps are throttled, but the first and last always report
+	if len(events) < 4 || events[0].Event != "start" || events[len(events)-1].Event != "end" {
+		t.Fatalf("events = %+v", events)
+	}
+	if last := events[len(events)-2]; last.Event != "progress" || last.Done != 3 || last.Item != "pid 12" {
+		t.Errorf("final progress event = %+v", last)
+	}
+}
+
+func TestNilReporter(t *testing.T) {
+	r, err := New(ModeNone, nil)
+	if err != nil || r != nil {
+		t.Fatalf("New(none) = %v, %v", r, err)
+	}
+	r.Start("scan", 1)
+	r.Step("x")
+	r.Finish()
+	if _, err := New("bar", nil); err == nil {
+		t.Error("New(bar) succeeded")
+	}
+}
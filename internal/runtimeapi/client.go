@@ -0,0 +1,137 @@
+// Package runtimeapi is the shared client layer for container runtime and
+// orchestrator CLIs (docker, podman, crictl, lxc, ...). Every call gets a
+// timeout, transient failures are retried, and a per-tool circuit breaker
+// stops witr from waiting on a daemon that has already hung, so callers fall
+// back to procfs-only attribution.
+package runtimeapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// Policy controls timeouts, retries and the circuit breaker
+type Policy struct {
+	// Timeout bounds a single invocation
+	Timeout time.Duration
+	// Retries is how many times a timed-out or failed-to-start call is retried
+	Retries int
+	// Backoff is the pause before each retry
+	Backoff time.Duration
+	// Threshold is the number of consecutive failures that opens the breaker
+	Threshold int
+	// Cooldown is how long an open breaker rejects calls before trying again
+	Cooldown time.Duration
+}
+
+// DefaultPolicy keeps the worst case for a hung daemon to a few seconds
+var DefaultPolicy = Policy{
+	Timeout:   2 * time.Second,
+	Retries:   1,
+	Backoff:   100 * time.Millisecond,
+	Threshold: 2,
+	Cooldown:  30 * time.Second,
+}
+
+// ErrCircuitOpen is returned without running the tool while its breaker is open
+var ErrCircuitOpen = errors.New("runtime API unavailable (circuit open)")
+
+type breaker struct {
+	failures  int
+	openUntil time.Time
+}
+
+var (
+	mu       sync.Mutex
+	breakers = make(map[string]*breaker)
+)
+
+// Output runs a runtime CLI and returns its stdout, like exec.Cmd.Output.
+// A non-zero exit means the runtime answered (e.g. "no such container") and
+// is returned as-is; timeouts and start failures count against the breaker.
+func Output(tool string, args ...string) ([]byte, error) {
+	return DefaultPolicy.Output(tool, args...)
+}
+
+// CombinedOutput is Output with stderr included, for commands such as
+// `docker logs` that relay a container's stderr on their own
+func CombinedOutput(tool string, args ...string) ([]byte, error) {
+	return DefaultPolicy.call(tool, args, true)
+}
+
+// Output runs a runtime CLI under this policy
+func (p Policy) Output(tool string, args ...string) ([]byte, error) {
+	return p.call(tool, args, false)
+}
+
+func (p Policy) call(tool string, args []string, combined bool) ([]byte, error) {
+	var lastErr error
+	for attempt := 0; attempt <= p.Retries; attempt++ {
+		if !allow(tool) {
+			if lastErr != nil {
+				return nil, lastErr
+			}
+			return nil, fmt.Errorf("%s: %w", tool, ErrCircuitOpen)
+		}
+		if attempt > 0 {
+			time.Sleep(p.Backoff)
+		}
+
+		out, err := p.run(tool, args, combined)
+		var exitErr *exec.ExitError
+		if err == nil || (errors.As(err, &exitErr) && !errors.Is(err, context.DeadlineExceeded)) {
+			record(tool, p, true)
+			return out, err
+		}
+		record(tool, p, false)
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+func (p Policy) run(tool string, args []string, combined bool) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.Timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, tool, args...)
+	var out []byte
+	var err error
+	if combined {
+		out, err = cmd.CombinedOutput()
+	} else {
+		out, err = cmd.Output()
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, fmt.Errorf("%s: timed out after %s: %w", tool, p.Timeout, context.DeadlineExceeded)
+	}
+	return out, err
+}
+
+// allow reports whether the tool's breaker lets a call through
+func allow(tool string) bool {
+	mu.Lock()
+	defer mu.Unlock()
+	b := breakers[tool]
+	return b == nil || time.Now().After(b.openUntil)
+}
+
+func record(tool string, p Policy, ok bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	b := breakers[tool]
+	if b == nil {
+		b = &breaker{}
+		breakers[tool] = b
+	}
+	if ok {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= p.Threshold {
+		b.openUntil = time.Now().Add(p.Cooldown)
+	}
+}
@@ -0,0 +1,30 @@
+package runtimeapi
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	p := Policy{Timeout: 50 * time.Millisecond, Retries: 1, Threshold: 2, Cooldown: time.Minute}
+
+	// A hung tool times out twice (one retry) and opens the breaker
+	start := time.Now()
+	if _, err := p.Output("sleep", "5"); err == nil {
+		t.Fatal("Output(sleep 5) succeeded, want timeout")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("Output(sleep 5) took %s, want it bounded by the timeout", elapsed)
+	}
+	if _, err := p.Output("sleep", "5"); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Output() with open breaker = %v, want ErrCircuitOpen", err)
+	}
+
+	// A tool that answers with a non-zero exit does not trip the breaker
+	for i := 0; i < 3; i++ {
+		if _, err := p.Output("false"); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("Output(false) = %v, want exit error", err)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package source
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// detectAppImage reports the .AppImage file behind a process whose
+// executable lives in the AppImage's temporary squashfs mount (/tmp/.mount_*)
+func detectAppImage(ancestry []model.Process) *model.Source {
+	if len(ancestry) == 0 {
+		return nil
+	}
+	target := ancestry[len(ancestry)-1]
+
+	// The AppImage runtime exports its own path and mount point
+	image := envValue(target.Env, "APPIMAGE")
+	mount := envValue(target.Env, "APPDIR")
+	if mount == "" && strings.Contains(target.Exe, "/.mount_") {
+		idx := strings.Index(target.Exe, "/.mount_")
+		end := strings.Index(target.Exe[idx+1:], "/")
+		if end == -1 {
+			mount = target.Exe
+		} else {
+			mount = target.Exe[:idx+1+end]
+		}
+	}
+	if image == "" {
+		// The runtime process that mounted the image is the .AppImage itself
+		for i := len(ancestry) - 1; i >= 0; i-- {
+			if strings.HasSuffix(strings.ToLower(ancestry[i].Exe), ".appimage") {
+				image = ancestry[i].Exe
+				break
+			}
+		}
+	}
+	if image == "" || (mount == "" && !strings.HasSuffix(strings.ToLower(target.Exe), ".appimage")) {
+		return nil
+	}
+
+	details := map[string]string{"appimage": image}
+	var evidence []string
+	if envValue(target.Env, "APPIMAGE") != "" {
+		evidence = append(evidence, "APPIMAGE environment variable is set")
+	} else {
+		evidence = append(evidence, "an ancestor executable is an .AppImage file")
+	}
+	if mount != "" {
+		details["mount"] = mount
+		evidence = append(evidence, "executable is inside the AppImage mount "+mount)
+	}
+	return &model.Source{
+		Type:       model.SourceAppImage,
+		Name:       strings.TrimSuffix(filepath.Base(image), filepath.Ext(image)),
+		Confidence: 0.9,
+		Evidence:   evidence,
+		Details:    details,
+	}
+}
@@ -0,0 +1,74 @@
+package source
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// at spool directories (Debian, RHEL, macOS layouts)
+var atSpoolDirs = []string{"/var/spool/cron/atjobs", "/var/spool/at", "/usr/lib/cron/jobs"}
+
+// detectAt reports jobs queued with at(1) or batch(1). atd runs each job
+// with its spool file as the shell's standard input.
+func detectAt(ancestry []model.Process) *model.Source {
+	for i, p := range ancestry {
+		if p.Command != "atd" && p.Command != "atrun" {
+			continue
+		}
+		src := &model.Source{
+			Type:       model.SourceCron,
+			Name:       "at",
+			Confidence: 0.7,
+			Evidence:   []string{"ancestor is " + p.Command + " (pid " + itoa(p.PID) + ")"},
+		}
+		if j := jobIndex(ancestry, i); j < len(ancestry) {
+			if file := atJobFile(ancestry[j].PID); file != "" {
+				src.Confidence = 0.9
+				src.Evidence = append(src.Evidence, "job shell reads its spool file "+file)
+				src.Details = map[string]string{"entry": file}
+				if job, when, ok := parseAtJobName(filepath.Base(file)); ok {
+					src.Details["job"] = strconv.Itoa(job)
+					src.Details["schedule"] = when.Local().Format("Mon 2006-01-02 15:04")
+				}
+			}
+		}
+		return src
+	}
+	return nil
+}
+
+// atJobFile returns the spool file a job shell reads from
+func atJobFile(pid int) string {
+	link, err := os.Readlink("/proc/" + strconv.Itoa(pid) + "/fd/0")
+	if err != nil {
+		return ""
+	}
+	for _, dir := range atSpoolDirs {
+		if strings.HasPrefix(link, dir+"/") {
+			return link
+		}
+	}
+	return ""
+}
+
+// parseAtJobName decodes spool names like "a0000f01a8b3c2": the queue
+// letter, a 5-digit hex job number and the run time in hex epoch minutes
+func parseAtJobName(name string) (int, time.Time, bool) {
+	if len(name) != 14 {
+		return 0, time.Time{}, false
+	}
+	job, err := strconv.ParseInt(name[1:6], 16, 64)
+	if err != nil {
+		return 0, time.Time{}, false
+	}
+	minutes, err := strconv.ParseInt(name[6:], 16, 64)
+	if err != nil {
+		return 0, time.Time{}, false
+	}
+	return int(job), time.Unix(minutes*60, 0), true
+}
@@ -0,0 +1,47 @@
+//go:build linux
+
+package source
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestDetectAtSkipsForkedAtd(t *testing.T) {
+	spool := t.TempDir()
+	job := filepath.Join(spool, "a0000f01c5a1e4")
+	if err := os.WriteFile(job, []byte("#!/bin/sh\nsleep 10\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	old := atSpoolDirs
+	atSpoolDirs = []string{spool}
+	t.Cleanup(func() { atSpoolDirs = old })
+
+	// A stand-in job shell reading the spool file on stdin
+	f, err := os.Open(job)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	shell := exec.Command("sleep", "10")
+	shell.Stdin = f
+	if err := shell.Start(); err != nil {
+		t.Skip(err)
+	}
+	t.Cleanup(func() { shell.Process.Kill(); shell.Wait() })
+
+	// atd forks a child per job, which runs the job shell
+	src := detectAt([]model.Process{
+		{PID: 1, Command: "systemd"},
+		{PID: 500, Command: "atd"},
+		{PID: 900, Command: "atd"},
+		{PID: shell.Process.Pid, Command: "sh"},
+	})
+	if src == nil || src.Details["entry"] != job || src.Details["job"] != "15" {
+		t.Fatalf("detectAt() = %+v, want job 15 from %s", src, job)
+	}
+}
@@ -0,0 +1,116 @@
+//go:build linux
+
+package source
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// audioRoles explains the processes of the PipeWire and PulseAudio stacks
+var audioRoles = map[string]string{
+	"pipewire":        "PipeWire media server (device access and processing graph)",
+	"pipewire-pulse":  "PulseAudio compatibility server running on PipeWire",
+	"wireplumber":     "PipeWire session manager (stream routing and device policy)",
+	"pipewire-media-": "legacy PipeWire session manager",
+	"pulseaudio":      "PulseAudio sound server",
+	"jackd":           "JACK audio server",
+}
+
+// audioHelpers are processes spawned by sound server modules
+var audioHelpers = map[string]string{
+	"gsettings-helpe": "helper for PulseAudio's module-gsettings",
+	"pulse-helper":    "PulseAudio module helper",
+	"pw-jack":         "JACK client bridged to PipeWire",
+}
+
+// detectAudio explains PipeWire/PulseAudio processes and their module
+// helpers, listing the clients with streams on the server
+func detectAudio(ancestry []model.Process) *model.Source {
+	if len(ancestry) == 0 {
+		return nil
+	}
+	target := ancestry[len(ancestry)-1]
+
+	role, server := audioRoles[target.Command], target.Command
+	if role == "" {
+		helper := audioHelpers[target.Command]
+		if helper == "" || len(ancestry) < 2 || audioRoles[ancestry[len(ancestry)-2].Command] == "" {
+			return nil
+		}
+		role, server = helper, ancestry[len(ancestry)-2].Command
+	}
+
+	details := map[string]string{"role": role}
+	if data, err := os.ReadFile("/proc/" + itoa(target.PID) + "/cgroup"); err == nil {
+		if _, unit := userUnitFromCgroup(string(data)); unit != "" {
+			details["unit"] = unit
+		}
+	}
+	if server == target.Command && server != "wireplumber" {
+		if clients := audioClients(target); len(clients) > 0 {
+			details["clients"] = strings.Join(clients, ", ")
+		}
+	}
+
+	return &model.Source{
+		Type:       model.SourceDesktop,
+		Name:       server,
+		Confidence: 0.8,
+		Evidence:   []string{"process name " + target.Command + " is a known sound server component"},
+		Details:    details,
+	}
+}
+
+// audioClients lists the applications connected to the sound server via the
+// PulseAudio protocol (served natively or by pipewire-pulse)
+func audioClients(server model.Process) []string {
+	cmd := exec.Command("pactl", "list", "clients")
+	if runtime := envValue(server.Env, "XDG_RUNTIME_DIR"); runtime != "" {
+		cmd.Env = append(os.Environ(), "XDG_RUNTIME_DIR="+runtime)
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+	return parsePactlClients(string(out))
+}
+
+// parsePactlClients extracts "name (pid N)" for each client in `pactl list clients`
+func parsePactlClients(out string) []string {
+	var clients []string
+	name, pid := "", ""
+	flush := func() {
+		if name != "" {
+			if pid != "" {
+				clients = append(clients, name+" (pid "+pid+")")
+			} else {
+				clients = append(clients, name)
+			}
+		}
+		name, pid = "", ""
+	}
+	for line := range strings.Lines(out) {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "Client #") {
+			flush()
+			continue
+		}
+		key, val, ok := strings.Cut(line, " = ")
+		if !ok {
+			continue
+		}
+		val = strings.Trim(val, `"`)
+		switch key {
+		case "application.name":
+			name = val
+		case "application.process.id":
+			pid = val
+		}
+	}
+	flush()
+	return clients
+}
@@ -0,0 +1,189 @@
+package source
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// Desktop session managers that launch XDG autostart entries, keyed by comm
+// (truncated to 15 chars), with the desktop they belong to
+var sessionManagers = map[string]string{
+	"gnome-session-b": "GNOME", // gnome-session-binary
+	"gnome-session":   "GNOME",
+	"ksmserver":       "Plasma",
+	"plasma_session":  "Plasma",
+	"startplasma-x11": "Plasma",
+	"startplasma-way": "Plasma",
+	"xfce4-session":   "Xfce",
+	"lxsession":       "LXDE",
+	"lxqt-session":    "LXQt",
+	"mate-session":    "MATE",
+	"cinnamon-sessio": "Cinnamon",
+	"budgie-desktop":  "Budgie",
+	"cosmic-session":  "COSMIC",
+}
+
+// detectAutostart reports the XDG autostart .desktop entry that launched a
+// desktop process, either directly from the session manager or through the
+// systemd xdg-autostart-generator (app-<id>@autostart.service)
+func detectAutostart(ancestry []model.Process) *model.Source {
+	if len(ancestry) < 2 {
+		return nil
+	}
+	target := ancestry[len(ancestry)-1]
+
+	viaSystemd := false
+	if data, err := os.ReadFile("/proc/" + itoa(target.PID) + "/cgroup"); err == nil {
+		viaSystemd = strings.Contains(string(data), "@autostart.service")
+	}
+	viaSession := false
+	for _, p := range ancestry[:len(ancestry)-1] {
+		if _, ok := sessionManagers[p.Command]; ok {
+			viaSession = true
+		}
+	}
+	if !viaSystemd && !viaSession {
+		return nil
+	}
+
+	file, userEntry := findAutostartEntry(target)
+	if file == "" {
+		return nil
+	}
+
+	evidence := []string{"command matches the Exec line of " + file}
+	if viaSystemd {
+		evidence = append(evidence, "cgroup shows a systemd-xdg-autostart unit")
+	} else {
+		evidence = append(evidence, "ancestry includes a desktop session manager")
+	}
+	details := map[string]string{"desktop": file}
+	if userEntry {
+		details["hint"] = "Disable by removing " + file + " or adding Hidden=true to it"
+	} else {
+		details["hint"] = "Disable by copying " + filepath.Base(file) + " to ~/.config/autostart/ and adding Hidden=true"
+	}
+	return &model.Source{
+		Type:       model.SourceDesktop,
+		Name:       "xdg autostart",
+		Confidence: 0.8,
+		Evidence:   evidence,
+		Details:    details,
+	}
+}
+
+// autostartDirs returns XDG autostart directories in precedence order, and
+// whether each belongs to the user
+func autostartDirs(env []string) ([]string, []bool) {
+	var dirs []string
+	var user []bool
+
+	configHome := envValue(env, "XDG_CONFIG_HOME")
+	if configHome == "" {
+		if home := envValue(env, "HOME"); home != "" {
+			configHome = filepath.Join(home, ".config")
+		}
+	}
+	if configHome != "" {
+		dirs = append(dirs, filepath.Join(configHome, "autostart"))
+		user = append(user, true)
+	}
+
+	configDirs := envValue(env, "XDG_CONFIG_DIRS")
+	if configDirs == "" {
+		configDirs = "/etc/xdg"
+	}
+	for _, d := range strings.Split(configDirs, ":") {
+		if d != "" {
+			dirs = append(dirs, filepath.Join(d, "autostart"))
+			user = append(user, false)
+		}
+	}
+	return dirs, user
+}
+
+// findAutostartEntry finds the enabled autostart entry whose Exec matches the target
+func findAutostartEntry(target model.Process) (string, bool) {
+	name := filepath.Base(target.Exe)
+	if target.Exe == "" {
+		name = target.Command
+	}
+
+	dirs, user := autostartDirs(target.Env)
+	seen := make(map[string]bool)
+	for i, dir := range dirs {
+		files, _ := filepath.Glob(filepath.Join(dir, "*.desktop"))
+		for _, file := range files {
+			// A user entry with the same file name overrides the system one
+			base := filepath.Base(file)
+			if seen[base] {
+				continue
+			}
+			seen[base] = true
+
+			data, err := os.ReadFile(file)
+			if err != nil {
+				continue
+			}
+			entry := parseKeyFile(string(data))
+			if entry["Hidden"] == "true" {
+				continue
+			}
+			exec := strings.Fields(entry["Exec"])
+			if len(exec) == 0 {
+				continue
+			}
+			execName := filepath.Base(exec[0])
+			if exec[0] == "env" || exec[0] == "/usr/bin/env" {
+				// Exec=env VAR=value program ...
+				for _, arg := range exec[1:] {
+					if !strings.Contains(arg, "=") {
+						execName = filepath.Base(arg)
+						break
+					}
+				}
+			}
+			// A name taken from comm is cut to commLen characters
+			if execName == name || len(name) == commLen && strings.HasPrefix(execName, name) {
+				return file, user[i]
+			}
+		}
+	}
+	return "", false
+}
+
+// parseKeyFile parses the first group of a freedesktop key file (.desktop,
+// D-Bus .service) into a map of keys to values
+func parseKeyFile(content string) map[string]string {
+	return parseKeyFileGroup(content, "")
+}
+
+// parseKeyFileGroup parses one [group] of a key file; an empty group name
+// selects the first group
+func parseKeyFileGroup(content, group string) map[string]string {
+	values := make(map[string]string)
+	inGroup := false
+	for line := range strings.Lines(content) {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			if inGroup {
+				break
+			}
+			inGroup = group == "" || strings.Trim(line, "[]") == group
+			continue
+		}
+		if !inGroup {
+			continue
+		}
+		if key, val, ok := strings.Cut(line, "="); ok {
+			values[strings.TrimSpace(key)] = strings.TrimSpace(val)
+		}
+	}
+	return values
+}
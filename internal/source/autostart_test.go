@@ -0,0 +1,35 @@
+package source
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestFindAutostartEntryMatchesName(t *testing.T) {
+	config := t.TempDir()
+	dir := filepath.Join(config, "autostart")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, exec := range map[string]string{
+		"sync.desktop":   "Exec=/usr/bin/syncthing-gtk-tray --minimized",
+		"backup.desktop": "Exec=backup",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("[Desktop Entry]\n"+exec+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	env := []string{"XDG_CONFIG_HOME=" + config, "XDG_CONFIG_DIRS=" + filepath.Join(config, "none")}
+
+	// comm is cut to 15 characters
+	if file, _ := findAutostartEntry(model.Process{Command: "syncthing-gtk-t", Env: env}); filepath.Base(file) != "sync.desktop" {
+		t.Errorf("findAutostartEntry(truncated comm) = %q, want sync.desktop", file)
+	}
+	// backup-agent is not the backup entry
+	if file, _ := findAutostartEntry(model.Process{Command: "backup-agent", Env: env}); file != "" {
+		t.Errorf("findAutostartEntry(backup-agent) = %q, want no entry", file)
+	}
+}
@@ -0,0 +1,117 @@
+package source
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+var backupTools = map[string]string{
+	"borgmatic":     "borgmatic",
+	"borg":          "borg",
+	"restic":        "restic",
+	"resticprofile": "resticprofile",
+	"autorestic":    "autorestic",
+	"duplicity":     "duplicity",
+	"duply":         "duply",
+	"veeamagent":    "veeam agent",
+	"veeamservice":  "veeam agent",
+	"veeamjobman":   "veeam agent",
+}
+
+func detectBackup(ancestry []model.Process) *model.Source {
+	// Walk from the root so wrappers (borgmatic, duply) win over the tools they run
+	for i, p := range ancestry {
+		tool, ok := backupTools[strings.ToLower(p.Command)]
+		if !ok {
+			continue
+		}
+
+		details := make(map[string]string)
+		if job := backupJob(p, ancestry[i:]); job != "" {
+			details["job"] = job
+		}
+
+		// The schedule comes from whatever started the backup tool
+		unit := ""
+		for _, q := range ancestry[:i+1] {
+			if q.Service != "" {
+				unit = q.Service
+			}
+		}
+		if unit != "" {
+			details["unit"] = unit
+			if schedule := systemdTimerSchedule(unit); schedule != "" {
+				details["schedule"] = schedule
+			}
+		}
+		if _, ok := details["schedule"]; !ok {
+			if cron := detectCron(ancestry[:i+1]); cron != nil {
+				details["schedule"] = "cron"
+			}
+		}
+
+		return &model.Source{
+			Type:       model.SourceBackup,
+			Name:       tool,
+			Confidence: 0.8,
+			Evidence:   []string{"ancestor " + p.Command + " (pid " + itoa(p.PID) + ") is a known backup tool"},
+			Details:    details,
+		}
+	}
+	return nil
+}
+
+// backupJob derives a job name from the backup tool's command line
+func backupJob(p model.Process, chain []model.Process) string {
+	args := strings.Fields(p.Cmdline)
+	switch strings.ToLower(p.Command) {
+	case "borgmatic":
+		if cfg := flagValue(args, "-c", "--config"); cfg != "" {
+			return strings.TrimSuffix(filepath.Base(cfg), filepath.Ext(cfg))
+		}
+		return "default"
+	case "restic", "borg":
+		if repo := flagValue(args, "-r", "--repo"); repo != "" {
+			return repo
+		}
+		if repo := envValue(chain[len(chain)-1].Env, "RESTIC_REPOSITORY"); repo != "" {
+			return repo
+		}
+		if repo := envValue(chain[len(chain)-1].Env, "BORG_REPO"); repo != "" {
+			return repo
+		}
+	case "resticprofile", "autorestic":
+		if profile := flagValue(args, "-n", "--name"); profile != "" {
+			return profile
+		}
+	case "duply":
+		// duply <profile> <command>
+		if len(args) > 1 {
+			return args[1]
+		}
+	case "duplicity":
+		// The backup target URL is the last argument
+		if len(args) > 1 && strings.Contains(args[len(args)-1], "://") {
+			return args[len(args)-1]
+		}
+	}
+	return ""
+}
+
+// flagValue returns the value of the first matching flag, accepting both
+// "-f value" and "--flag=value" forms
+func flagValue(args []string, names ...string) string {
+	for i, a := range args {
+		for _, name := range names {
+			if a == name && i+1 < len(args) {
+				return args[i+1]
+			}
+			if strings.HasPrefix(a, name+"=") {
+				return strings.TrimPrefix(a, name+"=")
+			}
+		}
+	}
+	return ""
+}
@@ -1,13 +1,59 @@
 package source
 
 import (
+	"encoding/json"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
 
+	"github.com/pranshuparmar/witr/internal/runtimeapi"
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
+// Container IDs appear in cgroup paths as 64 hex characters, e.g.
+// /docker/<id>, /system.slice/docker-<id>.scope, /kubepods/.../cri-containerd-<id>.scope
+var containerIDPattern = regexp.MustCompile(`[0-9a-f]{64}`)
+
+// containerInspect holds the fields of `docker inspect` output witr uses
+type containerInspect struct {
+	ID   string `json:"Id"`
+	Name string `json:"Name"`
+	// Path and Args are the command the runtime started; Image is the
+	// image ID
+	Path  string   `json:"Path"`
+	Args  []string `json:"Args"`
+	Image string   `json:"Image"`
+	// RestartCount counts restarts under the container's restart policy
+	RestartCount int    `json:"RestartCount"`
+	Created      string `json:"Created"`
+	Config       struct {
+		Image      string            `json:"Image"`
+		Labels     map[string]string `json:"Labels"`
+		Entrypoint []string          `json:"Entrypoint"`
+		Cmd        []string          `json:"Cmd"`
+		Env        []string          `json:"Env"`
+	} `json:"Config"`
+	State struct {
+		Pid       int    `json:"Pid"`
+		StartedAt string `json:"StartedAt"`
+	} `json:"State"`
+	// Ports maps "80/tcp" to the host bindings, null when unpublished
+	NetworkSettings struct {
+		Ports map[string][]struct {
+			HostIP   string `json:"HostIp"`
+			HostPort string `json:"HostPort"`
+		} `json:"Ports"`
+	} `json:"NetworkSettings"`
+	Mounts []struct {
+		Type        string `json:"Type"`
+		Name        string `json:"Name"`
+		Source      string `json:"Source"`
+		Destination string `json:"Destination"`
+		RW          bool   `json:"RW"`
+	} `json:"Mounts"`
+}
+
 func detectContainer(ancestry []model.Process) *model.Source {
 	for _, p := range ancestry {
 		data, err := os.ReadFile("/proc/" + itoa(p.PID) + "/cgroup")
@@ -22,24 +68,39 @@ func detectContainer(ancestry []model.Process) *model.Source {
 				Type:       model.SourceContainer,
 				Name:       "docker",
 				Confidence: 0.9,
+				Evidence:   cgroupEvidence(p, "docker"),
+				Details:    containerDetails("docker", containerID(content)),
 			}
 		case strings.Contains(content, "podman"), strings.Contains(content, "libpod"):
-			return &model.Source{
-				Type:       model.SourceContainer,
-				Name:       "podman",
-				Confidence: 0.9,
-			}
+			return podmanSource(p, containerID(content), content)
+		case p.Command == "conmon":
+			// Rootless podman with the cgroupfs manager leaves no libpod cgroup,
+			// but conmon still monitors every container
+			return podmanSource(p, flagValue(strings.Fields(p.Cmdline), "-c", "--cid"), content)
 		case strings.Contains(content, "kubepods"):
 			return &model.Source{
 				Type:       model.SourceContainer,
 				Name:       "kubernetes",
 				Confidence: 0.9,
+				Evidence:   cgroupEvidence(p, "kubepods"),
+				Details:    kubernetesDetails(ancestry[len(ancestry)-1], content),
 			}
 		case strings.Contains(content, "colima"):
 			return &model.Source{
 				Type:       model.SourceContainer,
 				Name:       "colima",
 				Confidence: 0.9,
+				Evidence:   cgroupEvidence(p, "colima"),
+			}
+		case strings.Contains(content, "/lxc.payload."), strings.Contains(content, "/lxc/"):
+			return lxcSource(content)
+		case strings.Contains(content, "nerdctl"):
+			return &model.Source{
+				Type:       model.SourceContainer,
+				Name:       "nerdctl",
+				Confidence: 0.9,
+				Evidence:   cgroupEvidence(p, "nerdctl"),
+				Details:    containerDetails("nerdctl", containerID(content)),
 			}
 		case strings.Contains(content, "containerd"):
 			// Only match containerd if not already matched by docker/kubernetes/colima
@@ -47,12 +108,98 @@ func detectContainer(ancestry []model.Process) *model.Source {
 				Type:       model.SourceContainer,
 				Name:       "containerd",
 				Confidence: 0.8,
+				Evidence:   cgroupEvidence(p, "containerd"),
+				Details:    containerDetails("nerdctl", containerID(content)),
 			}
 		}
 	}
 	return nil
 }
 
+// cgroupEvidence explains a match on a process's cgroup path
+func cgroupEvidence(p model.Process, pattern string) []string {
+	return []string{"cgroup of " + p.Command + " (pid " + itoa(p.PID) + ") matches " + pattern}
+}
+
+// containerID extracts the container ID from cgroup content
+func containerID(cgroup string) string {
+	return containerIDPattern.FindString(cgroup)
+}
+
+// inspectContainer runs `<runtime> inspect` for a container ID
+func inspectContainer(runtime, id string) *containerInspect {
+	if id == "" {
+		return nil
+	}
+	out, err := runtimeapi.Output(runtime, "inspect", id)
+	if err != nil {
+		return nil
+	}
+	var results []containerInspect
+	if err := json.Unmarshal(out, &results); err != nil || len(results) == 0 {
+		return nil
+	}
+	return &results[0]
+}
+
+// podmanSource describes a podman container, noting the owning user for
+// rootless containers (cgroup under user@<uid>.service)
+func podmanSource(p model.Process, id, cgroup string) *model.Source {
+	details := containerDetails("podman", id)
+	if strings.Contains(cgroup, "/user@") || (p.User != "root" && p.User != "unknown" && p.User != "") {
+		if details == nil {
+			details = make(map[string]string)
+			if id != "" {
+				details["container"] = id[:min(12, len(id))]
+			}
+		}
+		details["rootless"] = "yes (owned by " + p.User + ")"
+	}
+	evidence := cgroupEvidence(p, "libpod")
+	if p.Command == "conmon" {
+		evidence = []string{"ancestor is the podman container monitor conmon (pid " + itoa(p.PID) + ")"}
+	}
+	if details["rootless"] != "" {
+		evidence = append(evidence, "container is owned by the unprivileged user "+p.User)
+	}
+	return &model.Source{
+		Type:       model.SourceContainer,
+		Name:       "podman",
+		Confidence: 0.9,
+		Evidence:   evidence,
+		Details:    details,
+	}
+}
+
+// containerDetails describes a container by name and image, including the
+// compose project and service it was started for
+func containerDetails(runtime, id string) map[string]string {
+	info := inspectContainer(runtime, id)
+	if info == nil {
+		return nil
+	}
+	details := map[string]string{
+		"container": strings.TrimPrefix(info.Name, "/") + " (" + id[:12] + ")",
+	}
+	if info.Config.Image != "" {
+		details["image"] = info.Config.Image
+	}
+	labels := info.Config.Labels
+	if project := labels["com.docker.compose.project"]; project != "" {
+		origin := "started by docker compose project '" + project + "'"
+		if service := labels["com.docker.compose.service"]; service != "" {
+			origin += ", service '" + service + "'"
+		}
+		details["origin"] = origin
+		if files := labels["com.docker.compose.project.config_files"]; files != "" {
+			details["compose"] = files
+		} else if dir := labels["com.docker.compose.project.working_dir"]; dir != "" {
+			details["compose"] = dir
+		}
+	}
+	return details
+}
+
 func itoa(n int) string {
 	return strconv.Itoa(n)
 }
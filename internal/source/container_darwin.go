@@ -0,0 +1,10 @@
+//go:build darwin
+
+package source
+
+import "github.com/pranshuparmar/witr/pkg/model"
+
+// ContainerDetails returns nil; containers run inside the runtime's VM
+func ContainerDetails(_ model.Source, _ model.Process) *model.Container {
+	return nil
+}
@@ -0,0 +1,96 @@
+//go:build linux
+
+package source
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// Labels compose sets for its own bookkeeping; the project and service
+// labels already appear in the source details
+var composeBookkeeping = []string{
+	"com.docker.compose.config-hash", "com.docker.compose.container-number", "com.docker.compose.depends_on",
+	"com.docker.compose.image", "com.docker.compose.oneoff", "com.docker.compose.version",
+}
+
+// ContainerDetails returns the image, labels, published ports and mounts of
+// the container a process runs in, or nil for other sources
+func ContainerDetails(src model.Source, p model.Process) *model.Container {
+	if src.Type != model.SourceContainer {
+		return nil
+	}
+	runtime, info := processContainer(src, p)
+	if info == nil {
+		return nil
+	}
+	return buildContainer(runtime, info, inspectImage(runtime, info.Image))
+}
+
+// buildContainer converts inspect output to the model; image may be nil when
+// the image was removed after the container was created
+func buildContainer(runtime string, info *containerInspect, image *imageInspect) *model.Container {
+	c := &model.Container{
+		Runtime: runtime,
+		ID:      info.ID,
+		Name:    strings.TrimPrefix(info.Name, "/"),
+		Image:   info.Config.Image,
+	}
+	var inherited map[string]string
+	if image != nil {
+		c.Digest = imageDigest(info.Config.Image, image.RepoDigests)
+		inherited = image.Config.Labels
+	}
+	for key, val := range info.Config.Labels {
+		if v, ok := inherited[key]; (ok && v == val) || slices.Contains(composeBookkeeping, key) {
+			continue
+		}
+		if c.Labels == nil {
+			c.Labels = make(map[string]string)
+		}
+		c.Labels[key] = val
+	}
+	for _, port := range slices.Sorted(maps.Keys(info.NetworkSettings.Ports)) {
+		bindings := info.NetworkSettings.Ports[port]
+		if len(bindings) == 0 {
+			c.Ports = append(c.Ports, model.PortMapping{ContainerPort: port})
+		}
+		for _, b := range bindings {
+			c.Ports = append(c.Ports, model.PortMapping{ContainerPort: port, HostIP: b.HostIP, HostPort: b.HostPort})
+		}
+	}
+	for _, m := range info.Mounts {
+		c.Mounts = append(c.Mounts, model.Mount{
+			Type:        m.Type,
+			Source:      m.Source,
+			Name:        m.Name,
+			Destination: m.Destination,
+			ReadOnly:    !m.RW,
+		})
+	}
+	return c
+}
+
+// imageDigest picks the digest of the repository the image reference names
+// from RepoDigests ("nginx@sha256:..."), falling back to the first one
+func imageDigest(ref string, repoDigests []string) string {
+	repo := ref
+	if at := strings.Index(repo, "@"); at >= 0 {
+		repo = repo[:at]
+	} else if colon := strings.LastIndex(repo, ":"); colon > strings.LastIndex(repo, "/") {
+		repo = repo[:colon]
+	}
+	for _, d := range repoDigests {
+		if name, digest, ok := strings.Cut(d, "@"); ok && name == repo {
+			return digest
+		}
+	}
+	if len(repoDigests) > 0 {
+		_, digest, _ := strings.Cut(repoDigests[0], "@")
+		return digest
+	}
+	return ""
+}
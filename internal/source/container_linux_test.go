@@ -0,0 +1,65 @@
+//go:build linux
+
+package source
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestBuildContainer(t *testing.T) {
+	var info []containerInspect
+	if err := json.Unmarshal([]byte(`[{
+		"Id": "4f2a9c1e8b7d6a5f4e3d2c1b0a9f8e7d6c5b4a3f2e1d0c9b8a7f6e5d4c3b2a1f",
+		"Name": "/web-1",
+		"Config": {"Image": "nginx:1.27", "Labels": {
+			"maintainer": "NGINX Docker Maintainers",
+			"com.docker.compose.project": "shop",
+			"com.docker.compose.config-hash": "abc",
+			"traefik.enable": "true"
+		}},
+		"NetworkSettings": {"Ports": {
+			"443/tcp": null,
+			"80/tcp": [{"HostIp": "0.0.0.0", "HostPort": "8080"}, {"HostIp": "::", "HostPort": "8080"}]
+		}},
+		"Mounts": [
+			{"Type": "bind", "Source": "/srv/shop/nginx.conf", "Destination": "/etc/nginx/nginx.conf", "RW": false},
+			{"Type": "volume", "Name": "shop_data", "Source": "/var/lib/docker/volumes/shop_data/_data", "Destination": "/data", "RW": true}
+		]
+	}]`), &info); err != nil {
+		t.Fatal(err)
+	}
+	image := &imageInspect{RepoDigests: []string{"mirror.local/nginx@sha256:1111", "nginx@sha256:2222"}}
+	image.Config.Labels = map[string]string{"maintainer": "NGINX Docker Maintainers"}
+
+	c := buildContainer("docker", &info[0], image)
+	if c.Name != "web-1" || c.Digest != "sha256:2222" {
+		t.Errorf("name, digest = %q, %q", c.Name, c.Digest)
+	}
+	if len(c.Labels) != 2 || c.Labels["traefik.enable"] != "true" || c.Labels["com.docker.compose.project"] != "shop" {
+		t.Errorf("labels = %v", c.Labels)
+	}
+	if len(c.Ports) != 3 || c.Ports[0].ContainerPort != "443/tcp" || c.Ports[0].HostPort != "" || c.Ports[2].HostIP != "::" {
+		t.Errorf("ports = %+v", c.Ports)
+	}
+	if len(c.Mounts) != 2 || !c.Mounts[0].ReadOnly || c.Mounts[1].ReadOnly || c.Mounts[1].Name != "shop_data" {
+		t.Errorf("mounts = %+v", c.Mounts)
+	}
+}
+
+func TestImageDigest(t *testing.T) {
+	digests := []string{"docker.io/library/redis@sha256:aaa", "registry.local:5000/redis@sha256:bbb"}
+	for ref, want := range map[string]string{
+		"registry.local:5000/redis:7":    "sha256:bbb",
+		"registry.local:5000/redis":      "sha256:bbb",
+		"docker.io/library/redis:latest": "sha256:aaa",
+		"redis@sha256:ccc":               "sha256:aaa",
+	} {
+		if got := imageDigest(ref, digests); got != want {
+			t.Errorf("imageDigest(%q) = %q, want %q", ref, got, want)
+		}
+	}
+	if got := imageDigest("local-build", nil); got != "" {
+		t.Errorf("locally built image digest = %q", got)
+	}
+}
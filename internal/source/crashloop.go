@@ -0,0 +1,35 @@
+package source
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// crashLoopRestarts restarts within crashLoopWindow make a crash loop
+	crashLoopRestarts = 3
+	crashLoopWindow   = time.Hour
+)
+
+// crashLoopWarning describes a unit or container that keeps restarting.
+// windowed is set when all restarts fell within crashLoopWindow; otherwise
+// only the time of the last one is known.
+func crashLoopWarning(name string, restarts int, windowed bool, sinceLast time.Duration) string {
+	if restarts < crashLoopRestarts || sinceLast > crashLoopWindow {
+		return ""
+	}
+	if windowed {
+		return fmt.Sprintf("Crash loop: %s has restarted %d times in the last hour", name, restarts)
+	}
+	return fmt.Sprintf("Crash loop: %s has restarted %d times, most recently %s ago", name, restarts, roundAge(sinceLast))
+}
+
+// roundAge formats a duration to the minute ("2m", "1h5m"), or to the
+// second under one
+func roundAge(d time.Duration) string {
+	if d < time.Minute {
+		return d.Round(time.Second).String()
+	}
+	return strings.TrimSuffix(d.Round(time.Minute).String(), "0s")
+}
@@ -0,0 +1,11 @@
+//go:build darwin
+
+package source
+
+import "github.com/pranshuparmar/witr/pkg/model"
+
+// CrashLoop returns nil; restart counts are read from systemd and container
+// runtimes on Linux
+func CrashLoop(_ model.Source, _ model.Process, _ *model.ServiceUnit) []model.Warning {
+	return nil
+}
@@ -0,0 +1,60 @@
+//go:build linux
+
+package source
+
+import (
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// CrashLoop warns when the systemd unit (described by svc) or the container
+// a process belongs to keeps restarting, or returns nil
+func CrashLoop(src model.Source, p model.Process, svc *model.ServiceUnit) []model.Warning {
+	var msg string
+	switch {
+	case svc != nil:
+		// The journal dates each restart; NRestarts only counts them
+		if n, ok := journalRestarts(svc.Unit, crashLoopWindow); ok {
+			msg = crashLoopWarning(svc.Unit, n, true, p.Uptime)
+		} else {
+			msg = crashLoopWarning(svc.Unit, svc.NRestarts, false, p.Uptime)
+		}
+	case src.Type == model.SourceContainer:
+		msg = containerCrashLoop(src, p)
+	}
+	if msg == "" {
+		return nil
+	}
+	return []model.Warning{{Code: model.WarnCrashLoop, Severity: model.SeverityHigh, Message: msg}}
+}
+
+// journalRestarts counts the automatic restarts systemd logged for a unit
+// within window, reporting false when the journal cannot be read
+func journalRestarts(unit string, window time.Duration) (int, bool) {
+	since := time.Now().Add(-window).Format("2006-01-02 15:04:05")
+	out, err := exec.Command("journalctl", "-u", unit, "--since", since, "--no-pager", "-o", "cat", "-q").Output()
+	if err != nil {
+		return 0, false
+	}
+	// "Scheduled restart job, restart counter is at 14."
+	return strings.Count(string(out), "Scheduled restart job"), true
+}
+
+// containerCrashLoop reads the restart count the runtime keeps for a
+// container started with a restart policy
+func containerCrashLoop(src model.Source, p model.Process) string {
+	_, info := processContainer(src, p)
+	if info == nil {
+		return ""
+	}
+	started, err := time.Parse(time.RFC3339Nano, info.State.StartedAt)
+	if err != nil {
+		return ""
+	}
+	created, _ := time.Parse(time.RFC3339Nano, info.Created)
+	name := strings.TrimPrefix(info.Name, "/")
+	return crashLoopWarning(name, info.RestartCount, time.Since(created) <= crashLoopWindow, time.Since(started))
+}
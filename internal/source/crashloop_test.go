@@ -0,0 +1,26 @@
+package source
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCrashLoopWarning(t *testing.T) {
+	tests := []struct {
+		restarts  int
+		windowed  bool
+		sinceLast time.Duration
+		want      string
+	}{
+		{14, true, 2 * time.Minute, "Crash loop: web.service has restarted 14 times in the last hour"},
+		{5, false, 90 * time.Second, "Crash loop: web.service has restarted 5 times, most recently 2m ago"},
+		{2, true, time.Minute, ""},
+		// Restarts long ago are history, not a loop
+		{20, false, 3 * time.Hour, ""},
+	}
+	for _, tt := range tests {
+		if got := crashLoopWarning("web.service", tt.restarts, tt.windowed, tt.sinceLast); got != tt.want {
+			t.Errorf("crashLoopWarning(%d, %v, %s) = %q, want %q", tt.restarts, tt.windowed, tt.sinceLast, got, tt.want)
+		}
+	}
+}
@@ -1,16 +1,326 @@
 package source
 
-import "github.com/pranshuparmar/witr/pkg/model"
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// System crontabs carry a user field after the schedule
+var systemCrontabs = []string{"/etc/crontab"}
+var systemCrontabDirs = []string{"/etc/cron.d"}
+
+// Per-user crontabs are named after the user (Debian, RHEL, macOS layouts)
+var userCrontabDirs = []string{"/var/spool/cron/crontabs", "/var/spool/cron", "/usr/lib/cron/tabs"}
+
+type cronEntry struct {
+	File     string
+	Line     int
+	Schedule string
+	User     string
+	Command  string
+}
+
+// jobIndex returns the index of the job run by the scheduler at
+// ancestry[i]. cron, cronie, anacron and atd fork a child of their own
+// before running a job, so the job is the first process below them with
+// another name.
+func jobIndex(ancestry []model.Process, i int) int {
+	j := i + 1
+	for j < len(ancestry) && ancestry[j].Command == ancestry[i].Command {
+		j++
+	}
+	return j
+}
 
 func detectCron(ancestry []model.Process) *model.Source {
-	for _, p := range ancestry {
+	for i, p := range ancestry {
 		if p.Command == "cron" || p.Command == "crond" {
-			return &model.Source{
+			src := &model.Source{
 				Type:       model.SourceCron,
 				Name:       "cron",
 				Confidence: 0.6,
+				Evidence:   []string{"ancestor is " + p.Command + " (pid " + itoa(p.PID) + ")"},
+			}
+			if j := jobIndex(ancestry, i); j < len(ancestry) {
+				if entry, exact := findCronEntry(ancestry[j]); entry != nil {
+					src.Confidence = 0.9
+					match := "child command matches "
+					if !exact {
+						src.Confidence = 0.75
+						match = "child executable matches the only entry running it, "
+					}
+					src.Evidence = append(src.Evidence, match+entry.File+":"+strconv.Itoa(entry.Line))
+					src.Details = map[string]string{
+						"schedule": entry.Schedule,
+						"entry":    entry.File + ":" + strconv.Itoa(entry.Line),
+						"command":  entry.Command,
+					}
+					if entry.User != "" {
+						src.Details["user"] = entry.User
+					}
+				}
+			}
+			return src
+		}
+	}
+	return nil
+}
+
+// findCronEntry locates the crontab line that started child, the process cron
+// forked, and whether its command matched exactly. Cron runs entries
+// through "/bin/sh -c <command>".
+func findCronEntry(child model.Process) (*cronEntry, bool) {
+	cmd := shellCommand(child.Cmdline)
+	if cmd == "" {
+		return nil, false
+	}
+
+	return matchCronEntry(cmd, readCrontabs(child.User))
+}
+
+// matchCronEntry returns the entry whose command is cmd and true or, since
+// cron may rewrite % and quoting, the only entry running the same executable
+// and false. With several such entries nothing tells them apart.
+func matchCronEntry(cmd string, entries []cronEntry) (*cronEntry, bool) {
+	for i := range entries {
+		if strings.TrimSpace(entries[i].Command) == cmd {
+			return &entries[i], true
+		}
+	}
+	exe := cronExe(cmd)
+	var match *cronEntry
+	for i := range entries {
+		if cronExe(entries[i].Command) != exe {
+			continue
+		}
+		if match != nil {
+			return nil, false
+		}
+		match = &entries[i]
+	}
+	return match, false
+}
+
+// cronExe is the executable field of a cron command, followed by the
+// script for shells, which on their own say nothing about the job
+func cronExe(cmd string) string {
+	fields := strings.Fields(cmd)
+	switch {
+	case len(fields) == 0:
+		return ""
+	case len(fields) > 1 && scriptShells[filepath.Base(fields[0])]:
+		return fields[0] + " " + fields[1]
+	}
+	return fields[0]
+}
+
+// shellCommand strips the "/bin/sh -c" wrapper cron-like daemons run
+// commands through
+func shellCommand(cmdline string) string {
+	for _, prefix := range []string{"/bin/sh -c ", "/usr/bin/sh -c ", "sh -c "} {
+		if rest, ok := strings.CutPrefix(cmdline, prefix); ok {
+			cmdline = rest
+			break
+		}
+	}
+	return strings.TrimSpace(cmdline)
+}
+
+func readCrontabs(user string) []cronEntry {
+	var entries []cronEntry
+
+	files := append([]string{}, systemCrontabs...)
+	for _, dir := range systemCrontabDirs {
+		matches, _ := filepath.Glob(filepath.Join(dir, "*"))
+		files = append(files, matches...)
+	}
+	for _, file := range files {
+		if data, err := os.ReadFile(file); err == nil {
+			entries = append(entries, parseCrontab(string(data), file, true)...)
+		}
+	}
+
+	if user != "" && user != "unknown" {
+		for _, dir := range userCrontabDirs {
+			file := filepath.Join(dir, user)
+			if data, err := os.ReadFile(file); err == nil {
+				entries = append(entries, parseCrontab(string(data), file, false)...)
+			}
+		}
+	}
+	return entries
+}
+
+// parseCrontab parses crontab content. System crontabs have a user field
+// between the schedule and the command.
+func parseCrontab(content, file string, system bool) []cronEntry {
+	var entries []cronEntry
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+
+		// Environment assignments (MAILTO=, PATH=) are not entries
+		if eq := strings.Index(fields[0], "="); eq > 0 && !strings.HasPrefix(fields[0], "@") {
+			continue
+		}
+
+		schedFields := 5
+		if strings.HasPrefix(fields[0], "@") {
+			schedFields = 1
+		}
+		need := schedFields + 1
+		if system {
+			need++
+		}
+		if len(fields) < need {
+			continue
+		}
+
+		entry := cronEntry{
+			File:     file,
+			Line:     i + 1,
+			Schedule: strings.Join(fields[:schedFields], " "),
+		}
+		rest := fields[schedFields:]
+		if system {
+			entry.User = rest[0]
+			rest = rest[1:]
+		}
+		entry.Command = strings.Join(rest, " ")
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// anacron reads its jobs from anacrontab; period is in days or @monthly etc.
+var anacrontabs = []string{"/etc/anacrontab"}
+
+type anacronEntry struct {
+	File    string
+	Line    int
+	Period  string
+	Delay   string
+	ID      string
+	Command string
+}
+
+// detectAnacron reports jobs run by anacron, with their anacrontab entry
+func detectAnacron(ancestry []model.Process) *model.Source {
+	for i, p := range ancestry {
+		if p.Command != "anacron" {
+			continue
+		}
+		src := &model.Source{
+			Type:       model.SourceCron,
+			Name:       "anacron",
+			Confidence: 0.6,
+			Evidence:   []string{"ancestor is anacron (pid " + itoa(p.PID) + ")"},
+		}
+		if j := jobIndex(ancestry, i); j < len(ancestry) {
+			if entry, exact := findAnacronEntry(ancestry[j]); entry != nil {
+				src.Confidence = 0.9
+				match := "child command matches "
+				if !exact {
+					src.Confidence = 0.75
+					match = "child executable matches the only entry running it, "
+				}
+				src.Evidence = append(src.Evidence, match+entry.File+":"+strconv.Itoa(entry.Line))
+				src.Details = map[string]string{
+					"job":      entry.ID,
+					"schedule": anacronSchedule(entry.Period, entry.Delay),
+					"entry":    entry.File + ":" + strconv.Itoa(entry.Line),
+					"command":  entry.Command,
+				}
 			}
 		}
+		return src
 	}
 	return nil
 }
+
+// findAnacronEntry locates the anacrontab line for child and whether its
+// command matched exactly, as matchCronEntry does; anacron, like cron, runs
+// commands through "/bin/sh -c <command>"
+func findAnacronEntry(child model.Process) (*anacronEntry, bool) {
+	cmd := shellCommand(child.Cmdline)
+	if cmd == "" {
+		return nil, false
+	}
+
+	var entries []anacronEntry
+	for _, file := range anacrontabs {
+		if data, err := os.ReadFile(file); err == nil {
+			entries = append(entries, parseAnacrontab(string(data), file)...)
+		}
+	}
+	for i := range entries {
+		if strings.TrimSpace(entries[i].Command) == cmd {
+			return &entries[i], true
+		}
+	}
+	exe := cronExe(cmd)
+	var match *anacronEntry
+	for i := range entries {
+		if cronExe(entries[i].Command) != exe {
+			continue
+		}
+		if match != nil {
+			return nil, false
+		}
+		match = &entries[i]
+	}
+	return match, false
+}
+
+// parseAnacrontab parses "period delay job-identifier command" lines
+func parseAnacrontab(content, file string) []anacronEntry {
+	var entries []anacronEntry
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if eq := strings.Index(fields[0], "="); eq > 0 {
+			continue
+		}
+		if len(fields) < 4 {
+			continue
+		}
+		entries = append(entries, anacronEntry{
+			File:    file,
+			Line:    i + 1,
+			Period:  fields[0],
+			Delay:   fields[1],
+			ID:      fields[2],
+			Command: strings.Join(fields[3:], " "),
+		})
+	}
+	return entries
+}
+
+// anacronSchedule describes an anacron period and delay in words
+func anacronSchedule(period, delay string) string {
+	var s string
+	switch period {
+	case "1":
+		s = "daily"
+	case "7":
+		s = "weekly"
+	case "@monthly", "@weekly", "@daily", "@yearly":
+		s = strings.TrimPrefix(period, "@")
+	default:
+		s = "every " + period + " days"
+	}
+	if delay != "" && delay != "0" {
+		s += ", " + delay + " min after anacron starts"
+	}
+	return s
+}
@@ -0,0 +1,145 @@
+package source
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestParseCrontab(t *testing.T) {
+	content := `# m h dom mon dow command
+SHELL=/bin/sh
+MAILTO=ops@example.com
+
+17 * * * * root cd / && run-parts --report /etc/cron.hourly
+@reboot root /usr/local/bin/warmup.sh
+*/5 * * * *
+`
+	entries := parseCrontab(content, "/etc/crontab", true)
+	if len(entries) != 2 {
+		t.Fatalf("parseCrontab() returned %d entries, want 2", len(entries))
+	}
+
+	want := []cronEntry{
+		{File: "/etc/crontab", Line: 5, Schedule: "17 * * * *", User: "root", Command: "cd / && run-parts --report /etc/cron.hourly"},
+		{File: "/etc/crontab", Line: 6, Schedule: "@reboot", User: "root", Command: "/usr/local/bin/warmup.sh"},
+	}
+	for i, w := range want {
+		if entries[i] != w {
+			t.Errorf("entry %d = %+v, want %+v", i, entries[i], w)
+		}
+	}
+}
+
+func TestParseUserCrontab(t *testing.T) {
+	entries := parseCrontab("0 2 * * 1 /home/alice/bin/report --weekly\n", "/var/spool/cron/crontabs/alice", false)
+	if len(entries) != 1 {
+		t.Fatalf("parseCrontab() returned %d entries, want 1", len(entries))
+	}
+	e := entries[0]
+	if e.Schedule != "0 2 * * 1" || e.User != "" || e.Command != "/home/alice/bin/report --weekly" {
+		t.Errorf("unexpected entry %+v", e)
+	}
+}
+
+func TestParseAnacrontab(t *testing.T) {
+	content := `SHELL=/bin/sh
+# period delay job-identifier command
+1	5	cron.daily	run-parts --report /etc/cron.daily
+@monthly	15	cron.monthly	run-parts --report /etc/cron.monthly
+`
+	entries := parseAnacrontab(content, "/etc/anacrontab")
+	if len(entries) != 2 {
+		t.Fatalf("parseAnacrontab() returned %d entries, want 2", len(entries))
+	}
+	e := entries[1]
+	if e.Line != 4 || e.ID != "cron.monthly" || e.Command != "run-parts --report /etc/cron.monthly" {
+		t.Errorf("unexpected entry %+v", e)
+	}
+	if got := anacronSchedule(entries[0].Period, entries[0].Delay); got != "daily, 5 min after anacron starts" {
+		t.Errorf("anacronSchedule() = %q", got)
+	}
+}
+
+func TestParseAtJobName(t *testing.T) {
+	job, when, ok := parseAtJobName("a0000f01c5a1e4")
+	if !ok || job != 15 || when.Unix() != 0x1c5a1e4*60 {
+		t.Errorf("parseAtJobName() = %d, %v, %v", job, when, ok)
+	}
+	if _, _, ok := parseAtJobName(".SEQ"); ok {
+		t.Error("parseAtJobName(.SEQ) ok, want false")
+	}
+}
+
+func TestDetectCronSkipsForkedCron(t *testing.T) {
+	crontab := filepath.Join(t.TempDir(), "crontab")
+	if err := os.WriteFile(crontab, []byte("*/5 * * * * root /usr/local/bin/backup --full\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	oldFiles, oldDirs, oldUserDirs := systemCrontabs, systemCrontabDirs, userCrontabDirs
+	systemCrontabs, systemCrontabDirs, userCrontabDirs = []string{crontab}, nil, nil
+	t.Cleanup(func() { systemCrontabs, systemCrontabDirs, userCrontabDirs = oldFiles, oldDirs, oldUserDirs })
+
+	// Debian cron: cron -f forks another cron, which runs /bin/sh -c <job>
+	src := detectCron([]model.Process{
+		{PID: 1, Command: "systemd"},
+		{PID: 500, Command: "cron", Cmdline: "/usr/sbin/cron -f"},
+		{PID: 900, Command: "cron", Cmdline: "/usr/sbin/cron -f"},
+		{PID: 901, Command: "sh", Cmdline: "/bin/sh -c /usr/local/bin/backup --full", User: "root"},
+		{PID: 902, Command: "backup", Cmdline: "/usr/local/bin/backup --full", User: "root"},
+	})
+	if src == nil || src.Details["schedule"] != "*/5 * * * *" {
+		t.Fatalf("detectCron() = %+v, want the crontab entry", src)
+	}
+}
+
+func TestDetectAnacronSkipsForkedAnacron(t *testing.T) {
+	anacrontab := filepath.Join(t.TempDir(), "anacrontab")
+	if err := os.WriteFile(anacrontab, []byte("1\t5\tcron.daily\trun-parts --report /etc/cron.daily\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	old := anacrontabs
+	anacrontabs = []string{anacrontab}
+	t.Cleanup(func() { anacrontabs = old })
+
+	// anacron forks a child per job, which runs /bin/sh -c <command>
+	src := detectAnacron([]model.Process{
+		{PID: 1, Command: "systemd"},
+		{PID: 500, Command: "anacron", Cmdline: "/usr/sbin/anacron -d -q -s"},
+		{PID: 900, Command: "anacron", Cmdline: "/usr/sbin/anacron -d -q -s"},
+		{PID: 901, Command: "sh", Cmdline: "/bin/sh -c run-parts --report /etc/cron.daily"},
+		{PID: 902, Command: "run-parts", Cmdline: "run-parts --report /etc/cron.daily"},
+	})
+	if src == nil || src.Details["job"] != "cron.daily" {
+		t.Fatalf("detectAnacron() = %+v, want the cron.daily entry", src)
+	}
+}
+
+func TestMatchCronEntry(t *testing.T) {
+	entries := []cronEntry{
+		{Line: 1, Command: "bash /opt/rotate.sh"},
+		{Line: 2, Command: "/usr/bin/php /srv/app/artisan schedule:run"},
+	}
+	if e, exact := matchCronEntry("/usr/bin/php /srv/app/artisan schedule:run", entries); e == nil || e.Line != 2 || !exact {
+		t.Errorf("matchCronEntry(php) = %+v, %v; want line 2, exact", e, exact)
+	}
+	if e, exact := matchCronEntry("/usr/bin/php /srv/app/artisan schedule:run >/dev/null", entries); e == nil || e.Line != 2 || exact {
+		t.Errorf("matchCronEntry(php >/dev/null) = %+v, %v; want line 2 by executable", e, exact)
+	}
+	if e, _ := matchCronEntry("bash /opt/rotate.sh --force", entries); e == nil || e.Line != 1 {
+		t.Errorf("matchCronEntry(rotate) = %+v, want line 1", e)
+	}
+	// Only the executable field counts, and for shells the script too
+	for _, cmd := range []string{"bash /opt/other.sh", "/usr/bin/ph /srv/app/artisan", "/bin/sh -e /srv/app/artisan"} {
+		if e, _ := matchCronEntry(cmd, entries); e != nil {
+			t.Errorf("matchCronEntry(%q) = %+v, want nil", cmd, e)
+		}
+	}
+	// Two entries running the executable cannot be told apart
+	entries = append(entries, cronEntry{Line: 3, Command: "/usr/bin/php /srv/app/artisan queue:work"})
+	if e, _ := matchCronEntry("/usr/bin/php /srv/app/artisan horizon", entries); e != nil {
+		t.Errorf("matchCronEntry(ambiguous) = %+v, want nil", e)
+	}
+}
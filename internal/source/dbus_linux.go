@@ -0,0 +1,234 @@
+//go:build linux
+
+package source
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// Activation directories for each bus, in lookup order
+var dbusServiceDirs = map[string][]string{
+	"session": {"/usr/local/share/dbus-1/services", "/usr/share/dbus-1/services", "/var/lib/flatpak/exports/share/dbus-1/services"},
+	"system":  {"/usr/local/share/dbus-1/system-services", "/usr/share/dbus-1/system-services", "/lib/dbus-1/system-services"},
+}
+
+// dbus-broker starts services without a SystemdService= as transient units
+// named dbus-:<connection>-<bus name>@<n>.service
+var dbusTransientUnit = regexp.MustCompile(`^dbus-:[0-9.]+-(.+)@[0-9]+\.service$`)
+
+// Both bus daemons log who triggered an activation:
+// ... name='org.example.Foo' ... requested by ':1.42' (uid=1000 pid=2345 comm="notify-send hi")
+var dbusRequestPattern = regexp.MustCompile(`requested by '([^']*)' \(uid=(\d+) pid=(\d+) comm="([^"]*)"`)
+
+type dbusService struct {
+	File           string
+	Name           string
+	Exec           string
+	SystemdService string
+}
+
+// detectDBus reports services a bus daemon activated on demand, with the
+// .service activation file and, when the journal recorded it, the client
+// that asked for the service
+func detectDBus(ancestry []model.Process) *model.Source {
+	if len(ancestry) < 2 {
+		return nil
+	}
+	target := ancestry[len(ancestry)-1]
+
+	// Only the bus daemon's own children were activated; their descendants
+	// (the shells of an activated terminal) were started by them
+	bus, busName := "", ""
+	var evidence []string
+	if p := ancestry[len(ancestry)-2]; strings.HasPrefix(p.Command, "dbus-daemon") || strings.HasPrefix(p.Command, "dbus-broker") {
+		bus = busKind(p.Cmdline)
+		evidence = append(evidence, ancestorEvidence(p, "is the "+bus+" bus daemon"))
+	}
+	if bus == "" {
+		data, err := os.ReadFile("/proc/" + itoa(target.PID) + "/cgroup")
+		if err != nil {
+			return nil
+		}
+		uid, unit := userUnitFromCgroup(string(data))
+		bus = "session"
+		if uid == "" {
+			bus, unit = "system", systemUnitFromCgroup(string(data))
+		}
+		m := dbusTransientUnit.FindStringSubmatch(unit)
+		if m == nil {
+			return nil
+		}
+		busName = m[1]
+		evidence = append(evidence, "unit "+unit+" was created by dbus-broker for an activation")
+	}
+
+	svc := findDBusService(bus, busName, target)
+	if svc != nil {
+		busName = svc.Name
+		evidence = append(evidence, "activation file "+svc.File+" matches the command")
+	}
+
+	details := map[string]string{"bus": bus}
+	if app := requestingApp(target); app != "" {
+		details["app"] = app
+	}
+	if svc != nil {
+		details["service file"] = svc.File
+		if svc.SystemdService != "" {
+			details["unit"] = svc.SystemdService
+		}
+	}
+	name := "D-Bus activation"
+	if busName != "" {
+		details["bus name"] = busName
+		name += " (" + busName + ")"
+		if req := dbusRequester(busName, bus); req != "" {
+			details["requested by"] = req
+			evidence = append(evidence, "journal records the activation request")
+		}
+	}
+
+	confidence := 0.7
+	if svc != nil {
+		confidence = 0.9
+	}
+	return &model.Source{
+		Type:       model.SourceDesktop,
+		Name:       name,
+		Confidence: confidence,
+		Evidence:   evidence,
+		Details:    details,
+	}
+}
+
+// busKind tells the system bus from a session bus by the daemon's arguments
+func busKind(cmdline string) string {
+	if strings.Contains(cmdline, "--system") || strings.Contains(cmdline, "--scope system") {
+		return "system"
+	}
+	return "session"
+}
+
+// systemUnitFromCgroup returns the last unit in a system.slice cgroup path
+func systemUnitFromCgroup(cgroup string) string {
+	for line := range strings.Lines(cgroup) {
+		parts := strings.SplitN(strings.TrimSpace(line), ":", 3)
+		if len(parts) != 3 || !strings.Contains(parts[2], "/system.slice/") {
+			continue
+		}
+		return parts[2][strings.LastIndex(parts[2], "/")+1:]
+	}
+	return ""
+}
+
+// findDBusService locates the activation file for busName, or, when the name
+// is not known, the file whose Exec line starts the target
+func findDBusService(bus, busName string, target model.Process) *dbusService {
+	dirs := dbusServiceDirs[bus]
+	if bus == "session" {
+		dataHome := envValue(target.Env, "XDG_DATA_HOME")
+		if dataHome == "" {
+			if home := envValue(target.Env, "HOME"); home != "" {
+				dataHome = filepath.Join(home, ".local/share")
+			}
+		}
+		if dataHome != "" {
+			dirs = append([]string{filepath.Join(dataHome, "dbus-1/services")}, dirs...)
+		}
+	}
+
+	for _, dir := range dirs {
+		files, _ := filepath.Glob(filepath.Join(dir, "*.service"))
+		for _, file := range files {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				continue
+			}
+			svc := parseDBusService(string(data))
+			svc.File = file
+			if busName != "" {
+				if svc.Name == busName {
+					return &svc
+				}
+				continue
+			}
+			if execMatches(svc.Exec, target) {
+				return &svc
+			}
+		}
+	}
+	return nil
+}
+
+// parseDBusService reads the [D-BUS Service] section of an activation file
+func parseDBusService(content string) dbusService {
+	var svc dbusService
+	inSection := false
+	for line := range strings.Lines(content) {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") {
+			inSection = line == "[D-BUS Service]"
+			continue
+		}
+		key, val, ok := strings.Cut(line, "=")
+		if !inSection || !ok {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "Name":
+			svc.Name = strings.TrimSpace(val)
+		case "Exec":
+			svc.Exec = strings.TrimSpace(val)
+		case "SystemdService":
+			svc.SystemdService = strings.TrimSpace(val)
+		}
+	}
+	return svc
+}
+
+func execMatches(execLine string, p model.Process) bool {
+	fields := strings.Fields(execLine)
+	if len(fields) == 0 {
+		return false
+	}
+	if p.Exe != "" && fields[0] == p.Exe {
+		return true
+	}
+	args := strings.Fields(p.Cmdline)
+	return len(args) > 0 && args[0] == fields[0]
+}
+
+// dbusRequester finds the most recent activation request for busName in the
+// journal and describes the client that made it
+func dbusRequester(busName, bus string) string {
+	pattern := "name='" + regexp.QuoteMeta(busName) + "'.*requested by"
+	args := []string{"-b", "-r", "-n", "1", "-o", "cat", "--no-pager", "-g", pattern}
+	out, err := exec.Command("journalctl", args...).Output()
+	if (err != nil || len(out) == 0) && bus == "session" {
+		out, err = exec.Command("journalctl", append([]string{"--user"}, args...)...).Output()
+	}
+	if err != nil {
+		return ""
+	}
+	return parseDBusRequest(string(out))
+}
+
+// parseDBusRequest describes the requesting client in a bus daemon's
+// activation log line
+func parseDBusRequest(line string) string {
+	m := dbusRequestPattern.FindStringSubmatch(line)
+	if m == nil {
+		return ""
+	}
+	comm := m[4]
+	if comm == "" {
+		comm = "unknown"
+	}
+	return comm + " (pid " + m[3] + ", uid " + m[2] + ", connection " + m[1] + ")"
+}
@@ -0,0 +1,58 @@
+//go:build linux
+
+package source
+
+import (
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestParseDBusService(t *testing.T) {
+	svc := parseDBusService(`# activation file
+[D-BUS Service]
+Name=org.freedesktop.Notifications
+Exec=/usr/lib/notification-daemon/notification-daemon
+SystemdService=dunst.service
+`)
+	if svc.Name != "org.freedesktop.Notifications" || svc.Exec != "/usr/lib/notification-daemon/notification-daemon" || svc.SystemdService != "dunst.service" {
+		t.Errorf("parseDBusService() = %+v", svc)
+	}
+}
+
+func TestParseDBusRequest(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{
+			`Activating service name='org.freedesktop.Notifications' requested by ':1.42' (uid=1000 pid=2345 comm="notify-send hello")`,
+			"notify-send hello (pid 2345, uid 1000, connection :1.42)",
+		},
+		{
+			`Activating via systemd: service name='org.freedesktop.hostname1' unit='dbus-org.freedesktop.hostname1.service' requested by ':1.7' (uid=0 pid=811 comm="hostnamectl")`,
+			"hostnamectl (pid 811, uid 0, connection :1.7)",
+		},
+		{"Successfully activated service 'org.freedesktop.hostname1'", ""},
+	}
+	for _, tt := range tests {
+		if got := parseDBusRequest(tt.line); got != tt.want {
+			t.Errorf("parseDBusRequest(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestDetectDBusDirectChildOnly(t *testing.T) {
+	// PIDs above pid_max, so no live cgroup is read for them
+	bus := model.Process{PID: 1 << 30, Command: "dbus-daemon", Cmdline: "/usr/bin/dbus-daemon --session --address=systemd:"}
+	terminal := model.Process{PID: 1<<30 + 1, Command: "gnome-terminal-", Cmdline: "/usr/libexec/gnome-terminal-server"}
+	shell := model.Process{PID: 1<<30 + 2, Command: "bash", Cmdline: "bash"}
+	cmd := model.Process{PID: 1<<30 + 3, Command: "make", Cmdline: "make"}
+
+	if src := detectDBus([]model.Process{bus, terminal}); src == nil || src.Type != model.SourceDesktop {
+		t.Errorf("detectDBus(terminal) = %+v, want D-Bus activation", src)
+	}
+	if src := detectDBus([]model.Process{bus, terminal, shell, cmd}); src != nil {
+		t.Errorf("detectDBus(command in the terminal) = %+v, want nil", src)
+	}
+}
@@ -0,0 +1,29 @@
+//go:build darwin
+
+package source
+
+import "github.com/pranshuparmar/witr/pkg/model"
+
+func detectPortal(_ []model.Process) *model.Source {
+	return nil
+}
+
+func detectDisplayServer(_ []model.Process) *model.Source {
+	return nil
+}
+
+func detectAudio(_ []model.Process) *model.Source {
+	return nil
+}
+
+func detectLauncher(_ []model.Process) *model.Source {
+	return nil
+}
+
+func detectDBus(_ []model.Process) *model.Source {
+	return nil
+}
+
+func detectLoginSession(_ []model.Process) *model.Source {
+	return nil
+}
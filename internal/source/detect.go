@@ -1,40 +1,83 @@
 package source
 
 import (
+	"fmt"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
 func Detect(ancestry []model.Process) model.Source {
-	// Prefer supervisor over systemd/launchd if both are present
-	if src := detectContainer(ancestry); src != nil {
-		return *src
-	}
-	if src := detectSupervisor(ancestry); src != nil {
-		return *src
+	src := detect(ancestry)
+
+	// Name the Nix derivation behind an opaque /nix/store executable
+	if len(ancestry) > 0 {
+		if name, storePath := nixDerivation(ancestry[len(ancestry)-1].Exe); name != "" {
+			src.Details = withDetail(src.Details, "derivation", name+" ("+storePath+")")
+		}
 	}
-	if src := detectSystemd(ancestry); src != nil {
-		return *src
+
+	// Look through a container's entrypoint script to the workload it starts
+	if src.Type == model.SourceContainer {
+		if script, exec := entrypointScript(ancestry); script != "" {
+			src.Details = withDetail(src.Details, "entrypoint", script)
+			if exec != "" {
+				src.Details = withDetail(src.Details, "workload", "exec "+exec)
+			}
+		}
 	}
-	if src := detectLaunchd(ancestry); src != nil {
-		return *src
+
+	// Inside an LXC guest every process is containerized; say so on any source
+	if src.Type != model.SourceContainer && lxcGuest() {
+		guest := "running inside an LXC/LXD container"
+		if host, err := os.Hostname(); err == nil {
+			guest += " (" + host + ")"
+		}
+		src.Details = withDetail(src.Details, "guest", guest)
+	} else if src.Type != model.SourceWSL {
+		if guest := wslGuest(ancestry); guest != "" {
+			src.Details = withDetail(src.Details, "guest", "running under "+guest)
+		}
 	}
-	if src := detectCron(ancestry); src != nil {
-		return *src
+	return src
+}
+
+// ancestorEvidence explains a match on a process in the ancestry
+func ancestorEvidence(p model.Process, what string) string {
+	return "ancestor " + p.Command + " (pid " + itoa(p.PID) + ") " + what
+}
+
+// withDetail returns a copy of details with key set, leaving the original
+// map (which detectors may share) untouched
+func withDetail(details map[string]string, key, value string) map[string]string {
+	out := make(map[string]string, len(details)+1)
+	for k, v := range details {
+		out[k] = v
 	}
-	if src := detectShell(ancestry); src != nil {
-		return *src
+	out[key] = value
+	return out
+}
+
+func detect(ancestry []model.Process) model.Source {
+	for _, d := range detectors() {
+		if src := d.Detect(ancestry); src != nil {
+			return *src
+		}
 	}
 
 	return model.Source{
 		Type:       model.SourceUnknown,
 		Confidence: 0.2,
+		Evidence:   []string{"no detector matched the process or its ancestry"},
 	}
 }
 
-func Warnings(p []model.Process) []string {
-	var w []string
+// Warnings reports risky or unusual traits of the target (the last process
+// in p), given the source Detect found for it
+func Warnings(p []model.Process, src model.Source) []model.Warning {
+	var w []model.Warning
 
 	last := p[len(p)-1]
 
@@ -48,53 +91,177 @@ func Warnings(p []model.Process) []string {
 		lastCmd = proc.Command
 	}
 	if restartCount > 5 {
-		w = append(w, "Process or ancestor restarted more than 5 times")
+		w = append(w, model.Warning{Code: model.WarnFrequentRestarts, Severity: model.SeverityMedium, Message: "Process or ancestor restarted more than 5 times"})
+	}
+
+	skipped := 0
+	for _, proc := range p {
+		skipped += proc.SkippedAncestors
+	}
+	if skipped > 0 {
+		w = append(w, model.Warning{Code: model.WarnDeepAncestry, Severity: model.SeverityHigh, Message: fmt.Sprintf("Ancestry is %d levels deep (possible fork bomb); %d ancestors not shown", len(p)+skipped-1, skipped)})
 	}
 
 	// Health warnings
 	switch last.Health {
 	case "zombie":
-		w = append(w, "Process is a zombie (defunct)")
+		w = append(w, model.Warning{Code: model.WarnZombie, Severity: model.SeverityMedium, Message: "Process is a zombie (defunct)"})
 	case "stopped":
-		w = append(w, "Process is stopped (T state)")
+		w = append(w, model.Warning{Code: model.WarnStopped, Severity: model.SeverityLow, Message: "Process is stopped (T state)"})
 	case "high-cpu":
-		w = append(w, "Process is using high CPU (>2h total)")
+		w = append(w, model.Warning{Code: model.WarnHighUsage, Severity: model.SeverityMedium, Message: "Process is using high CPU (>2h total)"})
 	case "high-mem":
-		w = append(w, "Process is using high memory (>1GB RSS)")
+		w = append(w, model.Warning{Code: model.WarnHighUsage, Severity: model.SeverityMedium, Message: "Process is using high memory (>1GB RSS)"})
+	}
+
+	switch last.ExeState {
+	case model.ExeDeleted:
+		w = append(w, model.Warning{Code: model.WarnDeletedBinary, Severity: model.SeverityMedium, Message: "Running a removed binary (the executable was deleted after the process started); restart needed"})
+	case model.ExeReplaced:
+		w = append(w, model.Warning{Code: model.WarnOutdatedBinary, Severity: model.SeverityMedium, Message: "Running an outdated binary (the executable was replaced on disk, e.g. by an upgrade); restart needed"})
+	case model.ExeModified:
+		w = append(w, model.Warning{Code: model.WarnOutdatedBinary, Severity: model.SeverityMedium, Message: "Running an outdated binary (the executable was modified after the process started); restart needed"})
+	}
+
+	if mismatch := ExeMismatchWarning(last); mismatch != "" {
+		w = append(w, model.Warning{Code: model.WarnExeMismatch, Severity: model.SeverityMedium, Message: mismatch})
 	}
 
 	if IsPublicBind(last.BindAddresses) {
-		w = append(w, "Process is listening on a public interface")
+		w = append(w, model.Warning{Code: model.WarnPublicListener, Severity: model.SeverityMedium, Message: "Process is listening on a public interface"})
+	}
+
+	if sec := last.Security; sec != nil && !sec.Confined && IsPublicBind(last.BindAddresses) {
+		w = append(w, model.Warning{Code: model.WarnUnconfinedNetwork, Severity: model.SeverityMedium, Message: "Network-facing process is not confined by " + lsmName(sec.LSM) + " (" + sec.Profile + ", " + sec.Mode + ")"})
+	}
+
+	if id := last.Identity; id != nil && id.Seccomp == model.SeccompDisabled && IsPublicBind(last.BindAddresses) {
+		w = append(w, model.Warning{Code: model.WarnNoSeccomp, Severity: model.SeverityLow, Message: "Network-facing process has no seccomp filter; every system call is available to an attacker who compromises it"})
 	}
 
 	if last.User == "root" {
-		w = append(w, "Process is running as root")
+		w = append(w, model.Warning{Code: model.WarnRoot, Severity: model.SeverityLow, Message: "Process is running as root"})
+	}
+
+	if id := last.Identity; id != nil && id.EffectiveUID != 0 {
+		var held []string
+		for _, c := range id.Capabilities {
+			if dangerousCapabilities[c] {
+				held = append(held, c)
+			}
+		}
+		if len(held) > 0 {
+			w = append(w, model.Warning{Code: model.WarnDangerousCapabilities, Severity: model.SeverityHigh, Message: "Non-root process holds dangerous capabilities: " + strings.Join(held, ", ")})
+		}
+	}
+
+	// Kernel threads have no command line and legitimately run realtime
+	if sched := last.Scheduling; sched != nil && last.Cmdline != "" && last.PID != 1 {
+		if sched.Realtime() {
+			w = append(w, model.Warning{Code: model.WarnRealtime, Severity: model.SeverityMedium, Message: fmt.Sprintf("Process runs with realtime priority (%s %d); a busy loop in it can starve the rest of the system", sched.Policy, sched.RTPriority)})
+		}
+		if sched.OOMScoreAdj == -1000 {
+			w = append(w, model.Warning{Code: model.WarnOOMExempt, Severity: model.SeverityLow, Message: "Process is exempt from the OOM killer (oom_score_adj -1000); under memory pressure other processes are killed instead"})
+		}
 	}
 
-	if Detect(p).Type == model.SourceUnknown {
-		w = append(w, "No known supervisor or service manager detected")
+	if src.Type == model.SourceUnknown {
+		w = append(w, model.Warning{Code: model.WarnNoSupervisor, Severity: model.SeverityLow, Message: "No known supervisor or service manager detected"})
 	}
 
 	// Warn if process is very old (>90 days)
 	if time.Since(last.StartedAt).Hours() > 90*24 {
-		w = append(w, "Process has been running for over 90 days")
+		w = append(w, model.Warning{Code: model.WarnLongRunning, Severity: model.SeverityInfo, Message: "Process has been running for over 90 days"})
 	}
 
 	// Warn if working dir is suspicious
 	suspiciousDirs := map[string]bool{"/": true, "/tmp": true, "/var/tmp": true}
 	if suspiciousDirs[last.WorkingDir] {
-		w = append(w, "Process is running from a suspicious working directory: "+last.WorkingDir)
+		w = append(w, model.Warning{Code: model.WarnSuspiciousWorkingDir, Severity: model.SeverityHigh, Message: "Process is running from a suspicious working directory: " + last.WorkingDir})
 	}
 
 	// Warn if container and no healthcheck (placeholder, as healthcheck not detected)
 	if last.Container != "" {
-		w = append(w, "No healthcheck detected for container (best effort)")
+		w = append(w, model.Warning{Code: model.WarnNoHealthcheck, Severity: model.SeverityInfo, Message: "No healthcheck detected for container (best effort)"})
 	}
 
 	// Warn if service name and process name mismatch
 	if last.Service != "" && last.Command != "" && last.Service != last.Command {
-		w = append(w, "Service name and process name do not match")
+		w = append(w, model.Warning{Code: model.WarnServiceNameMismatch, Severity: model.SeverityInfo, Message: "Service name and process name do not match"})
 	}
 
 	return w
 }
+
+// Capabilities that amount to root, or let a process spy on the network or
+// other processes
+var dangerousCapabilities = map[string]bool{
+	"CAP_SYS_ADMIN": true, "CAP_SYS_MODULE": true, "CAP_SYS_PTRACE": true, "CAP_SYS_RAWIO": true,
+	"CAP_DAC_OVERRIDE": true, "CAP_DAC_READ_SEARCH": true, "CAP_SETUID": true, "CAP_SETGID": true,
+	"CAP_NET_ADMIN": true, "CAP_NET_RAW": true, "CAP_BPF": true,
+}
+
+// CgroupWarnings reports a process near its cgroup memory limit, throttled
+// by its CPU quota, or in a cgroup that has seen OOM kills
+func CgroupWarnings(c *model.CgroupInfo) []model.Warning {
+	var w []model.Warning
+	if c.MemoryMax > 0 && c.MemoryCurrent*10 >= c.MemoryMax*9 {
+		w = append(w, model.Warning{Code: model.WarnMemoryLimit, Severity: model.SeverityHigh, Message: fmt.Sprintf("Memory is at %.0f%% of the cgroup limit set by %s (near OOM)",
+			float64(c.MemoryCurrent)/float64(c.MemoryMax)*100, c.MemoryLimitedBy)})
+	}
+	if c.Periods > 0 && c.Throttled*10 >= c.Periods {
+		w = append(w, model.Warning{Code: model.WarnCPUThrottled, Severity: model.SeverityMedium, Message: fmt.Sprintf("CPU is throttled in %.0f%% of scheduling periods by the quota set by %s",
+			float64(c.Throttled)/float64(c.Periods)*100, c.CPULimitedBy)})
+	}
+	if c.OOMKills > 0 {
+		w = append(w, model.Warning{Code: model.WarnOOMKilled, Severity: model.SeverityMedium, Message: fmt.Sprintf("OOM killer has ended %d processes in cgroup %s", c.OOMKills, c.Path)})
+	}
+	return w
+}
+
+// lsmName returns the display name of a security module
+func lsmName(lsm string) string {
+	if lsm == "selinux" {
+		return "SELinux"
+	}
+	return "AppArmor"
+}
+
+// Descriptions of the limits LimitWarnings covers
+var limitDescriptions = map[string]string{
+	"nofile":  "open files",
+	"nproc":   "processes of the user",
+	"memlock": "locked memory",
+}
+
+// LimitWarnings warns for each resource limit with usage at 90% or more of
+// its soft limit
+func LimitWarnings(limits []model.ResourceLimit) []model.Warning {
+	var w []model.Warning
+	for _, l := range limits {
+		desc := limitDescriptions[l.Name]
+		if desc == "" || l.Usage == nil || l.Soft <= 0 || *l.Usage*10 < l.Soft*9 {
+			continue
+		}
+		w = append(w, model.Warning{Code: model.WarnResourceLimit, Severity: model.SeverityHigh, Message: fmt.Sprintf("Resource limit nearly reached: %s at %.0f%% of the %s limit (%d of %d)",
+			desc, float64(*l.Usage)/float64(l.Soft)*100, l.Name, *l.Usage, l.Soft)})
+	}
+	return w
+}
+
+// DescendantWarning describes a pathologically large descendant set
+func DescendantWarning(d *model.DescendantSummary) model.Warning {
+	count := fmt.Sprintf("~%d", d.Count)
+	if d.Truncated {
+		count = fmt.Sprintf("over %d", d.Count)
+	}
+	msg := "Process has " + count + " descendants (possible fork bomb or runaway spawner)"
+	var top []string
+	for _, c := range d.Top {
+		top = append(top, fmt.Sprintf("%s (%d)", c.Command, c.Count))
+	}
+	if len(top) > 0 {
+		msg += "; top offenders: " + strings.Join(top, ", ")
+	}
+	return model.Warning{Code: model.WarnManyDescendants, Severity: model.SeverityHigh, Message: msg}
+}
@@ -0,0 +1,123 @@
+//go:build linux
+
+package source
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// Wayland compositors and X servers, keyed by comm (truncated to 15 chars)
+var displayServers = map[string]string{
+	"gnome-shell":     "GNOME Shell (mutter)",
+	"mutter":          "mutter",
+	"kwin_wayland":    "KWin (Wayland)",
+	"kwin_x11":        "KWin (X11)",
+	"sway":            "sway",
+	"Hyprland":        "Hyprland",
+	"weston":          "weston",
+	"labwc":           "labwc",
+	"wayfire":         "wayfire",
+	"river":           "river",
+	"niri":            "niri",
+	"cosmic-comp":     "COSMIC compositor",
+	"Xorg":            "Xorg",
+	"X":               "Xorg",
+	"Xwayland":        "Xwayland",
+	"gamescope":       "gamescope",
+	"kwin_wayland_wr": "KWin (Wayland)",
+}
+
+// detectDisplayServer attributes helpers a Wayland compositor or X server
+// spawned directly (Xwayland, clients started by the compositor) to the
+// compositor, seat and login session
+func detectDisplayServer(ancestry []model.Process) *model.Source {
+	if len(ancestry) < 2 {
+		return nil
+	}
+	target := ancestry[len(ancestry)-1]
+
+	// Only the compositor's own children: a shell in a terminal it started
+	// belongs to the terminal and the shell, not to the compositor
+	p := ancestry[len(ancestry)-2]
+	name, ok := displayServers[p.Command]
+	if !ok {
+		return nil
+	}
+
+	details := make(map[string]string)
+	if target.Command == "Xwayland" {
+		details["role"] = "X11 compatibility server for Wayland clients"
+	} else {
+		details["role"] = "client started by the compositor"
+	}
+	if display := envValue(target.Env, "WAYLAND_DISPLAY"); display != "" {
+		details["display"] = "wayland " + display
+	} else if display := envValue(target.Env, "DISPLAY"); display != "" {
+		details["display"] = "X11 " + display
+	}
+
+	session := envValue(p.Env, "XDG_SESSION_ID")
+	if session == "" {
+		session = auditSession(p.PID)
+	}
+	if session != "" {
+		props := loginctlSession(session)
+		desc := session
+		if props["Type"] != "" {
+			desc += " (" + props["Type"] + ")"
+		}
+		if props["Name"] != "" {
+			desc += " for user " + props["Name"]
+		}
+		details["session"] = desc
+		if props["Seat"] != "" {
+			details["seat"] = props["Seat"]
+		}
+	}
+	if _, ok := details["seat"]; !ok {
+		if seat := envValue(p.Env, "XDG_SEAT"); seat != "" {
+			details["seat"] = seat
+		}
+	}
+
+	return &model.Source{
+		Type:       model.SourceDesktop,
+		Name:       name,
+		Confidence: 0.7,
+		Evidence:   []string{"parent " + p.Command + " (pid " + itoa(p.PID) + ") is a display server"},
+		Details:    details,
+	}
+}
+
+// auditSession returns the kernel audit session ID of a process, which matches
+// the logind session ID for processes started inside a login session
+func auditSession(pid int) string {
+	data, err := os.ReadFile("/proc/" + itoa(pid) + "/sessionid")
+	if err != nil {
+		return ""
+	}
+	id := strings.TrimSpace(string(data))
+	if id == "" || id == "4294967295" {
+		return ""
+	}
+	return id
+}
+
+// loginctlSession returns properties of a logind session
+func loginctlSession(id string) map[string]string {
+	props := make(map[string]string)
+	out, err := exec.Command("loginctl", "show-session", id, "-p", "Name", "-p", "Seat", "-p", "Type", "-p", "Class", "-p", "Service", "-p", "Desktop").Output()
+	if err != nil {
+		return props
+	}
+	for line := range strings.Lines(string(out)) {
+		if key, val, ok := strings.Cut(strings.TrimSpace(line), "="); ok {
+			props[key] = val
+		}
+	}
+	return props
+}
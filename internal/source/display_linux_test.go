@@ -0,0 +1,26 @@
+//go:build linux
+
+package source
+
+import (
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestDetectDisplayServerDirectChildOnly(t *testing.T) {
+	// PIDs above pid_max, so no live session is read for them
+	ancestry := []model.Process{
+		{PID: 1, Command: "systemd"},
+		{PID: 1 << 30, Command: "sway"},
+		{PID: 1<<30 + 1, Command: "foot"},
+		{PID: 1<<30 + 2, Command: "bash"},
+		{PID: 1<<30 + 3, Command: "make"},
+	}
+	if src := detectDisplayServer(ancestry[:3]); src == nil || src.Name != "sway" {
+		t.Errorf("detectDisplayServer(foot) = %+v, want sway", src)
+	}
+	if src := detectDisplayServer(ancestry); src != nil {
+		t.Errorf("detectDisplayServer(make in foot) = %+v, want nil", src)
+	}
+}
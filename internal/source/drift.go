@@ -0,0 +1,83 @@
+package source
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// Drift compares a running process with what its systemd unit or container
+// declares and returns a warning for each divergence, such as a unit edited
+// since the process started or a container command that overrides the
+// image's
+func Drift(p model.Process, src model.Source) []model.Warning {
+	var w []model.Warning
+	if p.Service != "" {
+		w = append(w, unitDrift(p)...)
+	}
+	if src.Type == model.SourceContainer {
+		w = append(w, containerDrift(p, src)...)
+	}
+	return w
+}
+
+// ExecStartArgv extracts the command lines from a systemctl show ExecStart
+// value, dropping the per-run fields (start_time, pid, status)
+func ExecStartArgv(val string) string {
+	var cmds []string
+	for entry := range strings.SplitSeq(val, "}") {
+		_, rest, ok := strings.Cut(entry, "argv[]=")
+		if !ok {
+			continue
+		}
+		argv, _, _ := strings.Cut(rest, " ;")
+		cmds = append(cmds, strings.TrimSpace(argv))
+	}
+	if len(cmds) == 0 {
+		return val
+	}
+	return strings.Join(cmds, "; ")
+}
+
+// commandDrifted reports whether a running command line differs from the
+// declared one. Processes that rewrite their title (nginx, postgres) run a
+// different program name than declared and are not compared, nor are
+// declarations that expand variables.
+func commandDrifted(declared, running string) bool {
+	want, got := strings.Fields(declared), strings.Fields(running)
+	if len(want) == 0 || len(got) == 0 || strings.Contains(declared, "$") {
+		return false
+	}
+	if filepath.Base(want[0]) != filepath.Base(got[0]) {
+		return false
+	}
+	return !slices.Equal(want[1:], got[1:])
+}
+
+// envDrift returns the keys whose declared KEY=value assignments differ
+// from the environment the process started with. Values are not returned,
+// as they may be secrets.
+func envDrift(declared, running []string) []string {
+	if len(running) == 0 {
+		return nil
+	}
+	actual := make(map[string]string, len(running))
+	for _, kv := range running {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			actual[k] = v
+		}
+	}
+	var keys []string
+	for _, kv := range declared {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok || k == "" || strings.Contains(v, "$") {
+			continue
+		}
+		if got, ok := actual[k]; !ok || got != v {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
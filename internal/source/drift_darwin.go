@@ -0,0 +1,14 @@
+//go:build darwin
+
+package source
+
+import "github.com/pranshuparmar/witr/pkg/model"
+
+func unitDrift(_ model.Process) []model.Warning {
+	return nil
+}
+
+// Containers run in a VM on macOS, so their processes are not visible
+func containerDrift(_ model.Process, _ model.Source) []model.Warning {
+	return nil
+}
@@ -0,0 +1,133 @@
+//go:build linux
+
+package source
+
+import (
+	"encoding/json"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/pranshuparmar/witr/internal/runtimeapi"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// unitDrift compares a process with its systemd unit: unit files changed
+// since it started, and for the main process the declared command line and
+// environment
+func unitDrift(p model.Process) []model.Warning {
+	unit := p.Service
+	props := systemctlShow(unit, "NeedDaemonReload", "FragmentPath", "DropInPaths", "MainPID", "ExecStart", "Environment")
+	var w []model.Warning
+	if props["NeedDaemonReload"] == "yes" {
+		w = append(w, model.Warning{Code: model.WarnUnitFileChanged, Severity: model.SeverityMedium, Message: "Unit file of " + unit + " changed on disk but was not reloaded (run systemctl daemon-reload and restart " + unit + ")"})
+	} else if file := modifiedSince(append([]string{props["FragmentPath"]}, strings.Fields(props["DropInPaths"])...), p); file != "" {
+		w = append(w, model.Warning{Code: model.WarnUnitFileChanged, Severity: model.SeverityMedium, Message: "Unit file of " + unit + " was modified after the process started (" + file + "); restart " + unit + " to apply it"})
+	}
+	if props["MainPID"] != itoa(p.PID) {
+		return w
+	}
+	if declared := ExecStartArgv(props["ExecStart"]); commandDrifted(declared, p.Cmdline) {
+		w = append(w, model.Warning{Code: model.WarnCommandDrift, Severity: model.SeverityMedium, Message: "Command line differs from the ExecStart of " + unit + ": " + declared})
+	}
+	if keys := envDrift(strings.Fields(props["Environment"]), p.Env); len(keys) > 0 {
+		w = append(w, model.Warning{Code: model.WarnEnvironmentDrift, Severity: model.SeverityLow, Message: "Environment differs from the unit " + unit + " for: " + strings.Join(keys, ", ")})
+	}
+	return w
+}
+
+// modifiedSince returns the first file changed after the process started
+func modifiedSince(files []string, p model.Process) string {
+	for _, f := range files {
+		if f == "" {
+			continue
+		}
+		if fi, err := os.Stat(f); err == nil && fi.ModTime().After(p.StartedAt) {
+			return f
+		}
+	}
+	return ""
+}
+
+// containerDrift compares a container with its image (a command given at
+// run time overrides the image's) and its main process with the command and
+// environment the runtime started it with
+func containerDrift(p model.Process, src model.Source) []model.Warning {
+	runtime, info := processContainer(src, p)
+	if info == nil {
+		return nil
+	}
+	name := strings.TrimPrefix(info.Name, "/")
+
+	var w []model.Warning
+	if image := inspectImage(runtime, info.Image); image != nil {
+		declared := append(slices.Clone(image.Config.Entrypoint), image.Config.Cmd...)
+		actual := append(slices.Clone(info.Config.Entrypoint), info.Config.Cmd...)
+		if !slices.Equal(declared, actual) {
+			w = append(w, model.Warning{Code: model.WarnContainerCommandOverride, Severity: model.SeverityLow, Message: "Container command overrides the image's: " + name + " runs \"" + strings.Join(actual, " ") +
+				"\" instead of the \"" + strings.Join(declared, " ") + "\" of " + info.Config.Image})
+		}
+	}
+	if info.State.Pid != p.PID {
+		return w
+	}
+	if declared := strings.Join(append([]string{info.Path}, info.Args...), " "); commandDrifted(declared, p.Cmdline) {
+		w = append(w, model.Warning{Code: model.WarnCommandDrift, Severity: model.SeverityMedium, Message: "Command line differs from the command container " + name + " was started with: " + declared})
+	}
+	if keys := envDrift(info.Config.Env, p.Env); len(keys) > 0 {
+		w = append(w, model.Warning{Code: model.WarnEnvironmentDrift, Severity: model.SeverityLow, Message: "Environment differs from container " + name + " for: " + strings.Join(keys, ", ")})
+	}
+	return w
+}
+
+// processContainer inspects the container of a process with the CLI of the
+// runtime the source names, returning a nil info when it cannot
+func processContainer(src model.Source, p model.Process) (string, *containerInspect) {
+	runtime := containerCLI(src.Name)
+	if runtime == "" {
+		return "", nil
+	}
+	data, err := os.ReadFile("/proc/" + itoa(p.PID) + "/cgroup")
+	if err != nil {
+		return runtime, nil
+	}
+	return runtime, inspectContainer(runtime, containerID(string(data)))
+}
+
+// containerCLI returns the CLI that inspects containers of a runtime, or ""
+// for runtimes witr does not query
+func containerCLI(runtime string) string {
+	switch runtime {
+	case "docker", "podman", "nerdctl":
+		return runtime
+	case "containerd":
+		return "nerdctl"
+	}
+	return ""
+}
+
+// imageInspect holds the fields of `docker image inspect` output witr uses
+type imageInspect struct {
+	// RepoDigests holds "name@sha256:..." for each registry it was pulled from
+	RepoDigests []string `json:"RepoDigests"`
+	Config      struct {
+		Entrypoint []string          `json:"Entrypoint"`
+		Cmd        []string          `json:"Cmd"`
+		Labels     map[string]string `json:"Labels"`
+	} `json:"Config"`
+}
+
+func inspectImage(runtime, id string) *imageInspect {
+	if id == "" {
+		return nil
+	}
+	out, err := runtimeapi.Output(runtime, "image", "inspect", id)
+	if err != nil {
+		return nil
+	}
+	var results []imageInspect
+	if err := json.Unmarshal(out, &results); err != nil || len(results) == 0 {
+		return nil
+	}
+	return &results[0]
+}
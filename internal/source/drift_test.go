@@ -0,0 +1,45 @@
+package source
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestExecStartArgv(t *testing.T) {
+	val := "{ path=/usr/sbin/nginx ; argv[]=/usr/sbin/nginx -g daemon off; ; ignore_errors=no ; start_time=[Mon 2026-10-12 09:00:01 UTC] ; stop_time=[n/a] ; pid=812 ; code=(null) ; status=0/0 }"
+	if got, want := ExecStartArgv(val), "/usr/sbin/nginx -g daemon off;"; got != want {
+		t.Errorf("ExecStartArgv() = %q, want %q", got, want)
+	}
+	if got := ExecStartArgv("/bin/true"); got != "/bin/true" {
+		t.Errorf("ExecStartArgv(plain) = %q", got)
+	}
+}
+
+func TestCommandDrifted(t *testing.T) {
+	tests := []struct {
+		declared, running string
+		want              bool
+	}{
+		{"/usr/bin/redis-server /etc/redis.conf", "/usr/bin/redis-server /etc/redis.conf", false},
+		{"/usr/bin/redis-server /etc/redis.conf", "redis-server /etc/redis.conf", false},
+		{"/usr/bin/redis-server /etc/redis.conf", "/usr/bin/redis-server /etc/redis-old.conf", true},
+		{"/usr/sbin/nginx -g daemon off;", "nginx: master process /usr/sbin/nginx -g daemon off;", false},
+		{"/usr/bin/app $OPTS", "/usr/bin/app --verbose", false},
+	}
+	for _, tt := range tests {
+		if got := commandDrifted(tt.declared, tt.running); got != tt.want {
+			t.Errorf("commandDrifted(%q, %q) = %v, want %v", tt.declared, tt.running, got, tt.want)
+		}
+	}
+}
+
+func TestEnvDrift(t *testing.T) {
+	declared := []string{"PORT=8080", "MODE=prod", "HOME=$STATE", "NEW=1"}
+	running := []string{"PORT=8080", "MODE=dev", "PATH=/usr/bin"}
+	if got, want := envDrift(declared, running), []string{"MODE", "NEW"}; !slices.Equal(got, want) {
+		t.Errorf("envDrift() = %v, want %v", got, want)
+	}
+	if got := envDrift(declared, nil); got != nil {
+		t.Errorf("envDrift(unreadable) = %v, want nil", got)
+	}
+}
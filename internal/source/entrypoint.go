@@ -0,0 +1,86 @@
+package source
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+var scriptShells = map[string]bool{"sh": true, "bash": true, "dash": true, "ash": true, "zsh": true}
+
+// entrypointScript finds the shell in the ancestry that runs a container's
+// entrypoint script and returns the script (or the -c command) and the
+// command it ends by exec'ing, read from the container's root filesystem.
+// exec is empty when the script runs its workload as a child instead.
+func entrypointScript(ancestry []model.Process) (script, exec string) {
+	for _, p := range ancestry {
+		args := strings.Fields(p.Cmdline)
+		if len(args) < 2 || !scriptShells[filepath.Base(args[0])] {
+			continue
+		}
+		i := 1
+		for i < len(args) && strings.HasPrefix(args[i], "-") && args[i] != "-c" {
+			i++
+		}
+		if i >= len(args) {
+			continue
+		}
+		if args[i] == "-c" {
+			// The command string is split across fields; without quoting
+			// information treat everything after -c as the script
+			inline := strings.Join(args[i+1:], " ")
+			return "sh -c " + inline, finalExec(inline, nil)
+		}
+		name := args[i]
+		file := name
+		if !path.IsAbs(file) {
+			file = path.Join(p.WorkingDir, file)
+		}
+		// The script lives in the container's mount namespace
+		data, err := os.ReadFile("/proc/" + itoa(p.PID) + "/root" + file)
+		if err != nil {
+			return name, ""
+		}
+		return name + " (pid " + itoa(p.PID) + ")", finalExec(string(data), args[i+1:])
+	}
+	return "", ""
+}
+
+// finalExec returns the last exec command of a shell script with the
+// positional parameters it was run with substituted, or "" when it has none
+func finalExec(script string, args []string) string {
+	last := ""
+	for line := range strings.Lines(script) {
+		line = strings.TrimSpace(line)
+		// One-liners such as `cond && exec foo` exec as well
+		for _, sep := range []string{"&& ", "; ", "then "} {
+			if i := strings.LastIndex(line, sep+"exec "); i >= 0 {
+				line = line[i+len(sep):]
+			}
+		}
+		if cmd, ok := strings.CutPrefix(line, "exec "); ok {
+			cmd, _, _ = strings.Cut(cmd, " #")
+			// exec with only redirections (exec 3>&1) replaces nothing
+			if fields := strings.Fields(cmd); len(fields) > 0 && !strings.ContainsAny(fields[0], "<>") {
+				last = strings.TrimSpace(cmd)
+			}
+		}
+	}
+	if last == "" {
+		return ""
+	}
+	all := strings.Join(args, " ")
+	for _, p := range []string{`"$@"`, `"${@}"`, `$@`, `"$*"`, `$*`} {
+		last = strings.ReplaceAll(last, p, all)
+	}
+	for i := len(args); i >= 1; i-- {
+		n := itoa(i)
+		for _, p := range []string{`"$` + n + `"`, `"${` + n + `}"`, `$` + n, `${` + n + `}`} {
+			last = strings.ReplaceAll(last, p, args[i-1])
+		}
+	}
+	return strings.Join(strings.Fields(last), " ")
+}
@@ -0,0 +1,25 @@
+package source
+
+import "testing"
+
+func TestFinalExec(t *testing.T) {
+	script := `#!/bin/sh
+set -e
+# allow the container to be started with --user
+if [ "$1" = 'redis-server' -a "$(id -u)" = '0' ]; then
+	chown -R redis .
+	exec gosu redis "$0" "$@"
+fi
+exec 3>&1
+exec "$@"
+`
+	if got, want := finalExec(script, []string{"redis-server", "--appendonly", "yes"}), "redis-server --appendonly yes"; got != want {
+		t.Errorf("finalExec() = %q, want %q", got, want)
+	}
+	if got, want := finalExec(`[ -f /app/.env ] && exec node "$1" --port ${2}`, []string{"server.js", "80"}), "node server.js --port 80"; got != want {
+		t.Errorf("finalExec(one-liner) = %q, want %q", got, want)
+	}
+	if got := finalExec("#!/bin/sh\n./migrate && ./server\n", nil); got != "" {
+		t.Errorf("finalExec(no exec) = %q, want empty", got)
+	}
+}
@@ -0,0 +1,66 @@
+package source
+
+import (
+	"os"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// detectFlatpak reports sandboxed Flatpak applications. Flatpak writes the
+// app ID and runtime to /.flatpak-info inside the sandbox, which is visible
+// through /proc/<pid>/root; bwrap in the ancestry is a fallback signal.
+func detectFlatpak(ancestry []model.Process) *model.Source {
+	if len(ancestry) == 0 {
+		return nil
+	}
+	target := ancestry[len(ancestry)-1]
+
+	details := make(map[string]string)
+	var evidence []string
+	if data, err := os.ReadFile("/proc/" + itoa(target.PID) + "/root/.flatpak-info"); err == nil {
+		content := string(data)
+		evidence = append(evidence, "sandbox contains /.flatpak-info")
+		app := parseKeyFileGroup(content, "Application")
+		if app["name"] != "" {
+			details["app"] = app["name"]
+		}
+		if app["runtime"] != "" {
+			details["runtime"] = app["runtime"]
+		}
+		if inst := parseKeyFileGroup(content, "Instance"); inst["instance-id"] != "" {
+			details["instance"] = inst["instance-id"]
+		}
+	}
+	if _, ok := details["app"]; !ok {
+		if id := envValue(target.Env, "FLATPAK_ID"); id != "" {
+			details["app"] = id
+			evidence = append(evidence, "FLATPAK_ID environment variable is set")
+		}
+	}
+
+	if len(details) == 0 {
+		bwrap := false
+		for _, p := range ancestry {
+			if p.Command == "bwrap" && strings.Contains(p.Cmdline, "flatpak") {
+				bwrap = true
+				evidence = append(evidence, "ancestor is flatpak's bwrap sandbox (pid "+itoa(p.PID)+")")
+			}
+		}
+		if !bwrap {
+			return nil
+		}
+	}
+
+	name := details["app"]
+	if name == "" {
+		name = "flatpak"
+	}
+	return &model.Source{
+		Type:       model.SourceFlatpak,
+		Name:       name,
+		Confidence: 0.9,
+		Evidence:   evidence,
+		Details:    details,
+	}
+}
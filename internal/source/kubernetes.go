@@ -0,0 +1,90 @@
+package source
+
+import (
+	"encoding/json"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/pranshuparmar/witr/internal/runtimeapi"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// Pod UIDs appear in kubepods cgroups as pod<uid>, with dashes replaced by
+// underscores under the systemd cgroup driver
+var podUIDPattern = regexp.MustCompile(`pod([0-9a-f]{8}[-_][0-9a-f]{4}[-_][0-9a-f]{4}[-_][0-9a-f]{4}[-_][0-9a-f]{12})`)
+
+const kubeletPodsDir = "/var/lib/kubelet/pods"
+
+// kubernetesDetails resolves the pod and container a process runs in, first
+// through the CRI (crictl) and then from the kubelet and container filesystems
+func kubernetesDetails(p model.Process, cgroup string) map[string]string {
+	details := make(map[string]string)
+
+	uid := ""
+	if m := podUIDPattern.FindStringSubmatch(cgroup); m != nil {
+		uid = strings.ReplaceAll(m[1], "_", "-")
+		details["pod_uid"] = uid
+	}
+
+	namespace, pod, container := "", "", ""
+	if id := containerID(cgroup); id != "" {
+		namespace, pod, container = criContainer(id)
+	}
+
+	if pod == "" {
+		// The pod name is the container's hostname
+		pod = envValue(p.Env, "HOSTNAME")
+	}
+	if namespace == "" {
+		// The service account token mount carries the namespace
+		if data, err := os.ReadFile("/proc/" + itoa(p.PID) + "/root/var/run/secrets/kubernetes.io/serviceaccount/namespace"); err == nil {
+			namespace = strings.TrimSpace(string(data))
+		}
+	}
+	if container == "" && uid != "" {
+		// kubelet keeps a directory per container of the pod
+		if entries, err := os.ReadDir(kubeletPodsDir + "/" + uid + "/containers"); err == nil && len(entries) == 1 {
+			container = entries[0].Name()
+		}
+	}
+
+	if pod != "" {
+		if namespace != "" {
+			pod = namespace + "/" + pod
+		}
+		details["pod"] = pod
+		origin := "running in pod " + pod
+		if container != "" {
+			origin += ", container " + container
+		}
+		details["origin"] = origin
+	}
+	if container != "" {
+		details["container"] = container
+	}
+
+	if len(details) == 0 {
+		return nil
+	}
+	return details
+}
+
+// criContainer returns the pod namespace, pod name and container name from
+// the container runtime's Kubernetes labels
+func criContainer(id string) (namespace, pod, container string) {
+	out, err := runtimeapi.Output("crictl", "inspect", id)
+	if err != nil {
+		return "", "", ""
+	}
+	var info struct {
+		Status struct {
+			Labels map[string]string `json:"labels"`
+		} `json:"status"`
+	}
+	if err := json.Unmarshal(out, &info); err != nil {
+		return "", "", ""
+	}
+	labels := info.Status.Labels
+	return labels["io.kubernetes.pod.namespace"], labels["io.kubernetes.pod.name"], labels["io.kubernetes.container.name"]
+}
@@ -37,6 +37,7 @@ func detectLaunchd(ancestry []model.Process) *model.Source {
 			Type:       model.SourceLaunchd,
 			Name:       "launchd",
 			Confidence: 0.8,
+			Evidence:   []string{"ancestry reaches launchd, but launchctl names no job for pid " + itoa(target.PID)},
 		}
 	}
 
@@ -45,8 +46,12 @@ func detectLaunchd(ancestry []model.Process) *model.Source {
 		Type:       model.SourceLaunchd,
 		Name:       info.Label,
 		Confidence: 0.95,
+		Evidence:   []string{"launchctl attributes pid " + itoa(target.PID) + " to job " + info.Label},
 		Details:    make(map[string]string),
 	}
+	if info.PlistPath != "" {
+		source.Evidence = append(source.Evidence, "job is defined in "+info.PlistPath)
+	}
 
 	// Add domain description (Launch Agent vs Launch Daemon)
 	source.Details["type"] = info.DomainDescription()
@@ -0,0 +1,112 @@
+//go:build linux
+
+package source
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// File managers that launch applications on double-click
+var fileManagers = map[string]string{
+	"nautilus":   "Files (nautilus)",
+	"dolphin":    "Dolphin",
+	"thunar":     "Thunar",
+	"Thunar":     "Thunar",
+	"nemo":       "Nemo",
+	"caja":       "Caja",
+	"pcmanfm":    "PCManFM",
+	"pcmanfm-qt": "PCManFM-Qt",
+	"spacefm":    "SpaceFM",
+}
+
+// URL/file openers that resolve a handler and start it
+var openers = map[string]bool{
+	"xdg-open":           true,
+	"gio":                true,
+	"gio-launch-desktop": true,
+	"kde-open":           true,
+	"kde-open5":          true,
+	"kioclient":          true,
+	"kioclient5":         true,
+	"exo-open":           true,
+	"gnome-open":         true,
+	"mimeopen":           true,
+}
+
+// detectLauncher attributes GUI applications to the way they were launched:
+// a .desktop file activated by GIO, an opener such as xdg-open, or a file
+// manager double-click
+func detectLauncher(ancestry []model.Process) *model.Source {
+	if len(ancestry) < 2 {
+		return nil
+	}
+	target := ancestry[len(ancestry)-1]
+	details := make(map[string]string)
+	var evidence []string
+	name := ""
+
+	// GIO records the activated desktop file and the PID of the process it
+	// launched. Descendants inherit both, so the launcher is the parent of
+	// whichever ancestor has that PID.
+	if file := envValue(target.Env, "GIO_LAUNCHED_DESKTOP_FILE"); file != "" {
+		details["desktop"] = file
+		evidence = append(evidence, "GIO_LAUNCHED_DESKTOP_FILE environment variable is set")
+		if pid, err := strconv.Atoi(envValue(target.Env, "GIO_LAUNCHED_DESKTOP_FILE_PID")); err == nil {
+			for i := len(ancestry) - 1; i > 0; i-- {
+				if ancestry[i].PID != pid {
+					continue
+				}
+				if pid != target.PID {
+					evidence = append(evidence, "inherited from ancestor "+ancestry[i].Command+" (pid "+strconv.Itoa(pid)+"), which GIO launched")
+				}
+				parent := ancestry[i-1]
+				details["launcher"] = parent.Command + " (pid " + strconv.Itoa(parent.PID) + ")"
+				if label, ok := fileManagers[parent.Command]; ok {
+					name = label
+				}
+				break
+			}
+		}
+	} else if file := envValue(target.Env, "BAMF_DESKTOP_FILE_HINT"); file != "" {
+		details["desktop"] = file
+		evidence = append(evidence, "BAMF_DESKTOP_FILE_HINT environment variable is set")
+	}
+
+	for i := len(ancestry) - 2; i >= 0; i-- {
+		p := ancestry[i]
+		if openers[p.Command] {
+			via := p.Command
+			if args := strings.Fields(p.Cmdline); len(args) > 1 {
+				via += " " + strings.Join(args[1:], " ")
+			}
+			details["via"] = via
+			evidence = append(evidence, "ancestor "+p.Command+" (pid "+strconv.Itoa(p.PID)+") is a URL/file opener")
+			continue
+		}
+		if label, ok := fileManagers[p.Command]; ok {
+			name = label
+			details["launcher"] = p.Command + " (pid " + strconv.Itoa(p.PID) + ")"
+			evidence = append(evidence, "ancestor "+p.Command+" is a file manager")
+			break
+		}
+	}
+
+	if len(details) == 0 {
+		return nil
+	}
+	if name == "" {
+		name = "desktop launcher"
+	} else {
+		name += " (double-click)"
+	}
+	return &model.Source{
+		Type:       model.SourceDesktop,
+		Name:       name,
+		Confidence: 0.7,
+		Evidence:   evidence,
+		Details:    details,
+	}
+}
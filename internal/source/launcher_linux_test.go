@@ -0,0 +1,31 @@
+//go:build linux
+
+package source
+
+import (
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestDetectLauncherInheritedGIOPID(t *testing.T) {
+	env := []string{"GIO_LAUNCHED_DESKTOP_FILE=/usr/share/applications/org.gnome.Terminal.desktop", "GIO_LAUNCHED_DESKTOP_FILE_PID=300"}
+	ancestry := []model.Process{
+		{PID: 1, Command: "systemd"},
+		{PID: 200, Command: "gnome-shell"},
+		{PID: 300, Command: "gnome-terminal-", Env: env},
+		{PID: 400, Command: "bash", Env: env},
+	}
+
+	// The shell inherited the variables from the terminal GIO launched
+	src := detectLauncher(ancestry)
+	if src == nil || src.Details["launcher"] != "gnome-shell (pid 200)" {
+		t.Fatalf("detectLauncher() = %+v, want gnome-shell as the launcher", src)
+	}
+
+	// A launched process that is not an ancestor names no launcher
+	env[1] = "GIO_LAUNCHED_DESKTOP_FILE_PID=350"
+	if src := detectLauncher(ancestry); src == nil || src.Details["launcher"] != "" {
+		t.Errorf("detectLauncher() = %+v, want no launcher", src)
+	}
+}
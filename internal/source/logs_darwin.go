@@ -0,0 +1,10 @@
+//go:build darwin
+
+package source
+
+import "github.com/pranshuparmar/witr/pkg/model"
+
+// RecentLogs returns nil; launchd jobs have no per-job log to tail
+func RecentLogs(_ model.Source, _ model.Process, _ int) *model.LogTail {
+	return nil
+}
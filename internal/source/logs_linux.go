@@ -0,0 +1,82 @@
+//go:build linux
+
+package source
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/internal/runtimeapi"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// RecentLogs returns the last n lines logged by the systemd unit or
+// container src attributes the process to, or nil when it has no log
+func RecentLogs(src model.Source, p model.Process, n int) *model.LogTail {
+	switch src.Type {
+	case model.SourceSystemd:
+		return unitLogs(src, n)
+	case model.SourceContainer:
+		return containerLogs(src, p, n)
+	}
+	return nil
+}
+
+// unitLogs reads the journal of a system or systemd --user unit
+func unitLogs(src model.Source, n int) *model.LogTail {
+	unit := src.Name
+	if !strings.Contains(unit, ".") {
+		// Attributed to systemd itself, not one of its units
+		return nil
+	}
+	filter := "-u"
+	if strings.HasPrefix(src.Details["manager"], "user ") {
+		filter = "--user-unit"
+	}
+	args := []string{filter, unit, "-n", strconv.Itoa(n), "--no-pager", "-o", "short-iso"}
+	out, err := exec.Command("journalctl", args...).Output()
+	if err != nil {
+		return nil
+	}
+	return logTail("journalctl "+filter+" "+unit, string(out))
+}
+
+// containerLogs reads the output a container runtime keeps for a container
+func containerLogs(src model.Source, p model.Process, n int) *model.LogTail {
+	runtime := containerCLI(src.Name)
+	if runtime == "" {
+		return nil
+	}
+	data, err := os.ReadFile("/proc/" + itoa(p.PID) + "/cgroup")
+	if err != nil {
+		return nil
+	}
+	id := containerID(string(data))
+	if id == "" {
+		return nil
+	}
+	out, err := runtimeapi.CombinedOutput(runtime, "logs", "--tail", strconv.Itoa(n), id)
+	if err != nil {
+		return nil
+	}
+	name := p.Container
+	if name == "" {
+		name = id[:min(12, len(id))]
+	}
+	return logTail(runtime+" logs "+name, string(out))
+}
+
+// logTail splits fetched log output into lines; journalctl reports an
+// empty journal as "-- No entries --"
+func logTail(command, out string) *model.LogTail {
+	tail := &model.LogTail{Command: command}
+	for line := range strings.Lines(out) {
+		line = strings.TrimRight(line, "\r\n")
+		if line != "" && line != "-- No entries --" {
+			tail.Lines = append(tail.Lines, line)
+		}
+	}
+	return tail
+}
@@ -0,0 +1,15 @@
+//go:build linux
+
+package source
+
+import "testing"
+
+func TestLogTail(t *testing.T) {
+	tail := logTail("journalctl -u nginx.service", "2026-10-14T10:00:00+0000 host nginx[12]: started\r\n\n2026-10-14T10:00:01+0000 host nginx[12]: ready\n")
+	if len(tail.Lines) != 2 || tail.Lines[1] != "2026-10-14T10:00:01+0000 host nginx[12]: ready" {
+		t.Errorf("logTail lines = %q", tail.Lines)
+	}
+	if empty := logTail("journalctl -u idle.service", "-- No entries --\n"); len(empty.Lines) != 0 {
+		t.Errorf("empty journal lines = %q", empty.Lines)
+	}
+}
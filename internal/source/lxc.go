@@ -0,0 +1,82 @@
+package source
+
+import (
+	"encoding/json"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/pranshuparmar/witr/internal/runtimeapi"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// LXC names container cgroups /lxc.payload.<name> (LXC 4+) or /lxc/<name>
+var lxcCgroupPattern = regexp.MustCompile(`/lxc(?:\.payload\.|/)([^/\s]+)`)
+
+// lxcSource describes a process that runs in an LXC or LXD container, as seen
+// from the host
+func lxcSource(cgroup string) *model.Source {
+	m := lxcCgroupPattern.FindStringSubmatch(cgroup)
+	if m == nil {
+		return nil
+	}
+	name := m[1]
+	details := map[string]string{"container": name}
+
+	// LXD (and its fork Incus) manage the container when they know it
+	for _, client := range []string{"lxc", "incus"} {
+		out, err := runtimeapi.Output(client, "query", "/1.0/instances/"+name)
+		if err != nil {
+			continue
+		}
+		var inst struct {
+			Profiles        []string                     `json:"profiles"`
+			ExpandedDevices map[string]map[string]string `json:"expanded_devices"`
+		}
+		if json.Unmarshal(out, &inst) != nil {
+			continue
+		}
+		if len(inst.Profiles) > 0 {
+			details["profiles"] = strings.Join(inst.Profiles, ", ")
+		}
+		if root, ok := inst.ExpandedDevices["root"]; ok && root["pool"] != "" {
+			details["storage"] = root["pool"]
+		}
+		runtime := "lxd"
+		if client == "incus" {
+			runtime = "incus"
+		}
+		return &model.Source{
+			Type:       model.SourceContainer,
+			Name:       runtime,
+			Confidence: 0.9,
+			Evidence:   []string{"cgroup path names LXC container " + name, client + " knows instance " + name},
+			Details:    details,
+		}
+	}
+
+	return &model.Source{
+		Type:       model.SourceContainer,
+		Name:       "lxc",
+		Confidence: 0.9,
+		Evidence:   []string{"cgroup path names LXC container " + name},
+		Details:    details,
+	}
+}
+
+// lxcGuest reports whether witr itself runs inside an LXC/LXD container
+func lxcGuest() bool {
+	if data, err := os.ReadFile("/run/systemd/container"); err == nil {
+		return strings.TrimSpace(string(data)) == "lxc"
+	}
+	data, err := os.ReadFile("/proc/1/environ")
+	if err != nil {
+		return false
+	}
+	for _, e := range strings.Split(string(data), "\x00") {
+		if e == "container=lxc" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+package source
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// Multi-call binaries run under the name of whichever applet they implement
+var multiCallBinaries = map[string]bool{"busybox": true, "toybox": true}
+
+// commLen is the length the kernel truncates a process's comm to
+const commLen = 15
+
+// ExeMismatchWarning warns when argv[0] or the process name (comm) names a
+// different program than the executable that is running, as malware
+// masquerading as a system process does, or returns "". Names that resolve to
+// the executable through symlinks or PATH, versioned interpreters (python3
+// running python3.11) and multi-call binaries are not reported.
+func ExeMismatchWarning(p model.Process) string {
+	// Symlinks only resolve against the host filesystem
+	resolve := func(name string) string {
+		if p.Root != "" && p.Root != "/" {
+			return ""
+		}
+		path, err := exec.LookPath(name)
+		if err != nil {
+			return ""
+		}
+		path, _ = filepath.EvalSymlinks(path)
+		return path
+	}
+	return exeMismatch(p.Command, p.Argv0(), p.Exe, resolve)
+}
+
+func exeMismatch(comm, argv0, exe string, resolve func(string) string) string {
+	// Kernel threads and unreadable executables have no exe to compare
+	if exe == "" || argv0 == "" || multiCallBinaries[filepath.Base(exe)] {
+		return ""
+	}
+	exe = strings.TrimSuffix(exe, " (deleted)")
+	name := filepath.Base(exe)
+	if !sameProgram(filepath.Base(argv0), name) && resolve(argv0) != exe {
+		return "Process name does not match its executable: argv[0] is \"" + argv0 + "\" but the running binary is " + exe
+	}
+	// Daemons that name their roles keep the program first, e.g. "tmux: server"
+	comm, _, _ = strings.Cut(comm, ": ")
+	if comm != "" && !sameProgram(comm, name) && !sameProgram(comm, filepath.Base(argv0)) {
+		return "Process name does not match its executable: the process calls itself \"" + comm + "\" but the running binary is " + exe
+	}
+	return ""
+}
+
+// sameProgram reports whether two program names match, allowing for
+// versioned or suffixed variants (python3 and python3.11, vim and vim.basic)
+// and for names cut to the length of comm
+func sameProgram(a, b string) bool {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	if a == "" || !strings.HasPrefix(b, a) {
+		return false
+	}
+	rest := strings.TrimPrefix(b, a)
+	return rest == "" || len(a) == commLen || strings.ContainsRune("0123456789.-_", rune(rest[0]))
+}
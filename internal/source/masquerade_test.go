@@ -0,0 +1,35 @@
+package source
+
+import (
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestExeMismatch(t *testing.T) {
+	links := map[string]string{"editor": "/usr/bin/vim.basic", "/sbin/init": "/usr/lib/systemd/systemd"}
+	resolve := func(name string) string { return links[name] }
+	tests := []struct {
+		comm, cmdline, exe string
+		mismatch           bool
+	}{
+		{"nginx", "nginx: worker process", "/usr/sbin/nginx", false},
+		{"bash", "-bash", "/usr/bin/bash", false},
+		{"python3", "python3 app.py", "/usr/bin/python3.11", false},
+		{"editor", "editor notes.txt", "/usr/bin/vim.basic", false},
+		{"systemd", "/sbin/init splash", "/usr/lib/systemd/systemd", false},
+		{"gdm-session-wor", "gdm-session-worker [pam/gdm-password]", "/usr/libexec/gdm-session-worker", false},
+		{"sh", "sh -c true", "/bin/busybox", false},
+		{"tmux: server", "tmux", "/usr/bin/tmux", false},
+		{"kworker/0:1", "", "", false},
+		{"sshd", "sshd", "/tmp/.x/miner", true},
+		{"sleep", "[kworker/0:2]", "/usr/bin/sleep", true},
+		{"kthreadd", "/usr/bin/sleep 60", "/usr/bin/sleep", true},
+		{"s", "s", "/usr/bin/sshd", true},
+	}
+	for _, tt := range tests {
+		if got := exeMismatch(tt.comm, model.Process{Cmdline: tt.cmdline}.Argv0(), tt.exe, resolve) != ""; got != tt.mismatch {
+			t.Errorf("exeMismatch(%q, %q, %q) = %v, want %v", tt.comm, tt.cmdline, tt.exe, got, tt.mismatch)
+		}
+	}
+}
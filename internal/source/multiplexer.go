@@ -0,0 +1,108 @@
+package source
+
+import (
+	"os/exec"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// detectMultiplexer reports the tmux or screen session a process was started in
+func detectMultiplexer(ancestry []model.Process) *model.Source {
+	for i, p := range ancestry {
+		cmd := strings.ToLower(p.Command)
+		switch {
+		case strings.HasPrefix(cmd, "tmux"):
+			return &model.Source{
+				Type:       model.SourceShell,
+				Name:       "tmux",
+				Confidence: 0.7,
+				Evidence:   []string{ancestorEvidence(p, "is a tmux server")},
+				Details:    tmuxDetails(ancestry[i+1:]),
+			}
+		case cmd == "screen":
+			return &model.Source{
+				Type:       model.SourceShell,
+				Name:       "screen",
+				Confidence: 0.7,
+				Evidence:   []string{ancestorEvidence(p, "is a screen session")},
+				Details:    screenDetails(ancestry[i+1:]),
+			}
+		}
+	}
+	return nil
+}
+
+// inheritedEnv returns the value of key from the closest process in the chain
+// that has it set, starting with the target
+func inheritedEnv(below []model.Process, key string) string {
+	for i := len(below) - 1; i >= 0; i-- {
+		if v := envValue(below[i].Env, key); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func tmuxDetails(below []model.Process) map[string]string {
+	// TMUX=<socket>,<server pid>,<session index>; TMUX_PANE=%<pane id>
+	tmuxEnv := inheritedEnv(below, "TMUX")
+	pane := inheritedEnv(below, "TMUX_PANE")
+	if tmuxEnv == "" || pane == "" {
+		return nil
+	}
+	socket := strings.Split(tmuxEnv, ",")[0]
+
+	out, err := exec.Command("tmux", "-S", socket, "display-message", "-p", "-t", pane,
+		"#{session_name}\t#{window_index}\t#{window_name}").Output()
+	if err != nil {
+		return map[string]string{"window": "pane " + pane}
+	}
+	parts := strings.Split(strings.TrimSpace(string(out)), "\t")
+	if len(parts) < 3 {
+		return nil
+	}
+
+	details := map[string]string{
+		"session": parts[0],
+		"window":  parts[1] + " (" + parts[2] + ")",
+	}
+
+	clients, err := exec.Command("tmux", "-S", socket, "list-clients", "-t", parts[0], "-F", "#{client_tty}").Output()
+	if err == nil {
+		ttys := strings.Fields(string(clients))
+		if len(ttys) > 0 {
+			details["client"] = strings.Join(ttys, ", ")
+		} else {
+			details["client"] = "detached"
+		}
+	}
+	return details
+}
+
+func screenDetails(below []model.Process) map[string]string {
+	// STY=<server pid>.<session name>; WINDOW=<window number>
+	sty := inheritedEnv(below, "STY")
+	if sty == "" {
+		return nil
+	}
+	details := make(map[string]string)
+	if _, name, ok := strings.Cut(sty, "."); ok {
+		details["session"] = name
+	} else {
+		details["session"] = sty
+	}
+	if window := inheritedEnv(below, "WINDOW"); window != "" {
+		details["window"] = window
+	}
+
+	// screen -ls marks sessions as (Attached) or (Detached)
+	out, _ := exec.Command("screen", "-ls", sty).CombinedOutput()
+	switch {
+	case strings.Contains(string(out), "(Attached)"):
+		details["client"] = "attached"
+	case strings.Contains(string(out), "(Detached)"):
+		details["client"] = "detached"
+	}
+	return details
+}
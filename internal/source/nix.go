@@ -0,0 +1,22 @@
+package source
+
+import "strings"
+
+const nixStore = "/nix/store/"
+
+// nixDerivation returns the derivation name ("nginx-1.24.0") and store path
+// of a file inside /nix/store, or empty strings for other paths
+func nixDerivation(path string) (name, storePath string) {
+	rest, ok := strings.CutPrefix(path, nixStore)
+	if !ok {
+		return "", ""
+	}
+	entry, _, _ := strings.Cut(rest, "/")
+
+	// Store entries are "<32-char hash>-<name>"
+	hash, name, ok := strings.Cut(entry, "-")
+	if !ok || len(hash) != 32 || name == "" {
+		return "", ""
+	}
+	return name, nixStore + entry
+}
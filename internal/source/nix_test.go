@@ -0,0 +1,19 @@
+package source
+
+import "testing"
+
+func TestNixDerivation(t *testing.T) {
+	tests := []struct {
+		path, name, store string
+	}{
+		{"/nix/store/0c3pyk2wbzfbb1qclqnx1arkh6irjn8v-nginx-1.24.0/bin/nginx", "nginx-1.24.0", "/nix/store/0c3pyk2wbzfbb1qclqnx1arkh6irjn8v-nginx-1.24.0"},
+		{"/nix/store/short-nginx/bin/nginx", "", ""},
+		{"/usr/sbin/nginx", "", ""},
+	}
+	for _, tt := range tests {
+		name, store := nixDerivation(tt.path)
+		if name != tt.name || store != tt.store {
+			t.Errorf("nixDerivation(%q) = %q, %q; want %q, %q", tt.path, name, store, tt.name, tt.store)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+//go:build linux
+
+package source
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pranshuparmar/witr/internal/runtimeapi"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// nixosOptionPolicy bounds nixos-option, which evaluates the whole system
+// configuration. One timeout disables it for the rest of the run, since
+// every later evaluation would be as slow.
+var nixosOptionPolicy = runtimeapi.Policy{
+	Timeout:   5 * time.Second,
+	Threshold: 1,
+	Cooldown:  10 * time.Minute,
+}
+
+var (
+	nixosModulesMu    sync.Mutex
+	nixosModulesCache = make(map[string][]string)
+)
+
+// detectNixOS reports systemd services generated by a NixOS configuration,
+// whose unit files are symlinks into /nix/store
+func detectNixOS(ancestry []model.Process) *model.Source {
+	if _, err := os.Stat("/etc/NIXOS"); err != nil {
+		return nil
+	}
+	for i := len(ancestry) - 1; i >= 0; i-- {
+		service := ancestry[i].Service
+		if service == "" {
+			continue
+		}
+		fragment := systemctlShow(service, "FragmentPath")["FragmentPath"]
+		if fragment == "" {
+			return nil
+		}
+		unitFile, err := filepath.EvalSymlinks(fragment)
+		if err != nil || !strings.HasPrefix(unitFile, nixStore) {
+			return nil
+		}
+
+		details := map[string]string{
+			"unit file": unitFile,
+			"manager":   systemManager,
+			"hint":      systemdHint(service, ""),
+		}
+		if modules := nixosModules(strings.TrimSuffix(service, ".service")); len(modules) > 0 {
+			details["module"] = strings.Join(modules, ", ")
+		}
+		return &model.Source{
+			Type:       model.SourceSystemd,
+			Name:       service,
+			Confidence: 0.9,
+			Evidence:   []string{"unit file " + fragment + " links into the Nix store", "/etc/NIXOS exists"},
+			Details:    details,
+		}
+	}
+	return nil
+}
+
+// nixosModules returns the NixOS modules that define systemd.services.<name>,
+// caching the answer per unit
+func nixosModules(name string) []string {
+	nixosModulesMu.Lock()
+	defer nixosModulesMu.Unlock()
+	if modules, ok := nixosModulesCache[name]; ok {
+		return modules
+	}
+	var modules []string
+	if _, err := exec.LookPath("nixos-option"); err == nil {
+		if out, err := nixosOptionPolicy.Output("nixos-option", "systemd.services."+name); err == nil {
+			modules = parseNixOSDefinedBy(string(out))
+		}
+	}
+	nixosModulesCache[name] = modules
+	return modules
+}
+
+// parseNixOSDefinedBy extracts the "Defined by:" file list from nixos-option
+// output, shortening paths to the part below the nixpkgs checkout
+func parseNixOSDefinedBy(out string) []string {
+	var modules []string
+	inList := false
+	for line := range strings.Lines(out) {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "Defined by:":
+			inList = true
+		case inList && line == "":
+			return modules
+		case inList && strings.HasSuffix(line, ":"):
+			return modules
+		case inList:
+			path := strings.Trim(line, `"`)
+			if idx := strings.Index(path, "/nixos/modules/"); idx != -1 {
+				path = path[idx+1:]
+			}
+			modules = append(modules, path)
+		}
+	}
+	return modules
+}
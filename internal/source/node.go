@@ -0,0 +1,116 @@
+package source
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// ecosystemFiles are the config file names pm2 looks for by convention
+var ecosystemFiles = []string{
+	"ecosystem.config.js",
+	"ecosystem.config.cjs",
+	"ecosystem.config.mjs",
+	"ecosystem.json",
+	"ecosystem.yml",
+	"ecosystem.yaml",
+	"process.json",
+}
+
+// nodeManager returns the node process manager a process belongs to, if any
+func nodeManager(p model.Process) string {
+	pname := strings.ReplaceAll(strings.ToLower(p.Command), " ", "")
+	pcmd := strings.ReplaceAll(strings.ToLower(p.Cmdline), " ", "")
+	switch {
+	case strings.Contains(pname, "pm2") || strings.Contains(pcmd, "pm2"):
+		return "pm2"
+	case strings.Contains(pname, "forever") || strings.Contains(pcmd, "/forever/"):
+		return "forever"
+	case strings.Contains(pname, "nodemon") || strings.Contains(pcmd, "nodemon"):
+		return "nodemon"
+	}
+	return ""
+}
+
+// nodeManagerDetails collects app details for pm2, forever and nodemon.
+// manager is the ancestor that matched, target is the explained process.
+func nodeManagerDetails(name string, manager, target model.Process) map[string]string {
+	details := make(map[string]string)
+
+	switch name {
+	case "pm2":
+		// pm2 exports the app definition into the environment of every child
+		if app := envValue(target.Env, "name"); app != "" {
+			details["app"] = app
+			if id := envValue(target.Env, "pm_id"); id != "" {
+				details["app"] = app + " (id " + id + ")"
+			}
+		}
+		if script := envValue(target.Env, "pm_exec_path"); script != "" {
+			details["script"] = script
+		}
+		dir := envValue(target.Env, "pm_cwd")
+		if dir == "" {
+			dir = target.WorkingDir
+		}
+		if eco := findEcosystemFile(dir); eco != "" {
+			details["ecosystem"] = eco
+		}
+	case "forever":
+		if script := argAfter(manager.Cmdline, "/monitor"); script != "" {
+			details["script"] = script
+		}
+	case "nodemon":
+		if script := argAfter(manager.Cmdline, "nodemon"); script != "" {
+			details["script"] = script
+		}
+	}
+
+	if len(details) == 0 {
+		return nil
+	}
+	return details
+}
+
+func findEcosystemFile(dir string) string {
+	if dir == "" || dir == "unknown" {
+		return ""
+	}
+	for _, name := range ecosystemFiles {
+		path := filepath.Join(dir, name)
+		if fi, err := os.Stat(path); err == nil && !fi.IsDir() {
+			return path
+		}
+	}
+	return ""
+}
+
+// argAfter returns the first non-flag argument following the field containing marker
+func argAfter(cmdline, marker string) string {
+	fields := strings.Fields(cmdline)
+	for i, f := range fields {
+		if !strings.Contains(f, marker) {
+			continue
+		}
+		for _, arg := range fields[i+1:] {
+			if !strings.HasPrefix(arg, "-") {
+				return arg
+			}
+		}
+		return ""
+	}
+	return ""
+}
+
+// envValue returns the value of key in a KEY=value environment list
+func envValue(env []string, key string) string {
+	prefix := key + "="
+	for _, e := range env {
+		if strings.HasPrefix(e, prefix) {
+			return e[len(prefix):]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,110 @@
+//go:build linux
+
+package source
+
+import (
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// Portal and bus processes that spawn helpers on behalf of applications.
+// comm is truncated to 15 characters, so match on prefixes.
+var portalParents = []struct {
+	prefix string
+	name   string
+	role   string
+}{
+	{"xdg-desktop-por", "xdg-desktop-portal", "portal helper started on behalf of a sandboxed or desktop application"},
+	{"xdg-document-po", "xdg-document-portal", "document portal helper exposing files to a sandboxed application"},
+	{"xdg-permission-", "xdg-permission-store", "permission store helper used by the portals"},
+}
+
+var unitRandomSuffix = regexp.MustCompile(`(@[^.]*|-[0-9]+)$`)
+
+// detectPortal attributes direct children of xdg-desktop-portal to the
+// requesting application, using the app ID systemd records in the scope the
+// portal starts the child in (app-<launcher>-<app id>-<random>.scope). The
+// children's own descendants were started by them, not by the portal.
+func detectPortal(ancestry []model.Process) *model.Source {
+	if len(ancestry) < 2 {
+		return nil
+	}
+	target := ancestry[len(ancestry)-1]
+	parent := ancestry[len(ancestry)-2]
+
+	for _, pp := range portalParents {
+		if !strings.HasPrefix(parent.Command, pp.prefix) {
+			continue
+		}
+		details := map[string]string{"role": pp.role}
+		if app := portalApp(target); app != "" {
+			details["app"] = app
+		}
+		return &model.Source{
+			Type:       model.SourceDesktop,
+			Name:       pp.name,
+			Confidence: 0.7,
+			Evidence:   []string{ancestorEvidence(parent, "is "+pp.name)},
+			Details:    details,
+		}
+	}
+	return nil
+}
+
+// portalApp returns the application a portal child was started for: the
+// Flatpak app in its environment, or the app of the scope it runs in. A
+// child left in the portal's own service unit names no application.
+func portalApp(child model.Process) string {
+	if id := envValue(child.Env, "FLATPAK_ID"); id != "" {
+		return id + " (flatpak)"
+	}
+	data, err := os.ReadFile("/proc/" + itoa(child.PID) + "/cgroup")
+	if err != nil {
+		return ""
+	}
+	_, unit := userUnitFromCgroup(string(data))
+	if !strings.HasSuffix(unit, ".scope") {
+		return ""
+	}
+	return appIDFromUnit(unit)
+}
+
+// requestingApp returns the application ID a portal child runs for
+func requestingApp(p model.Process) string {
+	if id := envValue(p.Env, "FLATPAK_ID"); id != "" {
+		return id + " (flatpak)"
+	}
+	data, err := os.ReadFile("/proc/" + itoa(p.PID) + "/cgroup")
+	if err != nil {
+		return ""
+	}
+	_, unit := userUnitFromCgroup(string(data))
+	return appIDFromUnit(unit)
+}
+
+// appIDFromUnit extracts the application ID from a unit named per the XDG
+// systemd conventions: app-[<launcher>-]<app id>[@<random>].service or
+// app-[<launcher>-]<app id>-<random>.scope
+func appIDFromUnit(unit string) string {
+	name, ok := strings.CutPrefix(unit, "app-")
+	if !ok {
+		return ""
+	}
+	name = strings.TrimSuffix(strings.TrimSuffix(name, ".service"), ".scope")
+	name = unitRandomSuffix.ReplaceAllString(name, "")
+	name = strings.ReplaceAll(name, `\x2d`, "-")
+
+	for _, launcher := range []string{"gnome-", "kde-", "flatpak-", "dbus-"} {
+		if rest, ok := strings.CutPrefix(name, launcher); ok {
+			name = rest
+			break
+		}
+	}
+	if name == "" {
+		return ""
+	}
+	return name
+}
@@ -0,0 +1,23 @@
+//go:build linux
+
+package source
+
+import (
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestDetectPortalDirectChildOnly(t *testing.T) {
+	portal := model.Process{PID: 1 << 30, Command: "xdg-desktop-por"}
+	helper := model.Process{PID: 1<<30 + 1, Command: "helper", Env: []string{"FLATPAK_ID=org.example.App"}}
+	shell := model.Process{PID: 1<<30 + 2, Command: "bash"}
+
+	src := detectPortal([]model.Process{{PID: 1, Command: "systemd"}, portal, helper})
+	if src == nil || src.Details["app"] != "org.example.App (flatpak)" {
+		t.Fatalf("detectPortal() = %+v, want the helper attributed to org.example.App", src)
+	}
+	if src := detectPortal([]model.Process{{PID: 1, Command: "systemd"}, portal, helper, shell}); src != nil {
+		t.Errorf("detectPortal() for a grandchild = %+v, want nil", src)
+	}
+}
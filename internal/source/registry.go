@@ -0,0 +1,120 @@
+package source
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// Detector recognizes one kind of source from a process ancestry (root
+// first, target last). Detect returns nil when the detector does not apply.
+type Detector interface {
+	Name() string
+	Detect(ancestry []model.Process) *model.Source
+}
+
+// DetectorFunc adapts a function to the Detector interface
+type DetectorFunc struct {
+	ID string
+	Fn func(ancestry []model.Process) *model.Source
+}
+
+func (f DetectorFunc) Name() string { return f.ID }
+
+func (f DetectorFunc) Detect(ancestry []model.Process) *model.Source { return f.Fn(ancestry) }
+
+// Priorities of the built-in detector groups. Detectors run in ascending
+// priority and the first match wins, so a custom detector registered below
+// PrioritySupervisor is tried before the generic supervisor scan (which
+// otherwise matches systemd or launchd at PID 1 on almost every host).
+const (
+	PriorityRuntime        = 100 // subsystems, containers, app packaging
+	PrioritySession        = 200 // backup jobs, multiplexers, SSH, cron
+	PriorityDesktop        = 300 // desktop session components
+	PriorityUnit           = 400 // specific systemd units, udev, WSL
+	PrioritySupervisor     = 500 // generic supervisor scan
+	PriorityServiceManager = 600 // systemd and launchd at PID 1
+	PriorityShell          = 700 // interactive shells
+)
+
+type registration struct {
+	detector Detector
+	priority int
+	seq      int
+}
+
+var (
+	registryMu sync.Mutex
+	registry   []registration
+	sorted     bool
+)
+
+// Register adds a detector at the given priority. Detectors with equal
+// priority run in registration order.
+func Register(d Detector, priority int) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry = append(registry, registration{detector: d, priority: priority, seq: len(registry)})
+	sorted = false
+}
+
+// detectors returns the registered detectors in the order they are tried
+func detectors() []Detector {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if !sorted {
+		sort.SliceStable(registry, func(i, j int) bool {
+			if registry[i].priority != registry[j].priority {
+				return registry[i].priority < registry[j].priority
+			}
+			return registry[i].seq < registry[j].seq
+		})
+		sorted = true
+	}
+	out := make([]Detector, len(registry))
+	for i, r := range registry {
+		out[i] = r.detector
+	}
+	return out
+}
+
+func init() {
+	builtin := []struct {
+		priority int
+		name     string
+		fn       func([]model.Process) *model.Source
+	}{
+		{PriorityRuntime, "subsystem", detectSubsystem},
+		{PriorityRuntime, "container", detectContainer},
+		{PriorityRuntime, "appimage", detectAppImage},
+		{PriorityRuntime, "flatpak", detectFlatpak},
+		{PriorityRuntime, "snap", detectSnap},
+		{PrioritySession, "backup", detectBackup},
+		{PrioritySession, "multiplexer", detectMultiplexer},
+		{PrioritySession, "ssh", detectSSH},
+		{PrioritySession, "anacron", detectAnacron},
+		{PrioritySession, "at", detectAt},
+		{PrioritySession, "cron", detectCron},
+		{PriorityDesktop, "audio", detectAudio},
+		{PriorityDesktop, "dbus", detectDBus},
+		{PriorityDesktop, "portal", detectPortal},
+		{PriorityDesktop, "autostart", detectAutostart},
+		{PriorityDesktop, "launcher", detectLauncher},
+		{PriorityDesktop, "display", detectDisplayServer},
+		{PriorityDesktop, "session", detectLoginSession},
+		{PriorityUnit, "systemd-user", detectSystemdUser},
+		{PriorityUnit, "udev", detectUdev},
+		{PriorityUnit, "systemd-timer", detectSystemdTimer},
+		{PriorityUnit, "systemd-socket", detectSystemdSocket},
+		{PriorityUnit, "nixos", detectNixOS},
+		{PriorityUnit, "wsl", detectWSL},
+		{PrioritySupervisor, "supervisor", detectSupervisor},
+		{PriorityServiceManager, "systemd", detectSystemd},
+		{PriorityServiceManager, "launchd", detectLaunchd},
+		{PriorityShell, "shell", detectShell},
+	}
+	for _, b := range builtin {
+		Register(DetectorFunc{ID: b.name, Fn: b.fn}, b.priority)
+	}
+}
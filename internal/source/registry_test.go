@@ -0,0 +1,39 @@
+package source
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func TestRegisterCustomDetector(t *testing.T) {
+	// Unregister acme afterwards so other tests see only the built-ins
+	registryMu.Lock()
+	saved := slices.Clone(registry)
+	registryMu.Unlock()
+	t.Cleanup(func() {
+		registryMu.Lock()
+		registry, sorted = saved, false
+		registryMu.Unlock()
+	})
+
+	Register(DetectorFunc{ID: "acme", Fn: func(ancestry []model.Process) *model.Source {
+		for _, p := range ancestry {
+			if p.Command == "acme-sup" {
+				return &model.Source{Type: model.SourceSupervisor, Name: "acme", Confidence: 0.9}
+			}
+		}
+		return nil
+	}}, PrioritySupervisor-1)
+
+	// systemd at PID 1 would otherwise match the generic supervisor scan
+	ancestry := []model.Process{
+		{PID: 1, Command: "systemd", Cmdline: "/sbin/init"},
+		{PID: 200, PPID: 1, Command: "acme-sup"},
+		{PID: 300, PPID: 200, Command: "worker"},
+	}
+	if src := detect(ancestry); src.Name != "acme" {
+		t.Errorf("detect() = %q, want the custom acme detector", src.Name)
+	}
+}
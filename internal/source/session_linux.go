@@ -0,0 +1,156 @@
+//go:build linux
+
+package source
+
+import (
+	"os"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// Display managers and their per-session helpers, keyed by comm
+var displayManagers = map[string]string{
+	"gdm":             "gdm",
+	"gdm-session-wor": "gdm",
+	"gdm-wayland-ses": "gdm",
+	"gdm-x-session":   "gdm",
+	"sddm":            "sddm",
+	"sddm-helper":     "sddm",
+	"lightdm":         "lightdm",
+	"lxdm":            "lxdm",
+	"lxdm-session":    "lxdm",
+	"greetd":          "greetd",
+	"ly":              "ly",
+	"xdm":             "xdm",
+}
+
+// detectLoginSession attributes GUI applications to the desktop session that
+// started them and the display manager the user logged in through. Modern
+// sessions run applications in systemd --user scopes, so when no session
+// manager is an ancestor the logind session of the process is used instead.
+func detectLoginSession(ancestry []model.Process) *model.Source {
+	if len(ancestry) < 2 {
+		return nil
+	}
+	target := ancestry[len(ancestry)-1]
+
+	name, dm := "", ""
+	var evidence []string
+	for i := len(ancestry) - 2; i >= 0; i-- {
+		p := ancestry[i]
+		// Commands typed into a terminal belong to the shell, not the session
+		if name == "" && shells[strings.TrimPrefix(p.Command, "-")] {
+			return nil
+		}
+		if label, ok := sessionManagers[p.Command]; ok && name == "" {
+			name = label + " session (" + sessionBinary(p) + ")"
+			evidence = append(evidence, ancestorEvidence(p, "is a desktop session manager"))
+		}
+		if label, ok := displayManagers[p.Command]; ok && dm == "" {
+			dm = label
+			evidence = append(evidence, ancestorEvidence(p, "is a display manager"))
+		}
+	}
+
+	session := envValue(target.Env, "XDG_SESSION_ID")
+	if session == "" {
+		session = auditSession(target.PID)
+	}
+	var props map[string]string
+	if session != "" {
+		props = loginctlSession(session)
+	}
+
+	if name == "" {
+		// Without a session manager ancestor, only claim applications the
+		// session launched into app-*.scope units of a graphical session
+		if !graphicalSession(props) || !inAppScope(target.PID) {
+			return nil
+		}
+		desktop := props["Desktop"]
+		if desktop == "" {
+			desktop = envValue(target.Env, "XDG_CURRENT_DESKTOP")
+		}
+		if desktop == "" {
+			desktop = "desktop"
+		}
+		name = desktop + " session"
+		evidence = append(evidence, "process runs in an application scope of graphical session "+session)
+	}
+	if dm == "" && props != nil {
+		// logind records the PAM service the session authenticated through
+		// (gdm-password, sddm, lightdm)
+		if svc := props["Service"]; svc != "" {
+			for _, label := range displayManagers {
+				if strings.HasPrefix(svc, label) {
+					dm = label
+					evidence = append(evidence, "logind session "+session+" was opened by PAM service "+svc)
+					break
+				}
+			}
+		}
+	}
+
+	details := make(map[string]string)
+	if session != "" {
+		desc := session
+		if props["Type"] != "" {
+			desc += " (" + props["Type"] + ")"
+		}
+		details["session"] = desc
+	}
+	if user := props["Name"]; user != "" {
+		details["user"] = user
+	} else if target.User != "" {
+		details["user"] = target.User
+	}
+	if seat := props["Seat"]; seat != "" {
+		details["seat"] = seat
+	} else if seat := envValue(target.Env, "XDG_SEAT"); seat != "" {
+		details["seat"] = seat
+	}
+	if dm != "" {
+		details["display manager"] = dm
+	}
+
+	confidence := 0.7
+	if dm != "" && details["seat"] != "" {
+		confidence = 0.8
+	}
+	return &model.Source{
+		Type:       model.SourceDesktop,
+		Name:       name,
+		Confidence: confidence,
+		Evidence:   evidence,
+		Details:    details,
+	}
+}
+
+// sessionBinary returns the base name of a session manager's executable,
+// which is not truncated like comm
+func sessionBinary(p model.Process) string {
+	if fields := strings.Fields(p.Cmdline); len(fields) > 0 {
+		return fields[0][strings.LastIndex(fields[0], "/")+1:]
+	}
+	return p.Command
+}
+
+func graphicalSession(props map[string]string) bool {
+	switch props["Type"] {
+	case "x11", "wayland", "mir":
+		return props["Class"] == "" || props["Class"] == "user"
+	}
+	return false
+}
+
+// inAppScope reports whether pid runs in an app-*.scope unit, where desktop
+// sessions place the applications they launch
+func inAppScope(pid int) bool {
+	data, err := os.ReadFile("/proc/" + itoa(pid) + "/cgroup")
+	if err != nil {
+		return false
+	}
+	_, unit := userUnitFromCgroup(string(data))
+	return strings.HasPrefix(unit, "app-") && strings.HasSuffix(unit, ".scope")
+}
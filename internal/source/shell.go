@@ -1,6 +1,14 @@
 package source
 
-import "github.com/pranshuparmar/witr/pkg/model"
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
 
 var shells = map[string]bool{
 	"bash": true,
@@ -9,15 +17,102 @@ var shells = map[string]bool{
 	"fish": true,
 }
 
+// Startup files read by interactive and login shells, relative to $HOME
+var profileFiles = []string{
+	".bash_profile",
+	".bash_login",
+	".profile",
+	".bashrc",
+	".zshenv",
+	".zprofile",
+	".zshrc",
+	".zlogin",
+	".config/fish/config.fish",
+}
+
+// Processes started this soon after their shell are treated as session startup
+const sessionStartWindow = 10 * time.Second
+
 func detectShell(ancestry []model.Process) *model.Source {
-	for _, p := range ancestry {
-		if shells[p.Command] {
-			return &model.Source{
+	for i, p := range ancestry {
+		if shells[strings.TrimPrefix(p.Command, "-")] {
+			src := &model.Source{
 				Type:       model.SourceShell,
 				Name:       p.Command,
 				Confidence: 0.5,
+				Evidence:   []string{ancestorEvidence(p, "is an interactive shell")},
 			}
+			if i+1 < len(ancestry) {
+				if entry, text := profileOrigin(p, ancestry[len(ancestry)-1]); entry != "" {
+					src.Confidence = 0.7
+					src.Evidence = append(src.Evidence, "command appears in shell startup file "+entry)
+					src.Details = map[string]string{
+						"entry":   entry,
+						"command": text,
+					}
+				}
+			}
+			return src
 		}
 	}
 	return nil
 }
+
+// profileOrigin looks for the target's command in the shell's startup files
+// when the target was started by a login shell or right after the shell began
+func profileOrigin(shell, target model.Process) (string, string) {
+	login := strings.HasPrefix(shell.Cmdline, "-")
+	for _, arg := range strings.Fields(shell.Cmdline) {
+		if arg == "-l" || arg == "--login" {
+			login = true
+		}
+	}
+	atStartup := !shell.StartedAt.IsZero() && target.StartedAt.Sub(shell.StartedAt) < sessionStartWindow
+	if !login && !atStartup {
+		return "", ""
+	}
+
+	home := envValue(shell.Env, "HOME")
+	if home == "" {
+		home = envValue(target.Env, "HOME")
+	}
+	if home == "" {
+		return "", ""
+	}
+
+	name := filepath.Base(target.Exe)
+	if target.Exe == "" {
+		name = target.Command
+	}
+	if name == "" {
+		return "", ""
+	}
+
+	for _, rel := range profileFiles {
+		file := filepath.Join(home, rel)
+		if line, text := findCommandLine(file, name); line > 0 {
+			return file + ":" + strconv.Itoa(line), text
+		}
+	}
+	return "", ""
+}
+
+// findCommandLine returns the first non-comment line in file that invokes name
+func findCommandLine(file, name string) (int, string) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return 0, ""
+	}
+	for i, line := range strings.Split(string(data), "\n") {
+		text := strings.TrimSpace(line)
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		for _, field := range strings.Fields(text) {
+			if filepath.Base(strings.Trim(field, `"'();&`)) == name {
+				return i + 1, text
+			}
+		}
+	}
+	return 0, ""
+}
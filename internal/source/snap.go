@@ -0,0 +1,107 @@
+package source
+
+import (
+	"os"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// detectSnap reports processes confined by snapd: snap apps and snap services
+// (snap.<name>.<app>.service), identified by their environment, /snap/ exe
+// path, cgroup or snap-confine in the ancestry
+func detectSnap(ancestry []model.Process) *model.Source {
+	if len(ancestry) == 0 {
+		return nil
+	}
+	target := ancestry[len(ancestry)-1]
+
+	name := envValue(target.Env, "SNAP_INSTANCE_NAME")
+	if name == "" {
+		name = envValue(target.Env, "SNAP_NAME")
+	}
+	revision := envValue(target.Env, "SNAP_REVISION")
+
+	// /snap/<name>/<revision>/...
+	if rest, ok := strings.CutPrefix(target.Exe, "/snap/"); ok {
+		parts := strings.SplitN(rest, "/", 3)
+		if name == "" && len(parts) > 0 {
+			name = parts[0]
+		}
+		if revision == "" && len(parts) > 1 {
+			revision = parts[1]
+		}
+	}
+
+	unit := ""
+	if data, err := os.ReadFile("/proc/" + itoa(target.PID) + "/cgroup"); err == nil {
+		for _, comp := range strings.FieldsFunc(string(data), func(r rune) bool { return r == '/' || r == '\n' }) {
+			if strings.HasPrefix(comp, "snap.") && (strings.HasSuffix(comp, ".service") || strings.HasSuffix(comp, ".scope")) {
+				unit = comp
+			}
+		}
+	}
+	if strings.HasPrefix(target.Service, "snap.") {
+		unit = target.Service
+	}
+
+	confined := false
+	for _, p := range ancestry {
+		if p.Command == "snap-confine" || p.Command == "snap-exec" {
+			confined = true
+		}
+	}
+	if name == "" && unit == "" && !confined {
+		return nil
+	}
+
+	// snap.<name>.<app>.service or snap.<name>.<app>-<uuid>.scope
+	app := ""
+	if unit != "" {
+		parts := strings.SplitN(strings.TrimPrefix(unit, "snap."), ".", 2)
+		if name == "" {
+			name = parts[0]
+		}
+		if len(parts) > 1 {
+			app = strings.TrimSuffix(strings.TrimSuffix(parts[1], ".service"), ".scope")
+			if idx := strings.LastIndex(app, "-"); idx != -1 && strings.HasSuffix(unit, ".scope") {
+				app = app[:idx]
+			}
+		}
+	}
+	if name == "" {
+		name = "unknown snap"
+	}
+
+	details := make(map[string]string)
+	if revision != "" {
+		details["revision"] = revision
+	}
+	if strings.HasSuffix(unit, ".service") {
+		details["kind"] = "snap service (" + unit + ")"
+	} else {
+		kind := "snap app"
+		if app != "" {
+			kind += " (" + name + "." + app + ")"
+		}
+		details["kind"] = kind
+	}
+
+	var evidence []string
+	if strings.HasPrefix(target.Exe, "/snap/") {
+		evidence = append(evidence, "executable lives under /snap/")
+	}
+	if unit != "" {
+		evidence = append(evidence, "process belongs to snap unit "+unit)
+	}
+	if confined {
+		evidence = append(evidence, "ancestry includes snap-confine or snap-exec")
+	}
+	return &model.Source{
+		Type:       model.SourceSnap,
+		Name:       name,
+		Confidence: 0.9,
+		Evidence:   evidence,
+		Details:    details,
+	}
+}
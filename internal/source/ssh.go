@@ -0,0 +1,89 @@
+package source
+
+import (
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// detectSSH reports the SSH session a process was started from. The per-session
+// sshd process is titled "sshd: user@pts/0" (or "sshd: user [priv]"), and the
+// session's shell carries SSH_CONNECTION with the client address.
+func detectSSH(ancestry []model.Process) *model.Source {
+	for i := len(ancestry) - 1; i >= 0; i-- {
+		p := ancestry[i]
+		if p.Command != "sshd" && p.Command != "sshd-session" {
+			continue
+		}
+		user, tty, ok := parseSSHDTitle(p.Cmdline)
+		if !ok {
+			continue
+		}
+
+		details := make(map[string]string)
+		if tty != "" {
+			details["tty"] = tty
+		}
+
+		remote := ""
+		if conn := inheritedEnv(ancestry[i+1:], "SSH_CONNECTION"); conn != "" {
+			// SSH_CONNECTION=<client ip> <client port> <server ip> <server port>
+			fields := strings.Fields(conn)
+			if len(fields) >= 2 {
+				remote = fields[0]
+				details["remote"] = fields[0] + ":" + fields[1]
+			}
+		}
+		if user == "" {
+			user = p.User
+		}
+
+		origin := "SSH session by " + user
+		if remote != "" {
+			origin += "@" + remote
+		}
+		details["origin"] = "started from " + origin
+
+		// Login shells of the session may have started the target from a profile
+		for _, q := range ancestry[i+1 : len(ancestry)-1] {
+			if shells[q.Command] {
+				if entry, text := profileOrigin(q, ancestry[len(ancestry)-1]); entry != "" {
+					details["entry"] = entry
+					details["command"] = text
+				}
+				break
+			}
+		}
+
+		evidence := []string{ancestorEvidence(p, "is a per-session sshd process")}
+		if remote != "" {
+			evidence = append(evidence, "SSH_CONNECTION names the client "+remote)
+		}
+		return &model.Source{
+			Type:       model.SourceSSH,
+			Name:       "sshd",
+			Confidence: 0.8,
+			Evidence:   evidence,
+			Details:    details,
+		}
+	}
+	return nil
+}
+
+// parseSSHDTitle extracts the user and tty from a per-session sshd process title.
+// The listener process ("sshd: /usr/sbin/sshd -D [listener] ...") is rejected.
+func parseSSHDTitle(cmdline string) (user, tty string, ok bool) {
+	rest, found := strings.CutPrefix(cmdline, "sshd: ")
+	if !found || strings.HasPrefix(rest, "/") {
+		return "", "", false
+	}
+	rest = strings.TrimSpace(strings.TrimSuffix(rest, "[priv]"))
+	rest = strings.TrimSpace(strings.TrimSuffix(rest, "[net]"))
+	if u, t, hasTTY := strings.Cut(rest, "@"); hasTTY {
+		return u, t, true
+	}
+	if rest == "" || strings.Contains(rest, " ") {
+		return "", "", false
+	}
+	return rest, "", true
+}
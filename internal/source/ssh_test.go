@@ -0,0 +1,30 @@
+package source
+
+import "testing"
+
+func TestParseSSHDTitle(t *testing.T) {
+	tests := []struct {
+		name     string
+		cmdline  string
+		wantUser string
+		wantTTY  string
+		wantOK   bool
+	}{
+		{"session with tty", "sshd: alice@pts/0", "alice", "pts/0", true},
+		{"privileged monitor", "sshd: alice [priv]", "alice", "", true},
+		{"notty session", "sshd: alice@notty", "alice", "notty", true},
+		{"listener", "sshd: /usr/sbin/sshd -D [listener] 0 of 10-100 startups", "", "", false},
+		{"not sshd title", "/usr/sbin/sshd -D", "", "", false},
+		{"empty", "", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, tty, ok := parseSSHDTitle(tt.cmdline)
+			if user != tt.wantUser || tty != tt.wantTTY || ok != tt.wantOK {
+				t.Errorf("parseSSHDTitle(%q) = (%q, %q, %v), want (%q, %q, %v)",
+					tt.cmdline, user, tty, ok, tt.wantUser, tt.wantTTY, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package source
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// postfixRoles describes the daemons spawned by the postfix master process
+var postfixRoles = map[string]string{
+	"pickup":     "picks up locally submitted mail from the maildrop queue",
+	"qmgr":       "queue manager, schedules delivery of queued mail",
+	"cleanup":    "canonicalizes and enqueues new messages",
+	"smtpd":      "accepts incoming SMTP connections",
+	"smtp":       "delivers mail to remote SMTP servers",
+	"lmtp":       "delivers mail over LMTP",
+	"local":      "delivers mail to local mailboxes",
+	"virtual":    "delivers mail to virtual mailboxes",
+	"pipe":       "delivers mail to an external command",
+	"bounce":     "generates delivery status notifications",
+	"defer":      "records deferred delivery status",
+	"trace":      "records delivery traces",
+	"flush":      "maintains the fast flush queue",
+	"showq":      "lists the mail queue for mailq",
+	"tlsmgr":     "maintains the TLS session cache and PRNG",
+	"anvil":      "enforces connection and rate limits",
+	"scache":     "caches SMTP client connections",
+	"proxymap":   "provides shared lookup table access",
+	"verify":     "verifies recipient addresses",
+	"postscreen": "screens incoming SMTP connections before smtpd",
+	"dnsblog":    "performs DNS allow/denylist lookups for postscreen",
+	"tlsproxy":   "handles TLS for postscreen",
+}
+
+// dovecotRoles describes the processes spawned by the dovecot master process
+var dovecotRoles = map[string]string{
+	"imap-login": "accepts IMAP connections until the user authenticates",
+	"pop3-login": "accepts POP3 connections until the user authenticates",
+	"imap":       "serves an authenticated IMAP session",
+	"pop3":       "serves an authenticated POP3 session",
+	"auth":       "authenticates users",
+	"anvil":      "tracks connections and enforces limits",
+	"log":        "collects logs from other dovecot processes",
+	"config":     "serves configuration to other dovecot processes",
+	"lmtp":       "delivers mail into mailboxes over LMTP",
+	"indexer":    "schedules mailbox indexing",
+	"stats":      "collects statistics",
+}
+
+// detectSubsystem explains children of classic multi-process daemons (postfix,
+// dovecot, cups) by the role they play, rather than as independent processes
+func detectSubsystem(ancestry []model.Process) *model.Source {
+	if len(ancestry) < 2 {
+		return nil
+	}
+	target := ancestry[len(ancestry)-1]
+	parent := ancestry[len(ancestry)-2]
+
+	var name, role string
+	switch {
+	case parent.Command == "master" && postfixRoles[target.Command] != "":
+		name, role = "postfix", target.Command+": "+postfixRoles[target.Command]
+	case parent.Command == "dovecot" && dovecotRoles[target.Command] != "":
+		name, role = "dovecot", target.Command+": "+dovecotRoles[target.Command]
+	case parent.Command == "cupsd":
+		role = cupsRole(target)
+		if role == "" {
+			return nil
+		}
+		name = "cups"
+	default:
+		return nil
+	}
+
+	details := map[string]string{"role": role}
+	if parent.Service != "" {
+		details["unit"] = parent.Service
+	}
+	return &model.Source{
+		Type:       model.SourceSubsystem,
+		Name:       name,
+		Confidence: 0.8,
+		Evidence:   []string{"parent is " + name + "'s " + parent.Command + " (pid " + itoa(parent.PID) + ")"},
+		Details:    details,
+	}
+}
+
+// cupsRole classifies a cupsd child by the directory its executable lives in
+func cupsRole(p model.Process) string {
+	path := p.Exe
+	if path == "" {
+		if fields := strings.Fields(p.Cmdline); len(fields) > 0 {
+			path = fields[0]
+		}
+	}
+	base := filepath.Base(path)
+	switch {
+	case strings.Contains(path, "/cups/backend/"):
+		return "backend " + base + ": sends print jobs to the printer device"
+	case strings.Contains(path, "/cups/filter/"):
+		return "filter " + base + ": converts print job data for the printer"
+	case strings.Contains(path, "/cups/notifier/"):
+		return "notifier " + base + ": delivers job event notifications"
+	case strings.Contains(path, "/cups/cgi-bin/"):
+		return "cgi " + base + ": serves the CUPS web interface"
+	}
+	return ""
+}
@@ -36,19 +36,20 @@ var knownSupervisors = map[string]string{
 	"launchd":      "launchd",
 	"god":          "god",
 	"forever":      "forever",
+	"nodemon":      "nodemon",
 	"nssm":         "nssm",
 }
 
 func detectSupervisor(ancestry []model.Process) *model.Source {
 	for _, p := range ancestry {
-		// Normalize: remove spaces, lowercase
-		pname := strings.ReplaceAll(strings.ToLower(p.Command), " ", "")
-		pcmd := strings.ReplaceAll(strings.ToLower(p.Cmdline), " ", "")
-		if strings.Contains(pname, "pm2") || strings.Contains(pcmd, "pm2") {
+		// Node process managers (pm2, forever, nodemon) carry app details
+		if name := nodeManager(p); name != "" {
 			return &model.Source{
 				Type:       model.SourceSupervisor,
-				Name:       "pm2",
+				Name:       name,
 				Confidence: 0.9,
+				Evidence:   []string{ancestorEvidence(p, "is the Node process manager "+name)},
+				Details:    nodeManagerDetails(name, p, ancestry[len(ancestry)-1]),
 			}
 		}
 		if label, ok := knownSupervisors[strings.ToLower(p.Command)]; ok {
@@ -56,6 +57,7 @@ func detectSupervisor(ancestry []model.Process) *model.Source {
 				Type:       model.SourceSupervisor,
 				Name:       label,
 				Confidence: 0.7,
+				Evidence:   []string{ancestorEvidence(p, "is the known supervisor "+label)},
 			}
 		}
 		// Also match on command line for supervisor keywords
@@ -65,6 +67,7 @@ func detectSupervisor(ancestry []model.Process) *model.Source {
 					Type:       model.SourceSupervisor,
 					Name:       label,
 					Confidence: 0.7,
+					Evidence:   []string{"command line of " + p.Command + " (pid " + itoa(p.PID) + ") mentions " + sup},
 				}
 			}
 		}
@@ -7,3 +7,32 @@ import "github.com/pranshuparmar/witr/pkg/model"
 func detectSystemd(_ []model.Process) *model.Source {
 	return nil
 }
+
+func systemdTimerSchedule(_ string) string {
+	return ""
+}
+
+func detectSystemdTimer(_ []model.Process) *model.Source {
+	return nil
+}
+
+func detectSystemdSocket(_ []model.Process) *model.Source {
+	return nil
+}
+
+func detectSystemdUser(_ []model.Process) *model.Source {
+	return nil
+}
+
+func detectUdev(_ []model.Process) *model.Source {
+	return nil
+}
+
+func detectNixOS(_ []model.Process) *model.Source {
+	return nil
+}
+
+// ServiceDetails returns nil; there are no systemd units on macOS
+func ServiceDetails(_ model.Source) *model.ServiceUnit {
+	return nil
+}
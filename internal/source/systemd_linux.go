@@ -2,17 +2,301 @@
 
 package source
 
-import "github.com/pranshuparmar/witr/pkg/model"
+import (
+	"os"
+	"os/exec"
+	osuser "os/user"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
 
 func detectSystemd(ancestry []model.Process) *model.Source {
 	for _, p := range ancestry {
 		if p.PID == 1 && p.Command == "systemd" {
-			return &model.Source{
+			src := &model.Source{
 				Type:       model.SourceSystemd,
 				Name:       "systemd",
 				Confidence: 0.8,
+				Evidence:   []string{"ancestry reaches systemd at pid 1"},
+			}
+			if unit := ancestry[len(ancestry)-1].Service; unit != "" {
+				src.Name = unit
+				src.Evidence = append(src.Evidence, "systemctl attributes the process to "+unit)
+				src.Details = map[string]string{
+					"manager": systemManager,
+					"hint":    systemdHint(unit, ""),
+				}
+			}
+			return src
+		}
+	}
+	return nil
+}
+
+// ServiceDetails returns the configuration and run state of the system
+// unit a systemd source names, or nil for other sources. Drop-ins are listed
+// with the settings they override, which usually explain unexpected flags.
+func ServiceDetails(src model.Source) *model.ServiceUnit {
+	if src.Type != model.SourceSystemd || src.Name == "" || src.Details["manager"] != systemManager {
+		return nil
+	}
+	props := systemctlShow(src.Name, "FragmentPath", "DropInPaths", "ExecStart", "Restart", "NRestarts",
+		"ActiveState", "SubState", "ActiveEnterTimestamp")
+	if props["FragmentPath"] == "" && props["ExecStart"] == "" {
+		return nil
+	}
+	svc := &model.ServiceUnit{
+		Unit:         src.Name,
+		ExecStart:    ExecStartArgv(props["ExecStart"]),
+		Restart:      props["Restart"],
+		ActiveState:  props["ActiveState"],
+		SubState:     props["SubState"],
+		FragmentPath: props["FragmentPath"],
+	}
+	svc.NRestarts, _ = strconv.Atoi(props["NRestarts"])
+	if since := props["ActiveEnterTimestamp"]; since != "n/a" {
+		svc.ActiveSince = since
+	}
+	for path := range strings.FieldsSeq(props["DropInPaths"]) {
+		dropIn := model.DropIn{Path: path}
+		if data, err := os.ReadFile(path); err == nil {
+			dropIn.Settings = dropInSettings(string(data))
+		}
+		svc.DropIns = append(svc.DropIns, dropIn)
+	}
+	return svc
+}
+
+// dropInSettings lists the settings a drop-in file assigns, in order of
+// first appearance
+func dropInSettings(content string) []string {
+	var keys []string
+	continued := false
+	for line := range strings.Lines(content) {
+		line = strings.TrimSpace(line)
+		wasContinued := continued
+		continued = strings.HasSuffix(line, "\\")
+		if wasContinued || line == "" || line[0] == '#' || line[0] == ';' || line[0] == '[' {
+			continue
+		}
+		if key, _, ok := strings.Cut(line, "="); ok {
+			if key = strings.TrimSpace(key); !slices.Contains(keys, key) {
+				keys = append(keys, key)
 			}
 		}
 	}
+	return keys
+}
+
+// systemManager describes the system-wide service manager in source details
+const systemManager = "system (systemd, pid 1)"
+
+// systemdHint suggests the systemctl commands for managing a unit. user is
+// empty for system units and names the owning user for systemd --user units.
+func systemdHint(unit, user string) string {
+	if user == "" {
+		return "Inspect with: systemctl status " + unit + "; stop with: sudo systemctl stop " + unit
+	}
+	ctl := "systemctl --user"
+	if self, err := osuser.Current(); err == nil && self.Username != user {
+		ctl = "sudo systemctl --user --machine=" + user + "@"
+	}
+	return "Inspect with: " + ctl + " status " + unit + "; stop with: " + ctl + " stop " + unit
+}
+
+// detectSystemdTimer reports the timer unit that activated the service a
+// process belongs to, along with its schedule and trigger times
+func detectSystemdTimer(ancestry []model.Process) *model.Source {
+	for i := len(ancestry) - 1; i >= 0; i-- {
+		service := ancestry[i].Service
+		if service == "" {
+			continue
+		}
+		timer := systemdTimer(service)
+		if timer == nil {
+			return nil
+		}
+		details := map[string]string{
+			"timer":   timer.Unit,
+			"manager": systemManager,
+			"hint":    systemdHint(timer.Unit, ""),
+		}
+		if timer.Calendar != "" {
+			details["schedule"] = timer.Calendar
+		}
+		if timer.Last != "" {
+			details["last"] = timer.Last
+		}
+		if timer.Next != "" {
+			details["next"] = timer.Next
+		}
+		return &model.Source{
+			Type:       model.SourceSystemd,
+			Name:       service,
+			Confidence: 0.9,
+			Evidence:   []string{service + " is triggered by " + timer.Unit},
+			Details:    details,
+		}
+	}
 	return nil
 }
+
+// detectSystemdSocket reports services systemd started on demand for a
+// .socket unit, which respawn on the next connection when killed
+func detectSystemdSocket(ancestry []model.Process) *model.Source {
+	for i := len(ancestry) - 1; i >= 0; i-- {
+		service := ancestry[i].Service
+		if service == "" {
+			continue
+		}
+		socket := triggeringSocket(systemctlShow(service, "TriggeredBy")["TriggeredBy"])
+		if socket == "" {
+			return nil
+		}
+		listen := socketListen(systemctlOutput(socket, "Listen"))
+		details := map[string]string{
+			"socket":  socket,
+			"manager": systemManager,
+			"respawn": socketRespawnNote(listen),
+			"hint":    "Stop both with: sudo systemctl stop " + socket + " " + service,
+		}
+		if len(listen) > 0 {
+			details["listen"] = strings.Join(listen, ", ")
+		}
+		if systemctlShow(socket, "Accept")["Accept"] == "yes" {
+			details["activation"] = "one service instance per connection (Accept=yes)"
+		} else {
+			details["activation"] = "started on the first connection"
+		}
+		return &model.Source{
+			Type:       model.SourceSystemd,
+			Name:       service,
+			Confidence: 0.9,
+			Evidence:   []string{service + " is triggered by " + socket},
+			Details:    details,
+		}
+	}
+	return nil
+}
+
+// triggeringSocket returns the first .socket unit in a TriggeredBy list
+func triggeringSocket(triggeredBy string) string {
+	for _, u := range strings.Fields(triggeredBy) {
+		if strings.HasSuffix(u, ".socket") {
+			return u
+		}
+	}
+	return ""
+}
+
+// socketListen parses the Listen= lines of systemctl show, one per address,
+// e.g. "Listen=/run/cups/cups.sock (Stream)"
+func socketListen(out string) []string {
+	var listen []string
+	for line := range strings.Lines(out) {
+		if val, ok := strings.CutPrefix(strings.TrimSpace(line), "Listen="); ok && val != "" {
+			listen = append(listen, val)
+		}
+	}
+	return listen
+}
+
+// socketRespawnNote warns that a socket-activated service comes back when
+// killed, naming the first listen address when known
+func socketRespawnNote(listen []string) string {
+	next := "the next connection"
+	if len(listen) > 0 {
+		addr, _, _ := strings.Cut(listen[0], " (")
+		next += " to " + addr
+	}
+	return "killing the service does not stop it; systemd starts it again on " + next
+}
+
+type timerInfo struct {
+	Unit     string
+	Calendar string
+	Last     string
+	Next     string
+}
+
+// systemdTimer returns the timer unit that triggers the given service, if any
+func systemdTimer(service string) *timerInfo {
+	triggeredBy := systemctlShow(service, "TriggeredBy")["TriggeredBy"]
+	unit := ""
+	for _, u := range strings.Fields(triggeredBy) {
+		if strings.HasSuffix(u, ".timer") {
+			unit = u
+			break
+		}
+	}
+	if unit == "" {
+		return nil
+	}
+
+	props := systemctlShow(unit, "TimersCalendar", "TimersMonotonic", "LastTriggerUSec", "NextElapseUSecRealtime")
+	info := &timerInfo{Unit: unit}
+
+	info.Calendar = timerSchedule(props["TimersCalendar"], props["TimersMonotonic"])
+	if last := props["LastTriggerUSec"]; last != "" && last != "n/a" && last != "0" {
+		info.Last = last
+	}
+	if next := props["NextElapseUSecRealtime"]; next != "" && next != "n/a" && next != "0" {
+		info.Next = next
+	}
+	return info
+}
+
+// timerSchedule extracts the schedule from the TimersCalendar and
+// TimersMonotonic properties of a timer, e.g. "*-*-* 02:00:00" from
+// "{ OnCalendar=*-*-* 02:00:00 ; next_elapse=... }". Monotonic timers keep
+// their setting's name ("OnUnitActiveSec=1h"), which says what the interval
+// counts from.
+func timerSchedule(calendar, monotonic string) string {
+	spec := func(val string) string {
+		spec, _, _ := strings.Cut(strings.Trim(val, "{} "), " ;")
+		return strings.TrimSpace(spec)
+	}
+	if s := spec(calendar); s != "" {
+		return strings.TrimPrefix(s, "OnCalendar=")
+	}
+	return spec(monotonic)
+}
+
+// systemdTimerSchedule returns the schedule of the timer that activates the
+// given service unit, if one exists
+func systemdTimerSchedule(service string) string {
+	timer := systemdTimer(service)
+	if timer == nil || timer.Calendar == "" {
+		return ""
+	}
+	return timer.Calendar + " (" + timer.Unit + ")"
+}
+
+// systemctlShow returns the requested properties of a unit
+func systemctlShow(unit string, props ...string) map[string]string {
+	values := make(map[string]string)
+	for line := range strings.Lines(systemctlOutput(unit, props...)) {
+		key, val, ok := strings.Cut(strings.TrimRight(line, "\n"), "=")
+		if ok {
+			values[key] = val
+		}
+	}
+	return values
+}
+
+// systemctlOutput returns the raw systemctl show output for the requested
+// properties, for properties that repeat (such as Listen)
+func systemctlOutput(unit string, props ...string) string {
+	args := []string{"show", unit}
+	for _, p := range props {
+		args = append(args, "-p", p)
+	}
+	out, err := exec.Command("systemctl", args...).Output()
+	if err != nil {
+		return ""
+	}
+	return string(out)
+}
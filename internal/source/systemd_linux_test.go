@@ -0,0 +1,60 @@
+//go:build linux
+
+package source
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSocketListen(t *testing.T) {
+	out := "Listen=/run/cups/cups.sock (Stream)\nListen=[::]:631 (Stream)\n"
+	want := []string{"/run/cups/cups.sock (Stream)", "[::]:631 (Stream)"}
+	if got := socketListen(out); !reflect.DeepEqual(got, want) {
+		t.Errorf("socketListen() = %q, want %q", got, want)
+	}
+	if got := triggeringSocket("cups.path cups.socket"); got != "cups.socket" {
+		t.Errorf("triggeringSocket() = %q, want cups.socket", got)
+	}
+}
+
+func TestDropInSettings(t *testing.T) {
+	content := `# Local tweaks
+[Service]
+ExecStart=
+ExecStart=/usr/sbin/nginx -g 'daemon off;' \
+  -c /etc/nginx/alt.conf
+Environment=MODE=dev
+Environment=DEBUG=1
+; LimitNOFILE=1
+[Unit]
+After=network-online.target
+`
+	want := []string{"ExecStart", "Environment", "After"}
+	if got := dropInSettings(content); !reflect.DeepEqual(got, want) {
+		t.Errorf("dropInSettings() = %q, want %q", got, want)
+	}
+}
+
+func TestTimerSchedule(t *testing.T) {
+	tests := []struct{ calendar, monotonic, want string }{
+		{"{ OnCalendar=*-*-* 02:00:00 ; next_elapse=Thu 2026-10-15 02:00:00 UTC }", "", "*-*-* 02:00:00"},
+		{"", "{ OnUnitActiveSec=1h ; next_elapse=2h 5min }", "OnUnitActiveSec=1h"},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		if got := timerSchedule(tt.calendar, tt.monotonic); got != tt.want {
+			t.Errorf("timerSchedule(%q, %q) = %q, want %q", tt.calendar, tt.monotonic, got, tt.want)
+		}
+	}
+}
+
+func TestUserUnitActivationUnnamedUID(t *testing.T) {
+	// Another uid's manager can only be reached by user name
+	if got := userUnitActivation("4000000000", "", "sync.service"); got != "" {
+		t.Errorf("userUnitActivation() for a uid without a name = %q, want empty", got)
+	}
+	if got := userUnitActivation("4000000000", "", "app-foo-1.scope"); got == "" {
+		t.Error("userUnitActivation() for a scope = empty, want the transient scope note")
+	}
+}
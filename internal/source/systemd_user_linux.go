@@ -0,0 +1,162 @@
+//go:build linux
+
+package source
+
+import (
+	"os"
+	"os/exec"
+	osuser "os/user"
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// detectSystemdUser reports units owned by a per-user systemd manager
+// (systemd --user), including how the unit was activated and whether it
+// outlives the user's login sessions
+func detectSystemdUser(ancestry []model.Process) *model.Source {
+	if len(ancestry) == 0 {
+		return nil
+	}
+	target := ancestry[len(ancestry)-1]
+
+	data, err := os.ReadFile("/proc/" + itoa(target.PID) + "/cgroup")
+	if err != nil {
+		return nil
+	}
+	uid, unit := userUnitFromCgroup(string(data))
+	if unit == "" {
+		return nil
+	}
+
+	// A uid without a passwd entry has no name to address its manager or
+	// linger file by
+	name := ""
+	if u, err := osuser.LookupId(uid); err == nil {
+		name = u.Username
+	}
+	self := uid == strconv.Itoa(os.Getuid())
+	user := name
+	if user == "" {
+		user = "uid " + uid
+	}
+	managerPID := 0
+	for _, p := range ancestry {
+		if p.Command == "systemd" && p.PID != 1 {
+			managerPID = p.PID
+		}
+	}
+
+	details := map[string]string{"unit": unit}
+	manager := "user (systemd --user for " + user
+	if managerPID > 0 {
+		manager += ", pid " + itoa(managerPID)
+	}
+	details["manager"] = manager + ")"
+	switch {
+	case name != "":
+		details["hint"] = systemdHint(unit, name)
+	case self:
+		details["hint"] = "Inspect with: systemctl --user status " + unit + "; stop with: systemctl --user stop " + unit
+	}
+
+	if activation := userUnitActivation(uid, name, unit); activation != "" {
+		details["activation"] = activation
+		if strings.HasPrefix(activation, "user socket ") {
+			details["respawn"] = socketRespawnNote(nil)
+		}
+	}
+	if name != "" {
+		if _, err := os.Stat("/var/lib/systemd/linger/" + name); err == nil {
+			details["logout"] = "keeps running (lingering enabled)"
+		} else {
+			details["logout"] = "stopped when the user's last session ends"
+		}
+	}
+
+	return &model.Source{
+		Type:       model.SourceSystemd,
+		Name:       unit,
+		Confidence: 0.9,
+		Evidence:   []string{"cgroup path places the process in " + unit + " under user@" + uid + ".service"},
+		Details:    details,
+	}
+}
+
+// userUnitFromCgroup extracts the owning uid and unit from a cgroup path like
+// 0::/user.slice/user-1000.slice/user@1000.service/app.slice/foo.service
+func userUnitFromCgroup(cgroup string) (uid, unit string) {
+	for line := range strings.Lines(cgroup) {
+		parts := strings.SplitN(strings.TrimSpace(line), ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		path := parts[2]
+		idx := strings.Index(path, "/user@")
+		if idx == -1 {
+			continue
+		}
+		rest := path[idx+len("/user@"):]
+		managerUnit, below, _ := strings.Cut(rest, "/")
+		uid = strings.TrimSuffix(managerUnit, ".service")
+
+		for _, comp := range strings.Split(below, "/") {
+			if strings.HasSuffix(comp, ".service") || strings.HasSuffix(comp, ".scope") {
+				unit = comp
+			}
+		}
+		if unit != "" {
+			return uid, unit
+		}
+	}
+	return "", ""
+}
+
+// userUnitActivation explains what started a user unit: a user timer, D-Bus
+// activation, the graphical session, or the default login target
+func userUnitActivation(uid, name, unit string) string {
+	if strings.HasSuffix(unit, ".scope") {
+		return "transient scope (launched by the desktop session or an application)"
+	}
+
+	// Another user's manager is reachable through --machine=<name>@ (needs
+	// root), so only by name
+	args := []string{"--user"}
+	if uid != strconv.Itoa(os.Getuid()) {
+		if name == "" {
+			return ""
+		}
+		args = append(args, "--machine="+name+"@")
+	}
+	args = append(args, "show", unit, "-p", "TriggeredBy", "-p", "BusName", "-p", "WantedBy", "-p", "PartOf")
+	out, err := exec.Command("systemctl", args...).Output()
+	if err != nil {
+		return ""
+	}
+	props := make(map[string]string)
+	for line := range strings.Lines(string(out)) {
+		if key, val, ok := strings.Cut(strings.TrimSpace(line), "="); ok {
+			props[key] = val
+		}
+	}
+
+	for _, t := range strings.Fields(props["TriggeredBy"]) {
+		if strings.HasSuffix(t, ".timer") {
+			return "user timer " + t
+		}
+		if strings.HasSuffix(t, ".socket") {
+			return "user socket " + t
+		}
+	}
+	if props["BusName"] != "" {
+		return "D-Bus activation (" + props["BusName"] + ")"
+	}
+	if strings.Contains(props["WantedBy"], "graphical-session.target") || strings.Contains(props["PartOf"], "graphical-session.target") {
+		return "graphical-session.target"
+	}
+	if strings.Contains(props["WantedBy"], "default.target") {
+		return "default.target (user login)"
+	}
+	return ""
+}
@@ -0,0 +1,256 @@
+//go:build linux
+
+package source
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// udev rule directories in priority order
+var udevRuleDirs = []string{"/etc/udev/rules.d", "/run/udev/rules.d", "/usr/lib/udev/rules.d", "/lib/udev/rules.d"}
+
+// Targets that udev pulls in when matching hardware appears
+var hardwareTargets = []string{"bluetooth.target", "sound.target", "printer.target", "smartcard.target"}
+
+// detectUdev reports processes started by udev rules, either directly through
+// RUN+= or as systemd services pulled in by a device (SYSTEMD_WANTS=)
+func detectUdev(ancestry []model.Process) *model.Source {
+	if len(ancestry) == 0 {
+		return nil
+	}
+	target := ancestry[len(ancestry)-1]
+
+	for _, p := range ancestry[:len(ancestry)-1] {
+		if p.Command != "systemd-udevd" && p.Command != "udevd" && p.Command != "(udev-worker)" {
+			continue
+		}
+		// RUN+= programs inherit the event properties as environment
+		details := make(map[string]string)
+		if dev := udevDevice(target.Env); dev != "" {
+			details["device"] = dev
+		}
+		if action := envValue(target.Env, "ACTION"); action != "" {
+			details["event"] = action
+		}
+		name := filepath.Base(target.Exe)
+		if target.Exe == "" {
+			name = target.Command
+		}
+		if rule := findUdevRule(udevRunsProgram(name)); rule != "" {
+			details["rule"] = rule
+		}
+		return &model.Source{
+			Type:       model.SourceUdev,
+			Name:       "udev",
+			Confidence: 0.8,
+			Evidence:   []string{ancestorEvidence(p, "is the udev daemon or a worker")},
+			Details:    details,
+		}
+	}
+
+	if target.Service == "" {
+		return nil
+	}
+	props := systemctlShow(target.Service, "WantedBy", "RequiredBy", "BindsTo")
+	var triggers []string
+	for _, key := range []string{"WantedBy", "RequiredBy", "BindsTo"} {
+		for _, unit := range strings.Fields(props[key]) {
+			if strings.HasSuffix(unit, ".device") {
+				triggers = append(triggers, unit)
+			}
+			for _, t := range hardwareTargets {
+				if unit == t {
+					triggers = append(triggers, unit)
+				}
+			}
+		}
+	}
+	if len(triggers) == 0 {
+		return nil
+	}
+	details := map[string]string{"device": strings.Join(triggers, ", ")}
+	if rule := findUdevRule(udevWantsUnit(target.Service)); rule != "" {
+		details["rule"] = rule
+	}
+	return &model.Source{
+		Type:       model.SourceUdev,
+		Name:       target.Service,
+		Confidence: 0.7,
+		Evidence:   []string{target.Service + " is wanted by " + strings.Join(triggers, ", ")},
+		Details:    details,
+	}
+}
+
+// udevDevice describes the device from udev event properties
+func udevDevice(env []string) string {
+	dev := envValue(env, "DEVNAME")
+	if dev == "" {
+		dev = envValue(env, "DEVPATH")
+	}
+	if dev == "" {
+		return ""
+	}
+	if vendor := envValue(env, "ID_VENDOR_FROM_DATABASE"); vendor != "" {
+		dev += " (" + vendor
+		if product := envValue(env, "ID_MODEL_FROM_DATABASE"); product != "" {
+			dev += " " + product
+		}
+		dev += ")"
+	} else if subsystem := envValue(env, "SUBSYSTEM"); subsystem != "" {
+		dev += " (" + subsystem + ")"
+	}
+	return dev
+}
+
+// udevAssignment is one KEY{attr}<op>"value" pair of a udev rule
+type udevAssignment struct {
+	Key, Attr, Op, Value string
+}
+
+// assigns reports whether a sets or adds to its key, rather than matching
+// or removing a value
+func (a udevAssignment) assigns() bool {
+	return a.Op == "=" || a.Op == "+=" || a.Op == ":="
+}
+
+// findUdevRule returns file:line of the first rule with an assignment
+// accepted by match. A rule file in /etc overrides one of the same name in
+// /usr/lib.
+func findUdevRule(match func(udevAssignment) bool) string {
+	seen := make(map[string]bool)
+	for _, dir := range udevRuleDirs {
+		files, _ := filepath.Glob(filepath.Join(dir, "*.rules"))
+		for _, file := range files {
+			base := filepath.Base(file)
+			if seen[base] {
+				continue
+			}
+			seen[base] = true
+
+			data, err := os.ReadFile(file)
+			if err != nil {
+				continue
+			}
+			if line := matchUdevRules(string(data), match); line > 0 {
+				return file + ":" + strconv.Itoa(line)
+			}
+		}
+	}
+	return ""
+}
+
+// matchUdevRules returns the line number of the first rule in content with
+// an assignment accepted by match, or 0. Rules continued with a trailing
+// backslash are reported at their first line.
+func matchUdevRules(content string, match func(udevAssignment) bool) int {
+	lines := strings.Split(content, "\n")
+	for i := 0; i < len(lines); i++ {
+		start := i
+		rule := strings.TrimSpace(lines[i])
+		for strings.HasSuffix(rule, "\\") && i+1 < len(lines) {
+			i++
+			rule = strings.TrimSuffix(rule, "\\") + strings.TrimSpace(lines[i])
+		}
+		if rule == "" || strings.HasPrefix(rule, "#") {
+			continue
+		}
+		for _, a := range parseUdevRule(rule) {
+			if match(a) {
+				return start + 1
+			}
+		}
+	}
+	return 0
+}
+
+// parseUdevRule splits a rule into its comma-separated assignments, stopping
+// at the first malformed one
+func parseUdevRule(rule string) []udevAssignment {
+	var out []udevAssignment
+	rest := rule
+	for {
+		rest = strings.TrimLeft(rest, ", \t")
+		if rest == "" {
+			return out
+		}
+		end := strings.IndexAny(rest, "{=!+-:")
+		if end <= 0 {
+			return out
+		}
+		a := udevAssignment{Key: strings.TrimSpace(rest[:end])}
+		rest = rest[end:]
+		if strings.HasPrefix(rest, "{") {
+			brace := strings.IndexByte(rest, '}')
+			if brace == -1 {
+				return out
+			}
+			a.Attr, rest = rest[1:brace], rest[brace+1:]
+		}
+		for _, op := range []string{"==", "!=", "+=", "-=", ":=", "="} {
+			if strings.HasPrefix(rest, op) {
+				a.Op, rest = op, rest[len(op):]
+				break
+			}
+		}
+		if a.Op == "" || !strings.HasPrefix(rest, `"`) {
+			return out
+		}
+		// The value runs to the next unescaped quote
+		var value strings.Builder
+		j := 1
+		for ; j < len(rest) && rest[j] != '"'; j++ {
+			if rest[j] == '\\' && j+1 < len(rest) && rest[j+1] == '"' {
+				j++
+			}
+			value.WriteByte(rest[j])
+		}
+		if j == len(rest) {
+			return out
+		}
+		a.Value, rest = value.String(), rest[j+1:]
+		out = append(out, a)
+	}
+}
+
+// udevRunsProgram matches RUN and PROGRAM assignments whose executable is
+// name. RUN{builtin} runs code inside udevd, so it never matches.
+func udevRunsProgram(name string) func(udevAssignment) bool {
+	return func(a udevAssignment) bool {
+		switch {
+		case a.Key == "RUN" && a.Attr != "builtin" && a.assigns():
+		case a.Key == "PROGRAM":
+		default:
+			return false
+		}
+		fields := strings.Fields(a.Value)
+		return len(fields) > 0 && filepath.Base(fields[0]) == name
+	}
+}
+
+// udevWantsUnit matches ENV{SYSTEMD_WANTS} assignments that list unit, or
+// the template it is an instance of
+func udevWantsUnit(unit string) func(udevAssignment) bool {
+	template := ""
+	if prefix, _, ok := strings.Cut(unit, "@"); ok {
+		template = prefix + "@"
+	}
+	return func(a udevAssignment) bool {
+		if a.Key != "ENV" || a.Attr != "SYSTEMD_WANTS" || !a.assigns() {
+			return false
+		}
+		for _, want := range strings.Fields(a.Value) {
+			if !strings.Contains(want, ".") {
+				want += ".service"
+			}
+			if want == unit || template != "" && strings.HasPrefix(want, template) {
+				return true
+			}
+		}
+		return false
+	}
+}
@@ -0,0 +1,33 @@
+//go:build linux
+
+package source
+
+import "testing"
+
+func TestMatchUdevRules(t *testing.T) {
+	rules := `# RUN+="/usr/bin/backup" in a comment
+ACTION=="add", SUBSYSTEM=="block", ENV{ID_FS_LABEL}=="backup-disk", RUN+="/usr/local/bin/notify backup"
+ACTION=="add", \
+  KERNEL=="sd*", RUN+="/usr/local/bin/backup --device %k"
+SUBSYSTEM=="usb", RUN{builtin}+="kmod load backup"
+SUBSYSTEM=="net", TAG+="systemd", ENV{SYSTEMD_WANTS}+="ifup@%k.service wpa"
+`
+	tests := []struct {
+		name  string
+		match func(udevAssignment) bool
+		want  int
+	}{
+		// "backup" in another rule's value is not its executable
+		{"RUN backup", udevRunsProgram("backup"), 3},
+		{"RUN notify", udevRunsProgram("notify"), 2},
+		{"RUN kmod", udevRunsProgram("kmod"), 0},
+		{"wants template", udevWantsUnit("ifup@eth0.service"), 6},
+		{"wants bare name", udevWantsUnit("wpa.service"), 6},
+		{"wants other", udevWantsUnit("ifdown@eth0.service"), 0},
+	}
+	for _, tt := range tests {
+		if got := matchUdevRules(rules, tt.match); got != tt.want {
+			t.Errorf("%s: matchUdevRules() = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package source
+
+import (
+	"os"
+	"strings"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// wslEnvironment returns "WSL1" or "WSL2" when running under the Windows
+// Subsystem for Linux, or "" elsewhere
+func wslEnvironment() string {
+	data, err := os.ReadFile("/proc/sys/kernel/osrelease")
+	if err != nil {
+		return ""
+	}
+	release := strings.ToLower(string(data))
+	if !strings.Contains(release, "microsoft") {
+		return ""
+	}
+	// WSL2 kernels are "...-microsoft-standard-WSL2"; WSL1 reports "...-Microsoft"
+	if strings.Contains(release, "wsl2") || strings.Contains(release, "microsoft-standard") {
+		return "WSL2"
+	}
+	return "WSL1"
+}
+
+// wslDistro returns the WSL distribution name exported to processes
+func wslDistro(ancestry []model.Process) string {
+	for i := len(ancestry) - 1; i >= 0; i-- {
+		if name := envValue(ancestry[i].Env, "WSL_DISTRO_NAME"); name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// detectWSL reports processes started from Windows through WSL interop
+// (wsl.exe, Windows Terminal, an IDE), which reach Linux through the WSL
+// init relay rather than a login, SSH session or service manager
+func detectWSL(ancestry []model.Process) *model.Source {
+	env := wslEnvironment()
+	if env == "" {
+		return nil
+	}
+	for i := len(ancestry) - 1; i >= 0; i-- {
+		p := ancestry[i]
+		if p.PID == 1 || (p.Command != "init" && !strings.HasPrefix(p.Command, "init-systemd")) {
+			continue
+		}
+
+		details := map[string]string{
+			"via":   env + " interop (wsl.exe or a Windows application)",
+			"relay": p.Command + " (pid " + itoa(p.PID) + ")",
+		}
+		if distro := wslDistro(ancestry); distro != "" {
+			details["distro"] = distro
+		}
+		return &model.Source{
+			Type:       model.SourceWSL,
+			Name:       "windows",
+			Confidence: 0.8,
+			Evidence:   []string{"kernel release identifies " + env, ancestorEvidence(p, "is the WSL init relay")},
+			Details:    details,
+		}
+	}
+	return nil
+}
+
+// wslGuest describes the WSL environment for sources that did not come from
+// Windows, e.g. "WSL2 (Ubuntu)"
+func wslGuest(ancestry []model.Process) string {
+	env := wslEnvironment()
+	if env == "" {
+		return ""
+	}
+	if distro := wslDistro(ancestry); distro != "" {
+		return env + " (" + distro + ")"
+	}
+	return env
+}
@@ -0,0 +1,127 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Correlation keys for grouping processes across hosts
+const (
+	ByExe     = "exe"
+	ByCmdline = "cmdline"
+)
+
+// Group is one binary or command line and where it runs
+type Group struct {
+	Key string
+	// Hosts and Users the process was seen on, sorted
+	Hosts []string
+	Users []string
+	// Processes counts matching processes across all hosts
+	Processes int
+}
+
+// LatestPerHost loads the most recent full-host snapshot of every host in
+// the store. A snapshot that cannot be read gives way to that host's
+// previous one, as with FindOrigin.
+func LatestPerHost(s Store) ([]*Snapshot, error) {
+	ids, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	byHost := make(map[string][]string)
+	for _, id := range ids {
+		if _, kind, host, err := parseID(id); err == nil && kind == KindHost {
+			byHost[host] = append(byHost[host], id)
+		}
+	}
+	hosts := make([]string, 0, len(byHost))
+	for host := range byHost {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	var snaps []*Snapshot
+	for _, host := range hosts {
+		// IDs sort oldest first
+		ids := byHost[host]
+		for i := len(ids) - 1; i >= 0; i-- {
+			if snap, err := s.Load(ids[i]); err == nil {
+				snaps = append(snaps, snap)
+				break
+			}
+		}
+	}
+	return snaps, nil
+}
+
+// Correlate groups the processes in snaps by executable path or command
+// line, most widespread first, and returns the number of distinct hosts
+func Correlate(snaps []*Snapshot, by string) ([]Group, int, error) {
+	if by != ByExe && by != ByCmdline {
+		return nil, 0, fmt.Errorf("unknown correlation key %q, expected %s or %s", by, ByExe, ByCmdline)
+	}
+	type acc struct {
+		hosts, users map[string]bool
+		processes    int
+	}
+	groups := make(map[string]*acc)
+	allHosts := make(map[string]bool)
+	for _, snap := range snaps {
+		allHosts[snap.Host] = true
+		for _, r := range snap.Results {
+			key := r.Process.Exe
+			if by == ByCmdline || key == "" {
+				key = r.Process.Cmdline
+			}
+			if key == "" {
+				key = r.Process.Command
+			}
+			g := groups[key]
+			if g == nil {
+				g = &acc{hosts: make(map[string]bool), users: make(map[string]bool)}
+				groups[key] = g
+			}
+			g.hosts[snap.Host] = true
+			if r.Process.User != "" {
+				g.users[r.Process.User] = true
+			}
+			g.processes++
+		}
+	}
+
+	var out []Group
+	for key, g := range groups {
+		out = append(out, Group{Key: key, Hosts: sortedKeys(g.hosts), Users: sortedKeys(g.users), Processes: g.processes})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if len(out[i].Hosts) != len(out[j].Hosts) {
+			return len(out[i].Hosts) > len(out[j].Hosts)
+		}
+		return out[i].Key < out[j].Key
+	})
+	return out, len(allHosts), nil
+}
+
+// Outliers returns the groups seen on at most maxHosts hosts, rarest first.
+// With too few hosts every group would qualify, so none are returned.
+func Outliers(groups []Group, totalHosts, maxHosts int) []Group {
+	if totalHosts <= maxHosts {
+		return nil
+	}
+	var out []Group
+	for i := len(groups) - 1; i >= 0; i-- {
+		if len(groups[i].Hosts) <= maxHosts {
+			out = append(out, groups[i])
+		}
+	}
+	return out
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
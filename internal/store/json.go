@@ -0,0 +1,320 @@
+package store
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// pidIndexDir names the per-boot directory of PID index files
+const pidIndexDir = "pids"
+
+// JSONStore keeps one JSON file per snapshot in a directory, grouped into
+// a subdirectory per boot ID. Each boot's pids/<pid> file indexes the
+// daemon snapshots recording that PID, one "<start unix nanos> <id>" line
+// per process.
+type JSONStore struct {
+	dir string
+}
+
+// OpenJSON opens (creating if needed) a JSON-file store in dir
+func OpenJSON(dir string) (*JSONStore, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("create store directory: %w", err)
+	}
+	return &JSONStore{dir: dir}, nil
+}
+
+func (s *JSONStore) path(bootID, id string) string {
+	return filepath.Join(s.dir, bootID, id+".json")
+}
+
+// find locates a snapshot file by ID in any boot directory
+func (s *JSONStore) find(id string) (string, error) {
+	path := s.path("", id)
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+	matches, _ := filepath.Glob(filepath.Join(s.dir, "*", id+".json"))
+	if len(matches) == 0 {
+		return "", fmt.Errorf("snapshot %s: %w", id, os.ErrNotExist)
+	}
+	return matches[0], nil
+}
+
+// listDir returns snapshot IDs stored directly in dir
+func listDir(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || strings.HasPrefix(name, ".") || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		ids = append(ids, strings.TrimSuffix(name, ".json"))
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+func (s *JSONStore) Save(snap *Snapshot) error {
+	prepare(snap)
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Join(s.dir, snap.BootID)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return err
+	}
+
+	// Write to a temporary file and rename so readers never see partial files
+	tmp, err := os.CreateTemp(dir, ".snapshot-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), s.path(snap.BootID, snap.ID)); err != nil {
+		return err
+	}
+	if snap.Kind != KindOrigins || snap.BootID == "" {
+		return nil
+	}
+	return s.index(snap)
+}
+
+// index records the processes of a daemon snapshot in the boot's PID index
+func (s *JSONStore) index(snap *Snapshot) error {
+	dir := filepath.Join(s.dir, snap.BootID, pidIndexDir)
+	for _, r := range snap.Results {
+		if r.Process.StartedAt.IsZero() {
+			continue
+		}
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			return err
+		}
+		f, err := os.OpenFile(filepath.Join(dir, strconv.Itoa(r.Process.PID)), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o640)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(f, "%d %s\n", r.Process.StartedAt.UnixNano(), snap.ID)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *JSONStore) List() ([]string, error) {
+	ids, err := listDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	boots, err := s.Boots()
+	if err != nil {
+		return nil, err
+	}
+	for _, boot := range boots {
+		bootIDs, err := listDir(filepath.Join(s.dir, boot))
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, bootIDs...)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+func (s *JSONStore) ListBoot(bootID string) ([]string, error) {
+	if bootID == "" {
+		return nil, fmt.Errorf("empty boot ID")
+	}
+	ids, err := listDir(filepath.Join(s.dir, bootID))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return ids, err
+}
+
+func (s *JSONStore) Boots() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Order boots by their earliest snapshot
+	first := make(map[string]string)
+	var boots []string
+	for _, e := range entries {
+		if !e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		ids, err := listDir(filepath.Join(s.dir, e.Name()))
+		if err != nil || len(ids) == 0 {
+			continue
+		}
+		first[e.Name()] = ids[0]
+		boots = append(boots, e.Name())
+	}
+	sort.Slice(boots, func(i, j int) bool { return first[boots[i]] < first[boots[j]] })
+	return boots, nil
+}
+
+func (s *JSONStore) Origins(bootID string, pid int, started time.Time) ([]string, error) {
+	if bootID == "" {
+		return nil, fmt.Errorf("empty boot ID")
+	}
+	f, err := os.Open(filepath.Join(s.dir, bootID, pidIndexDir, strconv.Itoa(pid)))
+	if os.IsNotExist(err) {
+		// Boots recorded before the index existed have no index directory
+		if _, err := os.Stat(filepath.Join(s.dir, bootID, pidIndexDir)); os.IsNotExist(err) {
+			return s.ListBoot(bootID)
+		}
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var ids []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		ns, id, ok := strings.Cut(scanner.Text(), " ")
+		n, err := strconv.ParseInt(ns, 10, 64)
+		if !ok || err != nil {
+			continue
+		}
+		if d := time.Unix(0, n).Sub(started); d < startTolerance && d > -startTolerance {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids, scanner.Err()
+}
+
+func (s *JSONStore) Load(id string) (*Snapshot, error) {
+	path, err := s.find(id)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot %s: %w", id, err)
+	}
+	var snap Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("snapshot %s: %w", id, err)
+	}
+	return &snap, nil
+}
+
+func (s *JSONStore) Prune(r Retention) error {
+	ids, err := s.List()
+	if err != nil {
+		return err
+	}
+
+	type entry struct {
+		path string
+		size int64
+	}
+	var kept []entry
+	var total int64
+	for _, id := range ids {
+		path, err := s.find(id)
+		if err != nil {
+			continue
+		}
+		fi, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		takenAt, _, _, err := parseID(id)
+		if err != nil {
+			takenAt = fi.ModTime()
+		}
+		if r.MaxAge > 0 && time.Since(takenAt) > r.MaxAge {
+			if err := os.Remove(path); err != nil {
+				return err
+			}
+			continue
+		}
+		kept = append(kept, entry{path, fi.Size()})
+		total += fi.Size()
+	}
+
+	// IDs sort oldest first, so drop from the front until under the limit
+	for r.MaxBytes > 0 && total > r.MaxBytes && len(kept) > 0 {
+		if err := os.Remove(kept[0].path); err != nil {
+			return err
+		}
+		total -= kept[0].size
+		kept = kept[1:]
+	}
+	if len(kept) == len(ids) {
+		return nil
+	}
+	return s.pruneIndex()
+}
+
+// pruneIndex drops PID index lines whose snapshot is gone
+func (s *JSONStore) pruneIndex() error {
+	ids, err := s.List()
+	if err != nil {
+		return err
+	}
+	live := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		live[id] = true
+	}
+	files, _ := filepath.Glob(filepath.Join(s.dir, "*", pidIndexDir, "*"))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		var keep strings.Builder
+		for line := range strings.Lines(string(data)) {
+			if _, id, ok := strings.Cut(strings.TrimSpace(line), " "); ok && live[id] {
+				keep.WriteString(line)
+			}
+		}
+		switch {
+		case keep.Len() == len(data):
+			continue
+		case keep.Len() == 0:
+			err = os.Remove(file)
+		default:
+			err = os.WriteFile(file, []byte(keep.String()), 0o640)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *JSONStore) Close() error {
+	return nil
+}
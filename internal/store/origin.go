@@ -0,0 +1,46 @@
+package store
+
+import (
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// startTolerance absorbs clock-tick rounding between start times read at
+// record and at query time
+const startTolerance = time.Second
+
+// FindOrigin returns the result recorded for a process when it started,
+// loading only the snapshots the store indexes for it, newest first. A PID
+// is only matched together with its start time, so a reused PID is not
+// misattributed.
+func FindOrigin(s Store, bootID string, pid int, started time.Time) (*model.Result, time.Time, error) {
+	ids, err := s.Origins(bootID, pid, started)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	for i := len(ids) - 1; i >= 0; i-- {
+		snap, err := s.Load(ids[i])
+		if err != nil {
+			continue
+		}
+		if r := findResult(snap, pid, started); r != nil {
+			return r, snap.TakenAt, nil
+		}
+	}
+	return nil, time.Time{}, nil
+}
+
+// findResult returns the result in a snapshot describing pid started at started
+func findResult(snap *Snapshot, pid int, started time.Time) *model.Result {
+	for i := range snap.Results {
+		p := snap.Results[i].Process
+		if p.PID != pid || p.StartedAt.IsZero() {
+			continue
+		}
+		if d := p.StartedAt.Sub(started); d < startTolerance && d > -startTolerance {
+			return &snap.Results[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SQLiteStore keeps snapshots in a SQLite database. It drives the sqlite3
+// command-line tool, which keeps witr a single static binary without cgo.
+type SQLiteStore struct {
+	path string
+}
+
+const sqliteSchema = `CREATE TABLE IF NOT EXISTS snapshots (
+	id TEXT PRIMARY KEY,
+	taken_at INTEGER NOT NULL,
+	boot_id TEXT NOT NULL DEFAULT '',
+	data TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS snapshots_boot ON snapshots (boot_id, id);
+CREATE TABLE IF NOT EXISTS origins (
+	boot_id TEXT NOT NULL,
+	pid INTEGER NOT NULL,
+	started_at INTEGER NOT NULL,
+	snapshot_id TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS origins_pid ON origins (boot_id, pid);`
+
+// OpenSQLite opens (creating if needed) a SQLite store at path
+func OpenSQLite(path string) (*SQLiteStore, error) {
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		return nil, fmt.Errorf("sqlite store requires the sqlite3 command: %w", err)
+	}
+	s := &SQLiteStore{path: path}
+	if _, err := s.exec(sqliteSchema); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// exec runs SQL through sqlite3 and returns JSON-mode output
+func (s *SQLiteStore) exec(sql string) ([]byte, error) {
+	cmd := exec.Command("sqlite3", "-json", s.path)
+	cmd.Stdin = strings.NewReader(sql)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("sqlite3: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// quote returns a SQL string literal
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func (s *SQLiteStore) Save(snap *Snapshot) error {
+	prepare(snap)
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return err
+	}
+	var sql strings.Builder
+	sql.WriteString("BEGIN;\n")
+	fmt.Fprintf(&sql, "INSERT OR REPLACE INTO snapshots (id, taken_at, boot_id, data) VALUES (%s, %d, %s, %s);\n",
+		quote(snap.ID), snap.TakenAt.Unix(), quote(snap.BootID), quote(string(data)))
+	// Index the processes of daemon snapshots for Origins
+	if snap.Kind == KindOrigins && snap.BootID != "" {
+		for _, r := range snap.Results {
+			if !r.Process.StartedAt.IsZero() {
+				fmt.Fprintf(&sql, "INSERT INTO origins (boot_id, pid, started_at, snapshot_id) VALUES (%s, %d, %d, %s);\n",
+					quote(snap.BootID), r.Process.PID, r.Process.StartedAt.UnixNano(), quote(snap.ID))
+			}
+		}
+	}
+	sql.WriteString("COMMIT;")
+	_, err = s.exec(sql.String())
+	return err
+}
+
+func (s *SQLiteStore) List() ([]string, error) {
+	return s.column("SELECT id FROM snapshots ORDER BY id;")
+}
+
+func (s *SQLiteStore) ListBoot(bootID string) ([]string, error) {
+	if bootID == "" {
+		return nil, fmt.Errorf("empty boot ID")
+	}
+	return s.column("SELECT id FROM snapshots WHERE boot_id = " + quote(bootID) + " ORDER BY id;")
+}
+
+func (s *SQLiteStore) Boots() ([]string, error) {
+	return s.column("SELECT boot_id AS id FROM snapshots WHERE boot_id != '' GROUP BY boot_id ORDER BY MIN(id);")
+}
+
+func (s *SQLiteStore) Origins(bootID string, pid int, started time.Time) ([]string, error) {
+	if bootID == "" {
+		return nil, fmt.Errorf("empty boot ID")
+	}
+	indexed, err := s.column("SELECT boot_id AS id FROM origins WHERE boot_id = " + quote(bootID) + " LIMIT 1;")
+	if err != nil {
+		return nil, err
+	}
+	// Boots recorded before the index existed have no rows
+	if len(indexed) == 0 {
+		return s.ListBoot(bootID)
+	}
+	return s.column(fmt.Sprintf("SELECT snapshot_id AS id FROM origins WHERE boot_id = %s AND pid = %d AND started_at BETWEEN %d AND %d ORDER BY snapshot_id;",
+		quote(bootID), pid, started.Add(-startTolerance).UnixNano(), started.Add(startTolerance).UnixNano()))
+}
+
+// column runs a query selecting a single "id" column
+func (s *SQLiteStore) column(sql string) ([]string, error) {
+	out, err := s.exec(sql)
+	if err != nil {
+		return nil, err
+	}
+	var rows []struct {
+		ID string `json:"id"`
+	}
+	if len(bytes.TrimSpace(out)) > 0 {
+		if err := json.Unmarshal(out, &rows); err != nil {
+			return nil, err
+		}
+	}
+	ids := make([]string, 0, len(rows))
+	for _, r := range rows {
+		ids = append(ids, r.ID)
+	}
+	return ids, nil
+}
+
+func (s *SQLiteStore) Load(id string) (*Snapshot, error) {
+	out, err := s.exec("SELECT data FROM snapshots WHERE id = " + quote(id) + ";")
+	if err != nil {
+		return nil, err
+	}
+	var rows []struct {
+		Data string `json:"data"`
+	}
+	if len(bytes.TrimSpace(out)) > 0 {
+		if err := json.Unmarshal(out, &rows); err != nil {
+			return nil, err
+		}
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("snapshot %s not found", id)
+	}
+	var snap Snapshot
+	if err := json.Unmarshal([]byte(rows[0].Data), &snap); err != nil {
+		return nil, fmt.Errorf("snapshot %s: %w", id, err)
+	}
+	return &snap, nil
+}
+
+func (s *SQLiteStore) Prune(r Retention) error {
+	var sql strings.Builder
+	if r.MaxAge > 0 {
+		cutoff := time.Now().Add(-r.MaxAge).Unix()
+		sql.WriteString("DELETE FROM snapshots WHERE taken_at < " + strconv.FormatInt(cutoff, 10) + ";\n")
+	}
+	if r.MaxBytes > 0 {
+		// Keep the newest snapshots whose cumulative size fits the limit
+		sql.WriteString(fmt.Sprintf(`DELETE FROM snapshots WHERE id IN (
+	SELECT id FROM (
+		SELECT id, SUM(LENGTH(data)) OVER (ORDER BY id DESC) AS total FROM snapshots
+	) WHERE total > %d
+);
+`, r.MaxBytes))
+	}
+	if sql.Len() == 0 {
+		return nil
+	}
+	sql.WriteString("DELETE FROM origins WHERE snapshot_id NOT IN (SELECT id FROM snapshots);\nVACUUM;")
+	_, err := s.exec(sql.String())
+	return err
+}
+
+func (s *SQLiteStore) Close() error {
+	return nil
+}
@@ -0,0 +1,141 @@
+// Package store persists snapshots of witr results for history, diff and
+// baseline features. Backends are selected with a "<kind>:<path>" URI.
+package store
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// Snapshot is a set of results captured at one point in time
+type Snapshot struct {
+	ID      string
+	TakenAt time.Time
+	Host    string
+	// Kind is KindHost or KindOrigins; an empty kind saves as KindOrigins
+	Kind string `json:",omitempty"`
+	// BootID identifies the boot the snapshot was taken in
+	BootID  string `json:",omitempty"`
+	Results []model.Result
+}
+
+// Snapshot kinds
+const (
+	// KindHost snapshots explain every process on the host (witr dump --store)
+	KindHost = "host"
+	// KindOrigins snapshots record only the processes started since the
+	// previous one (witr daemon)
+	KindOrigins = "origins"
+)
+
+// Retention bounds how much history a store keeps. Zero values disable a limit.
+type Retention struct {
+	// MaxAge removes snapshots taken longer ago than this
+	MaxAge time.Duration
+	// MaxBytes removes the oldest snapshots until the store fits this size
+	MaxBytes int64
+}
+
+// Store is a snapshot persistence backend
+type Store interface {
+	// Save stores a snapshot, assigning an ID if it has none
+	Save(s *Snapshot) error
+	// List returns snapshot IDs, oldest first
+	List() ([]string, error)
+	// ListBoot returns IDs of snapshots taken during one boot, oldest first
+	ListBoot(bootID string) ([]string, error)
+	// Boots returns the recorded boot IDs, oldest first
+	Boots() ([]string, error)
+	// Origins returns IDs of the daemon snapshots of one boot that record
+	// pid started at started, oldest first. Boots recorded before origins
+	// were indexed return all their snapshots.
+	Origins(bootID string, pid int, started time.Time) ([]string, error)
+	// Load returns the snapshot with the given ID
+	Load(id string) (*Snapshot, error)
+	// Prune removes snapshots outside the retention policy
+	Prune(r Retention) error
+	Close() error
+}
+
+// Open opens a store from a URI: "json:<directory>" or "sqlite:<file>"
+func Open(uri string) (Store, error) {
+	kind, path, ok := strings.Cut(uri, ":")
+	if !ok || path == "" {
+		return nil, fmt.Errorf("invalid store %q, expected json:<dir> or sqlite:<file>", uri)
+	}
+	switch kind {
+	case "json":
+		return OpenJSON(path)
+	case "sqlite":
+		return OpenSQLite(path)
+	default:
+		return nil, fmt.Errorf("unknown store type %q", kind)
+	}
+}
+
+// idLayout formats snapshot timestamps into IDs that sort chronologically
+const idLayout = "20060102T150405.000000000Z"
+
+// newID derives a sortable snapshot ID from its timestamp, kind and host, so
+// snapshots can be told apart without loading them
+func newID(snap *Snapshot) string {
+	host := strings.NewReplacer("/", "-", `\`, "-").Replace(snap.Host)
+	return snap.TakenAt.UTC().Format(idLayout) + "_" + snap.Kind + "_" + host
+}
+
+// parseID splits an ID made by newID. IDs saved before kinds were
+// recorded hold only the timestamp and belong to daemon snapshots.
+func parseID(id string) (takenAt time.Time, kind, host string, err error) {
+	ts, rest, ok := strings.Cut(id, "_")
+	takenAt, err = time.Parse(idLayout, ts)
+	if err != nil || !ok {
+		return takenAt, KindOrigins, "", err
+	}
+	kind, host, _ = strings.Cut(rest, "_")
+	return takenAt, kind, host, nil
+}
+
+// prepare fills in the defaults of a snapshot about to be saved
+func prepare(snap *Snapshot) {
+	if snap.TakenAt.IsZero() {
+		snap.TakenAt = time.Now()
+	}
+	if snap.Kind == "" {
+		snap.Kind = KindOrigins
+	}
+	if snap.ID == "" {
+		snap.ID = newID(snap)
+	}
+}
+
+// PreviousBoot returns the most recent recorded boot other than current
+func PreviousBoot(s Store, current string) (string, error) {
+	boots, err := s.Boots()
+	if err != nil {
+		return "", err
+	}
+	for i := len(boots) - 1; i >= 0; i-- {
+		if boots[i] != current {
+			return boots[i], nil
+		}
+	}
+	return "", fmt.Errorf("no snapshots from a previous boot")
+}
+
+// MarkPreviousBoot flags results that were captured in a boot other than
+// current, so renderers can tell the user the process may be long gone
+func MarkPreviousBoot(snap *Snapshot, current string) {
+	if current == "" {
+		return
+	}
+	for i := range snap.Results {
+		r := &snap.Results[i]
+		if r.BootID == "" {
+			r.BootID = snap.BootID
+		}
+		r.PreviousBoot = r.BootID != "" && r.BootID != current
+	}
+}
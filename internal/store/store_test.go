@@ -0,0 +1,195 @@
+package store
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+func testStore(t *testing.T, s Store) {
+	t.Helper()
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		snap := &Snapshot{
+			TakenAt: base.Add(time.Duration(i) * time.Hour),
+			Host:    "web-1",
+			BootID:  []string{"boot-a", "boot-a", "boot-b"}[i],
+			Results: []model.Result{{ResolvedTarget: "nginx", Process: model.Process{PID: 100 + i}}},
+		}
+		if err := s.Save(snap); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	ids, err := s.List()
+	if err != nil || len(ids) != 3 {
+		t.Fatalf("List() = %v, %v; want 3 ids", ids, err)
+	}
+	snap, err := s.Load(ids[2])
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if snap.Host != "web-1" || len(snap.Results) != 1 || snap.Results[0].Process.PID != 102 {
+		t.Errorf("Load() = %+v", snap)
+	}
+
+	boots, err := s.Boots()
+	if err != nil || len(boots) != 2 || boots[0] != "boot-a" {
+		t.Fatalf("Boots() = %v, %v; want [boot-a boot-b]", boots, err)
+	}
+	if ids, _ := s.ListBoot("boot-a"); len(ids) != 2 {
+		t.Errorf("ListBoot(boot-a) = %v, want 2 ids", ids)
+	}
+	prev, err := PreviousBoot(s, "boot-b")
+	if err != nil || prev != "boot-a" {
+		t.Errorf("PreviousBoot() = %q, %v; want boot-a", prev, err)
+	}
+	MarkPreviousBoot(snap, "boot-c")
+	if !snap.Results[0].PreviousBoot || snap.Results[0].BootID != "boot-b" {
+		t.Errorf("MarkPreviousBoot() = %+v", snap.Results[0])
+	}
+
+	// Daemon snapshots are indexed by PID and start time
+	started := base.Add(3 * time.Hour)
+	origin := &Snapshot{TakenAt: started, BootID: "boot-b", Results: []model.Result{{Process: model.Process{PID: 200, StartedAt: started}}}}
+	if err := s.Save(origin); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if ids, err := s.Origins("boot-b", 200, started); err != nil || len(ids) != 1 || ids[0] != origin.ID {
+		t.Errorf("Origins() = %v, %v; want [%s]", ids, err, origin.ID)
+	}
+	if ids, _ := s.Origins("boot-b", 200, started.Add(time.Hour)); len(ids) != 0 {
+		t.Errorf("Origins() for another start time = %v, want empty", ids)
+	}
+	// Snapshots without start times leave boot-a unindexed, so all are searched
+	if ids, _ := s.Origins("boot-a", 100, base); len(ids) != 2 {
+		t.Errorf("Origins() for an unindexed boot = %v, want its 2 snapshots", ids)
+	}
+
+	// A one-byte limit leaves nothing
+	if err := s.Prune(Retention{MaxBytes: 1}); err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+	if ids, _ := s.List(); len(ids) != 0 {
+		t.Errorf("List() after Prune = %v, want empty", ids)
+	}
+	if ids, _ := s.Origins("boot-b", 200, started); len(ids) != 0 {
+		t.Errorf("Origins() after Prune = %v, want empty", ids)
+	}
+}
+
+func TestJSONStore(t *testing.T) {
+	s, err := OpenJSON(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	testStore(t, s)
+}
+
+func TestSQLiteStore(t *testing.T) {
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		t.Skip("sqlite3 not installed")
+	}
+	s, err := OpenSQLite(t.TempDir() + "/history.db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testStore(t, s)
+}
+
+func TestCorrelate(t *testing.T) {
+	proc := func(exe, user string) model.Result {
+		return model.Result{Process: model.Process{Exe: exe, Cmdline: exe + " --serve", User: user}}
+	}
+	var snaps []*Snapshot
+	for _, host := range []string{"web-1", "web-2", "web-3"} {
+		results := []model.Result{proc("/usr/sbin/nginx", "www-data")}
+		if host == "web-2" {
+			results = append(results, proc("/tmp/.x/miner", "nobody"))
+		}
+		snaps = append(snaps, &Snapshot{Host: host, Results: results})
+	}
+
+	groups, hosts, err := Correlate(snaps, ByExe)
+	if err != nil || hosts != 3 || len(groups) != 2 {
+		t.Fatalf("Correlate() = %+v, %d, %v", groups, hosts, err)
+	}
+	if groups[0].Key != "/usr/sbin/nginx" || len(groups[0].Hosts) != 3 || groups[0].Processes != 3 {
+		t.Errorf("groups[0] = %+v, want nginx on 3 hosts", groups[0])
+	}
+	out := Outliers(groups, hosts, 1)
+	if len(out) != 1 || out[0].Key != "/tmp/.x/miner" || out[0].Hosts[0] != "web-2" || out[0].Users[0] != "nobody" {
+		t.Errorf("Outliers() = %+v, want the miner on web-2", out)
+	}
+	if _, _, err := Correlate(snaps, "pid"); err == nil {
+		t.Error("Correlate() with an unknown key succeeded")
+	}
+}
+
+func TestLatestPerHost(t *testing.T) {
+	dir := t.TempDir()
+	s, err := OpenJSON(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	save := func(host, kind string, pid int, at time.Duration) *Snapshot {
+		snap := &Snapshot{TakenAt: base.Add(at), Host: host, Kind: kind,
+			Results: []model.Result{{Process: model.Process{PID: pid}}}}
+		if err := s.Save(snap); err != nil {
+			t.Fatal(err)
+		}
+		return snap
+	}
+	save("web-1", KindHost, 1, 0)
+	save("web-1", KindOrigins, 2, time.Hour)
+	save("web-2", KindHost, 3, 0)
+	broken := save("web-2", KindHost, 4, time.Hour)
+	if err := os.WriteFile(filepath.Join(dir, broken.ID+".json"), []byte("{"), 0o640); err != nil {
+		t.Fatal(err)
+	}
+
+	// Daemon snapshots are ignored and a broken one gives way to the previous
+	snaps, err := LatestPerHost(s)
+	if err != nil || len(snaps) != 2 {
+		t.Fatalf("LatestPerHost() = %+v, %v; want 2 snapshots", snaps, err)
+	}
+	if snaps[0].Host != "web-1" || snaps[0].Results[0].Process.PID != 1 ||
+		snaps[1].Host != "web-2" || snaps[1].Results[0].Process.PID != 3 {
+		t.Errorf("LatestPerHost() = %+v, %+v", snaps[0], snaps[1])
+	}
+}
+
+func TestFindOrigin(t *testing.T) {
+	s, err := OpenJSON(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	started := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i, src := range []string{"cron", "systemd"} {
+		snap := &Snapshot{
+			TakenAt: started.Add(time.Duration(i) * time.Hour),
+			BootID:  "boot-a",
+			Results: []model.Result{{
+				Process: model.Process{PID: 100, StartedAt: started.Add(time.Duration(i) * time.Hour)},
+				Source:  model.Source{Name: src},
+			}},
+		}
+		if err := s.Save(snap); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r, at, err := FindOrigin(s, "boot-a", 100, started.Add(10*time.Millisecond))
+	if err != nil || r == nil || r.Source.Name != "cron" || !at.Equal(started) {
+		t.Errorf("FindOrigin() = %+v, %v, %v; want cron recorded at %v", r, at, err, started)
+	}
+	// Same PID, different start time: a reused PID
+	if r, _, _ := FindOrigin(s, "boot-a", 100, started.Add(30*time.Minute)); r != nil {
+		t.Errorf("FindOrigin() for reused PID = %+v, want nil", r)
+	}
+}
@@ -0,0 +1,32 @@
+package target
+
+import (
+	"fmt"
+	"io"
+)
+
+// AmbiguousError is returned when a name matches more than one process and
+// witr cannot tell which one was meant
+type AmbiguousError struct {
+	Name string
+	// Matches lists each candidate as "PID <n>   <name>: <role>   (<kind>)"
+	Matches []string
+}
+
+func (e *AmbiguousError) Error() string {
+	return fmt.Sprintf("ambiguous target %q matches %d processes; re-run with an explicit pid", e.Name, len(e.Matches))
+}
+
+// Report prints the candidates and how to pick one
+func (e *AmbiguousError) Report(w io.Writer) {
+	fmt.Fprintf(w, "Ambiguous target: \"%s\"\n\n", e.Name)
+	fmt.Fprintln(w, "The name matches multiple entities:")
+	fmt.Fprintln(w)
+	for i, m := range e.Matches {
+		fmt.Fprintf(w, "[%d] %s\n", i+1, m)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "witr cannot determine intent safely.")
+	fmt.Fprintln(w, "Please re-run with an explicit PID:")
+	fmt.Fprintln(w, "  witr --pid <pid>")
+}
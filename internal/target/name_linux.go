@@ -75,25 +75,17 @@ func ResolveName(name string) ([]int, error) {
 		uniquePIDs[pid] = true
 	}
 	if len(uniquePIDs) > 1 {
-		fmt.Printf("Ambiguous target: \"%s\"\n\n", name)
-		fmt.Println("The name matches multiple entities:")
-		fmt.Println()
+		amb := &AmbiguousError{Name: name}
 		// Service entry first
-		fmt.Printf("[1] PID %d   %s: master process   (service)\n", servicePID, name)
+		amb.Matches = append(amb.Matches, fmt.Sprintf("PID %d   %s: master process   (service)", servicePID, name))
 		// Process entries (skip if PID matches servicePID)
-		idx := 2
 		for _, pid := range procPIDs {
 			if pid == servicePID {
 				continue
 			}
-			fmt.Printf("[%d] PID %d   %s: worker process   (manual)\n", idx, pid, name)
-			idx++
+			amb.Matches = append(amb.Matches, fmt.Sprintf("PID %d   %s: worker process   (manual)", pid, name))
 		}
-		fmt.Println()
-		fmt.Println("witr cannot determine intent safely.")
-		fmt.Println("Please re-run with an explicit PID:")
-		fmt.Println("  witr --pid <pid>")
-		os.Exit(1)
+		return nil, amb
 	}
 
 	// Service only
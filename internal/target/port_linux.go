@@ -48,13 +48,9 @@ func findSocketInodes(port int) (map[string]bool, error) {
 	return inodes, nil
 }
 
-func ResolvePort(port int) ([]int, error) {
-	inodes, err := findSocketInodes(port)
-	if err != nil {
-		return nil, err
-	}
-
-	// Map inode to PID that owns the LISTEN socket
+// pidsForInodes returns the PIDs holding a file descriptor for any of the
+// given socket inodes
+func pidsForInodes(inodes map[string]bool) map[int]bool {
 	pidSet := make(map[int]bool)
 	procEntries, _ := os.ReadDir("/proc")
 	for _, entry := range procEntries {
@@ -85,6 +81,17 @@ func ResolvePort(port int) ([]int, error) {
 		}
 	}
 
+	return pidSet
+}
+
+func ResolvePort(port int) ([]int, error) {
+	inodes, err := findSocketInodes(port)
+	if err != nil {
+		return nil, err
+	}
+
+	pidSet := pidsForInodes(inodes)
+
 	// Only return the lowest PID (the main listener, not forked children)
 	var result []int
 	minPID := 0
@@ -98,7 +105,7 @@ func ResolvePort(port int) ([]int, error) {
 	}
 
 	if len(result) == 0 {
-		return nil, fmt.Errorf("socket found but owning process not detected")
+		return nil, ErrOwnerHidden
 	}
 
 	return result, nil
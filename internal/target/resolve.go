@@ -1,12 +1,18 @@
 package target
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 
 	"github.com/pranshuparmar/witr/pkg/model"
 )
 
+// ErrOwnerHidden is returned for a port or socket that exists but whose
+// owning process could not be found, usually because its fds are not
+// readable without more privileges
+var ErrOwnerHidden = errors.New("socket found but owning process not detected")
+
 func Resolve(t model.Target) ([]int, error) {
 	switch t.Type {
 	case model.TargetPID:
@@ -23,6 +29,9 @@ func Resolve(t model.Target) ([]int, error) {
 		}
 		return ResolvePort(port)
 
+	case model.TargetSocket:
+		return ResolveSocket(t.Value)
+
 	case model.TargetName:
 		return ResolveName(t.Value)
 
@@ -0,0 +1,20 @@
+package target
+
+// socketOwner picks the process that owns a socket: the lowest PID, except
+// that PID 1 (systemd or launchd holding an activation socket) only wins
+// when no service process has picked the socket up yet
+func socketOwner(pids map[int]bool) int {
+	owner := 0
+	for pid := range pids {
+		if pid == 1 {
+			continue
+		}
+		if owner == 0 || pid < owner {
+			owner = pid
+		}
+	}
+	if owner == 0 && pids[1] {
+		return 1
+	}
+	return owner
+}
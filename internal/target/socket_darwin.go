@@ -0,0 +1,44 @@
+//go:build darwin
+
+package target
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/pranshuparmar/witr/internal/launchd"
+)
+
+func ResolveSocket(path string) ([]int, error) {
+	// lsof reports processes with the socket file open
+	// -t = terse output (PIDs only)
+	out, _ := exec.Command("lsof", "-t", path).Output()
+
+	pids := make(map[int]bool)
+	for _, pidStr := range strings.Fields(string(out)) {
+		pid, err := strconv.Atoi(pidStr)
+		if err == nil && pid > 0 {
+			pids[pid] = true
+		}
+	}
+	if pid := socketOwner(pids); pid > 1 {
+		return []int{pid}, nil
+	}
+
+	// launchd holds on-demand sockets itself until a client connects, so
+	// find the job that declares the socket and report its process
+	job, err := launchd.FindSocketJob(path)
+	if err != nil {
+		if pids[1] {
+			return []int{1}, nil
+		}
+		return nil, fmt.Errorf("no process listening on socket %s", path)
+	}
+	pid, err := resolveLaunchdServicePID(job.Label)
+	if err != nil {
+		return nil, fmt.Errorf("socket %s is managed by launchd job %s (%s), which is not running; it starts on the first connection", path, job.Label, job.PlistPath)
+	}
+	return []int{pid}, nil
+}
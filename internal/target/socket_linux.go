@@ -0,0 +1,47 @@
+//go:build linux
+
+package target
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// findUnixSocketInodes returns the inodes of Unix sockets bound to path.
+// Abstract sockets are matched with a leading "@", as ss prints them.
+func findUnixSocketInodes(path string) (map[string]bool, error) {
+	data, err := os.ReadFile("/proc/net/unix")
+	if err != nil {
+		return nil, err
+	}
+
+	inodes := make(map[string]bool)
+	lines := strings.Split(string(data), "\n")
+	for _, line := range lines[1:] {
+		// Num RefCount Protocol Flags Type St Inode Path
+		fields := strings.Fields(line)
+		if len(fields) < 8 || fields[7] != path {
+			continue
+		}
+		inodes[fields[6]] = true
+	}
+
+	if len(inodes) == 0 {
+		return nil, fmt.Errorf("no process listening on socket %s", path)
+	}
+	return inodes, nil
+}
+
+func ResolveSocket(path string) ([]int, error) {
+	inodes, err := findUnixSocketInodes(path)
+	if err != nil {
+		return nil, err
+	}
+
+	pid := socketOwner(pidsForInodes(inodes))
+	if pid == 0 {
+		return nil, ErrOwnerHidden
+	}
+	return []int{pid}, nil
+}
@@ -0,0 +1,76 @@
+package model
+
+// With* helpers return a modified copy of a Result, leaving the receiver
+// untouched, so wrapper tools can enrich or trim a result before rendering it.
+
+// WithMetadata returns a copy of r with a metadata entry set
+func (r Result) WithMetadata(key, value string) Result {
+	out := r
+	out.Metadata = cloneMap(r.Metadata)
+	if out.Metadata == nil {
+		out.Metadata = make(map[string]string)
+	}
+	out.Metadata[key] = value
+	return out
+}
+
+// WithWarning returns a copy of r with an additional warning, which has
+// the code WarnOther
+func (r Result) WithWarning(warning string) Result {
+	out := r
+	out.Warnings = append(append([]string{}, r.Warnings...), warning)
+	return out
+}
+
+// WithoutWarnings returns a copy of r with all warnings removed
+func (r Result) WithoutWarnings() Result {
+	out := r
+	out.Warnings = nil
+	out.Findings = nil
+	return out
+}
+
+// WithSourceDetail returns a copy of r with a source detail set
+func (r Result) WithSourceDetail(key, value string) Result {
+	out := r
+	out.Source.Details = cloneMap(r.Source.Details)
+	if out.Source.Details == nil {
+		out.Source.Details = make(map[string]string)
+	}
+	out.Source.Details[key] = value
+	return out
+}
+
+// WithoutEnv returns a copy of r with environment variables stripped from
+// the process and its ancestry
+func (r Result) WithoutEnv() Result {
+	out := r
+	out.Process.Env = nil
+	out.Ancestry = make([]Process, len(r.Ancestry))
+	for i, p := range r.Ancestry {
+		p.Env = nil
+		out.Ancestry[i] = p
+	}
+	return out
+}
+
+// WithoutContext returns a copy of r without the socket, resource and file
+// context sections
+func (r Result) WithoutContext() Result {
+	out := r
+	out.SocketInfo = nil
+	out.ResourceContext = nil
+	out.FileContext = nil
+	return out
+}
+
+func cloneMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
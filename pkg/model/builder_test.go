@@ -0,0 +1,38 @@
+package model
+
+import "testing"
+
+func TestWithHelpersDoNotMutate(t *testing.T) {
+	orig := Result{
+		Warnings: []string{"Process is running as root"},
+		Source:   Source{Type: SourceSystemd, Details: map[string]string{"unit": "nginx.service"}},
+		Process:  Process{PID: 42, Env: []string{"TOKEN=x"}},
+		Ancestry: []Process{{PID: 1}, {PID: 42, Env: []string{"TOKEN=x"}}},
+	}
+
+	r := orig.
+		WithMetadata("team", "payments").
+		WithWarning("custom").
+		WithSourceDetail("unit", "other.service").
+		WithoutEnv()
+
+	if r.Metadata["team"] != "payments" {
+		t.Errorf("WithMetadata() not applied: %v", r.Metadata)
+	}
+	if len(r.Warnings) != 2 || r.Warnings[1] != "custom" {
+		t.Errorf("WithWarning() = %v", r.Warnings)
+	}
+	if r.Source.Details["unit"] != "other.service" {
+		t.Errorf("WithSourceDetail() = %v", r.Source.Details)
+	}
+	if r.Process.Env != nil || r.Ancestry[1].Env != nil {
+		t.Errorf("WithoutEnv() left environment in place")
+	}
+
+	if orig.Metadata != nil || len(orig.Warnings) != 1 || orig.Source.Details["unit"] != "nginx.service" {
+		t.Errorf("original result was mutated: %+v", orig)
+	}
+	if orig.Process.Env == nil || orig.Ancestry[1].Env == nil {
+		t.Errorf("original environment was stripped")
+	}
+}
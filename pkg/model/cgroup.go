@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// CgroupInfo describes the cgroup v2 of a process and the limits that
+// constrain it, which may be set by the cgroup itself or an enclosing slice
+type CgroupInfo struct {
+	// Path is relative to the cgroup root, e.g. /system.slice/nginx.service
+	Path string
+	// MemoryMax is the tightest memory.max on the path in bytes (0 for no
+	// limit), set by MemoryLimitedBy; MemoryCurrent is the usage of
+	// that cgroup, or of Path when nothing limits memory
+	MemoryCurrent   uint64
+	MemoryMax       uint64 `json:",omitempty"`
+	MemoryLimitedBy string `json:",omitempty"`
+	// CPUQuota is the tightest cpu.max on the path in CPUs, e.g. 0.5 (0 for
+	// no limit), set by CPULimitedBy
+	CPUQuota     float64 `json:",omitempty"`
+	CPULimitedBy string  `json:",omitempty"`
+	// Throttling counters from cpu.stat
+	Periods       uint64        `json:",omitempty"`
+	Throttled     uint64        `json:",omitempty"`
+	ThrottledTime time.Duration `json:",omitempty"`
+	// OOMKills counts processes the OOM killer ended in the cgroup
+	OOMKills uint64 `json:",omitempty"`
+}
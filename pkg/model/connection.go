@@ -0,0 +1,17 @@
+package model
+
+// ConnectionGroup counts a process's established connections to one remote
+// endpoint. Inbound connections to a listening port are grouped by remote
+// host only, since their remote ports are ephemeral.
+type ConnectionGroup struct {
+	Protocol string // tcp, tcp6, udp, udp6
+	// Direction is "out" for connections the process opened and "in" for
+	// connections accepted on one of its listening ports
+	Direction  string
+	RemoteAddr string
+	// RemotePort is zero for inbound groups
+	RemotePort int `json:",omitempty"`
+	// LocalPort is the listening port inbound connections arrived on
+	LocalPort int `json:",omitempty"`
+	Count     int
+}
@@ -0,0 +1,38 @@
+package model
+
+// Container describes the container a process runs in, as reported by the
+// runtime's inspect API
+type Container struct {
+	Runtime string
+	ID      string
+	Name    string
+	// Image is the image reference the container was created from, e.g.
+	// "nginx:1.27"
+	Image string `json:",omitempty"`
+	// Digest is the registry digest of the image, e.g. "sha256:...", when
+	// it was pulled from a registry
+	Digest string `json:",omitempty"`
+	// Labels are the labels set on the container itself; labels inherited
+	// from the image are left out
+	Labels map[string]string `json:",omitempty"`
+	Ports  []PortMapping     `json:",omitempty"`
+	Mounts []Mount           `json:",omitempty"`
+}
+
+// PortMapping is a container port and the host address it is published on.
+// HostPort is empty for exposed ports that are not published.
+type PortMapping struct {
+	ContainerPort string // e.g. "80/tcp"
+	HostIP        string `json:",omitempty"`
+	HostPort      string `json:",omitempty"`
+}
+
+// Mount is a bind mount, volume or tmpfs of a container
+type Mount struct {
+	Type string // bind, volume, tmpfs
+	// Source is the host path; Name is set for named volumes
+	Source      string `json:",omitempty"`
+	Name        string `json:",omitempty"`
+	Destination string
+	ReadOnly    bool `json:",omitempty"`
+}
@@ -0,0 +1,41 @@
+package model
+
+// Family counts the threads of a process and the processes below it, so a
+// master process with many workers is recognizable as one
+type Family struct {
+	// Threads of the process itself; 0 when unknown (macOS)
+	Threads  int `json:",omitempty"`
+	Children int
+	// Descendants counts every process below, Children included
+	Descendants int
+	// Truncated is set when counting stopped at its cap
+	Truncated bool `json:",omitempty"`
+	// SubtreeThreads and SubtreeRSS total the process and all descendants
+	SubtreeThreads int `json:",omitempty"`
+	SubtreeRSS     uint64
+}
+
+// DescendantSummary describes a large descendant set (fork bomb, runaway
+// worker spawner) without listing every process
+type DescendantSummary struct {
+	// Count is the number of descendants found
+	Count int
+	// Truncated is set when traversal stopped at its cap, so Count is a floor
+	Truncated bool `json:",omitempty"`
+	// Top lists the most common descendant commands, most frequent first
+	Top []CommandCount
+}
+
+// CommandCount is the number of processes running one command
+type CommandCount struct {
+	Command string
+	Count   int
+}
+
+// ChildProcess is one node of the subtree below a process (with --children)
+type ChildProcess struct {
+	PID      int
+	Command  string
+	Cmdline  string         `json:",omitempty"`
+	Children []ChildProcess `json:",omitempty"`
+}
@@ -0,0 +1,23 @@
+package model
+
+// FDSummary summarizes the open file descriptors of a process (with --fds)
+type FDSummary struct {
+	Open int
+	// Limit is the soft RLIMIT_NOFILE, 0 when unknown
+	Limit int
+	// Counts maps a descriptor type (file, directory, socket, pipe, device,
+	// anon) to the number open
+	Counts map[string]int
+	// Largest lists the biggest regular files held open, largest first
+	Largest []OpenFile `json:",omitempty"`
+}
+
+// OpenFile is a regular file a process holds open
+type OpenFile struct {
+	FD   int
+	Path string
+	Size int64
+	// Deleted is set for files unlinked while open, whose space is only
+	// freed when the process closes them
+	Deleted bool `json:",omitempty"`
+}
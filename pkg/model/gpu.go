@@ -0,0 +1,21 @@
+package model
+
+// GPUUsage describes the GPU devices a process holds open and, for NVIDIA
+// GPUs, what it uses on each (Linux)
+type GPUUsage struct {
+	// Devices are the open device nodes, e.g. /dev/nvidia0 or /dev/dri/renderD128
+	Devices []string
+	// GPUs is filled from nvidia-smi when it is installed
+	GPUs []GPUProcess `json:",omitempty"`
+}
+
+// GPUProcess is the use of one GPU by the process
+type GPUProcess struct {
+	Index int
+	// Type is "compute", "graphics" or both joined by "+"
+	Type string `json:",omitempty"`
+	// SMPercent is the utilization of the streaming multiprocessors and
+	// MemoryMiB the framebuffer memory in use; nil when not reported
+	SMPercent *int `json:",omitempty"`
+	MemoryMiB *int `json:",omitempty"`
+}
@@ -0,0 +1,56 @@
+package model
+
+// Identity holds the user and group credentials of a process
+type Identity struct {
+	RealUID, EffectiveUID, SavedUID int
+	RealGID, EffectiveGID, SavedGID int
+	// UserName and GroupName resolve the real UID and GID
+	UserName  string
+	GroupName string
+	// Groups are the supplementary groups
+	Groups []GroupRef `json:",omitempty"`
+	// Capabilities are the effective Linux capabilities, e.g. CAP_NET_RAW
+	Capabilities []string `json:",omitempty"`
+	// AllCapabilities reports that every known capability is held, as for
+	// an unconfined root process
+	AllCapabilities bool `json:",omitempty"`
+	// Privilege summarizes the credentials, e.g. "root" or "dropped privileges"
+	Privilege string
+	// Seccomp is the seccomp mode (Linux): "disabled", "strict" or "filter",
+	// empty when the kernel does not report it
+	Seccomp string `json:",omitempty"`
+	// SeccompFilters counts the attached filters in filter mode
+	SeccompFilters int `json:",omitempty"`
+	// NoNewPrivs reports that execve cannot gain privileges (setuid bits and
+	// file capabilities are ignored)
+	NoNewPrivs bool `json:",omitempty"`
+}
+
+// Values of Identity.Seccomp
+const (
+	SeccompDisabled = "disabled"
+	SeccompStrict   = "strict"
+	SeccompFilter   = "filter"
+)
+
+// GroupRef is a group ID with its name, when it resolves
+type GroupRef struct {
+	ID   int
+	Name string `json:",omitempty"`
+}
+
+// CapabilityNames are the Linux capabilities indexed by number
+// (linux/capability.h)
+var CapabilityNames = []string{
+	"CAP_CHOWN", "CAP_DAC_OVERRIDE", "CAP_DAC_READ_SEARCH", "CAP_FOWNER",
+	"CAP_FSETID", "CAP_KILL", "CAP_SETGID", "CAP_SETUID",
+	"CAP_SETPCAP", "CAP_LINUX_IMMUTABLE", "CAP_NET_BIND_SERVICE", "CAP_NET_BROADCAST",
+	"CAP_NET_ADMIN", "CAP_NET_RAW", "CAP_IPC_LOCK", "CAP_IPC_OWNER",
+	"CAP_SYS_MODULE", "CAP_SYS_RAWIO", "CAP_SYS_CHROOT", "CAP_SYS_PTRACE",
+	"CAP_SYS_PACCT", "CAP_SYS_ADMIN", "CAP_SYS_BOOT", "CAP_SYS_NICE",
+	"CAP_SYS_RESOURCE", "CAP_SYS_TIME", "CAP_SYS_TTY_CONFIG", "CAP_MKNOD",
+	"CAP_LEASE", "CAP_AUDIT_WRITE", "CAP_AUDIT_CONTROL", "CAP_SETFCAP",
+	"CAP_MAC_OVERRIDE", "CAP_MAC_ADMIN", "CAP_SYSLOG", "CAP_WAKE_ALARM",
+	"CAP_BLOCK_SUSPEND", "CAP_AUDIT_READ", "CAP_PERFMON", "CAP_BPF",
+	"CAP_CHECKPOINT_RESTORE",
+}
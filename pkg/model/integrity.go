@@ -0,0 +1,19 @@
+package model
+
+// Integrity describes kernel integrity measurements and signatures of the
+// running executable
+type Integrity struct {
+	// IMA is the digest the IMA measurement list recorded for the executable
+	IMA string `json:",omitempty"`
+	// IMAAppraisal describes the security.ima xattr used for appraisal
+	// ("signature", "file hash", "fs-verity signature")
+	IMAAppraisal string `json:",omitempty"`
+	// EVM describes the security.evm xattr protecting file metadata
+	EVM string `json:",omitempty"`
+	// FSVerity is the fs-verity digest when verity is enabled on the file
+	FSVerity string `json:",omitempty"`
+	// Signature is the result of verifying a detached Sigstore signature
+	Signature string `json:",omitempty"`
+	// SignatureOK reports whether that verification succeeded
+	SignatureOK bool `json:",omitempty"`
+}
@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// LaunchRecord is how a process was started, reconstructed from the audit
+// log's execve record
+type LaunchRecord struct {
+	Time time.Time
+	Argv []string
+	Exe  string `json:",omitempty"`
+	Cwd  string `json:",omitempty"`
+	PPID int    `json:",omitempty"`
+	// LoginUser is the audit login user (auid), which survives su and sudo
+	LoginUser string `json:",omitempty"`
+	UID       string `json:",omitempty"`
+	TTY       string `json:",omitempty"`
+	Session   string `json:",omitempty"`
+}
@@ -0,0 +1,17 @@
+package model
+
+// ResourceLimit is a resource limit of a process with its current usage,
+// where that can be measured (Linux)
+type ResourceLimit struct {
+	// Name is the ulimit name: nofile, nproc, memlock or core
+	Name string
+	// Soft and Hard are -1 when unlimited; memlock and core are in bytes
+	Soft int64
+	Hard int64
+	// Usage is the amount in use, when known: open descriptors, tasks of
+	// the user, or locked bytes
+	Usage *int64 `json:",omitempty"`
+}
+
+// Unlimited is the Soft or Hard value of a limit that is not set
+const Unlimited = -1
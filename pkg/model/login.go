@@ -0,0 +1,19 @@
+package model
+
+// LoginSession is the login that is ultimately responsible for a process,
+// from its audit login uid and session ID (Linux). Both survive
+// re-parenting, so they still name the login after the shell has exited.
+type LoginSession struct {
+	LoginUID  int
+	LoginUser string `json:",omitempty"`
+	// ID is the audit session ID, which logind uses as its session ID
+	ID string
+	// The remaining fields come from logind while the session exists
+	Type       string `json:",omitempty"` // tty, x11, wayland
+	Service    string `json:",omitempty"` // PAM service, e.g. sshd or login
+	TTY        string `json:",omitempty"`
+	RemoteHost string `json:",omitempty"`
+	// Leader is the PID of the process that opened the session
+	Leader int    `json:",omitempty"`
+	State  string `json:",omitempty"` // active, online, closing
+}
@@ -0,0 +1,9 @@
+package model
+
+// LogTail is the end of the log of a process's unit or container
+// (with --logs)
+type LogTail struct {
+	// Command is how the lines were fetched, e.g. "journalctl -u nginx.service"
+	Command string
+	Lines   []string
+}
@@ -0,0 +1,10 @@
+package model
+
+// Namespace is one Linux namespace a process belongs to
+type Namespace struct {
+	// Type is pid, net, mnt, user, uts, ipc or cgroup
+	Type  string
+	Inode uint64
+	// Isolated reports that the namespace differs from init's (pid 1)
+	Isolated bool
+}
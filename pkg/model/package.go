@@ -0,0 +1,11 @@
+package model
+
+// PackageOwner is the package that installed an executable
+type PackageOwner struct {
+	// Manager is the package manager asked: dpkg, rpm, pacman, apk,
+	// homebrew or pkgutil
+	Manager string
+	// Name and Version are empty when no package owns the file
+	Name    string `json:",omitempty"`
+	Version string `json:",omitempty"`
+}
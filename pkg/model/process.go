@@ -1,25 +1,63 @@
 package model
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 type Process struct {
-	PID       int
-	PPID      int
-	Command   string
-	Cmdline   string
-	Exe       string
+	PID     int
+	PPID    int
+	Command string
+	Cmdline string
+	Exe     string
+	// ExeState is set when the running executable no longer matches the
+	// file at its path (Linux): ExeDeleted, ExeReplaced or ExeModified
+	ExeState  string `json:",omitempty"`
 	StartedAt time.Time
+	// Uptime is how long the process has been running, and SinceBoot how
+	// long after boot it started
+	Uptime    time.Duration
+	SinceBoot time.Duration
 	User      string
 
+	// LaunchUser is the identity the process was started as, when it differs
+	// from User (started as root and dropped privileges, or a setuid binary)
+	LaunchUser string
+	// Setuid reports whether the executable has the setuid bit set
+	Setuid bool
+	// Identity holds real, effective and saved IDs and supplementary groups
+	Identity *Identity `json:",omitempty"`
+	// Security is the AppArmor or SELinux label (Linux)
+	Security *SecurityLabel `json:",omitempty"`
+	// Namespaces lists the Linux namespaces, marking those not shared
+	// with init
+	Namespaces []Namespace `json:",omitempty"`
+	// Scheduling holds niceness, scheduling policy, CPU affinity and OOM
+	// score (Linux)
+	Scheduling *Scheduling `json:",omitempty"`
+
 	WorkingDir string
-	GitRepo    string
-	GitBranch  string
-	Container  string
-	Service    string
+	// Root is the root directory (Linux); anything but "/" means the
+	// process is chrooted
+	Root      string `json:",omitempty"`
+	GitRepo   string
+	GitBranch string
+	Container string
+	Service   string
 
 	// Network context
 	ListeningPorts []int
 	BindAddresses  []string
+	// Sockets lists every listening TCP, UDP and Unix socket
+	Sockets []ListenSocket `json:",omitempty"`
+
+	// Resource usage
+	RSSBytes   uint64
+	MemPercent float64
+	CPUTime    time.Duration
+	// CPUPercent is sampled over a short interval, for the target only
+	CPUPercent *float64 `json:",omitempty"`
 
 	// Health status ("healthy", "zombie", "stopped", "high-cpu", "high-mem")
 	Health string
@@ -28,4 +66,26 @@ type Process struct {
 	Forked string
 	// Environment variables (key=value)
 	Env []string
+
+	// SkippedAncestors counts ancestors between this process and the next
+	// one in the chain that were omitted because the chain was too deep
+	SkippedAncestors int `json:",omitempty"`
 }
+
+// Argv0 returns the program name the command line starts with, without the
+// "-" of login shells or the ":" processes such as "nginx: worker process"
+// append
+func (p Process) Argv0() string {
+	fields := strings.Fields(p.Cmdline)
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(fields[0], "-"), ":")
+}
+
+// Values of Process.ExeState
+const (
+	ExeDeleted  = "deleted"
+	ExeReplaced = "replaced"
+	ExeModified = "modified"
+)
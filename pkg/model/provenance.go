@@ -0,0 +1,21 @@
+package model
+
+// Parts of a result whose collection time is recorded in Result.SampledAt
+const (
+	SampledProcess     = "process"
+	SampledSource      = "source"
+	SampledCPU         = "cpu"
+	SampledSocket      = "socket"
+	SampledResources   = "resources"
+	SampledFiles       = "files"
+	SampledIntegrity   = "integrity"
+	SampledConnections = "connections"
+	SampledLaunch      = "launch"
+	SampledTriage      = "triage"
+	SampledCgroup      = "cgroup"
+	SampledPackage     = "package"
+	SampledChildren    = "children"
+	SampledLogs        = "logs"
+	SampledContainer   = "container"
+	SampledFDs         = "fds"
+)
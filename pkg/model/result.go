@@ -1,6 +1,12 @@
 package model
 
+import "time"
+
 type Result struct {
+	// SchemaVersion is set to SchemaVersion when the result is written as
+	// JSON
+	SchemaVersion int `json:"schema_version"`
+
 	Target         Target
 	ResolvedTarget string
 	Process        Process
@@ -9,6 +15,14 @@ type Result struct {
 	Source         Source
 	Warnings       []string
 
+	// Findings are the Warnings with their codes and severities, as their
+	// emitters recorded them (see AddWarnings and WarningFindings)
+	Findings []Warning `json:",omitempty"`
+
+	// Error is set, and nothing else but Target, when the target could not
+	// be resolved or read in a multi-target run
+	Error string `json:",omitempty"`
+
 	// SocketInfo holds socket state details (for port queries)
 	SocketInfo *SocketInfo
 
@@ -17,4 +31,73 @@ type Result struct {
 
 	// FileContext holds file descriptor and lock info
 	FileContext *FileContext
+
+	// Service holds the unit configuration and state for processes of a
+	// systemd system service (Linux)
+	Service *ServiceUnit `json:",omitempty"`
+
+	// Login is the login session the process descends from (Linux)
+	Login *LoginSession `json:",omitempty"`
+
+	// Container holds the image, labels, ports and mounts of the container
+	// a process runs in (Linux)
+	Container *Container `json:",omitempty"`
+
+	// Sandbox is set when witr runs under gVisor, Firecracker or a user
+	// namespace, where some probes are skipped
+	Sandbox *Sandbox `json:",omitempty"`
+
+	// Cgroup holds the cgroup v2 path, limits and throttling (Linux)
+	Cgroup *CgroupInfo `json:",omitempty"`
+
+	// Metadata holds extra key/value pairs added by wrapper tools
+	Metadata map[string]string `json:",omitempty"`
+
+	// Family counts threads, children and descendants with their total RSS
+	Family *Family `json:",omitempty"`
+
+	// Descendants summarizes a pathologically large descendant set
+	Descendants *DescendantSummary `json:",omitempty"`
+
+	// Children is the subtree below the process (with --children)
+	Children []ChildProcess `json:",omitempty"`
+
+	// GPU lists the GPU devices the process holds open and its usage (Linux)
+	GPU *GPUUsage `json:",omitempty"`
+
+	// Limits lists notable resource limits with their usage (Linux)
+	Limits []ResourceLimit `json:",omitempty"`
+
+	// FDs summarizes the open file descriptors (with --fds)
+	FDs *FDSummary `json:",omitempty"`
+
+	// Logs is the end of the unit's or container's log (with --logs)
+	Logs *LogTail `json:",omitempty"`
+
+	// Launch is the execve record from the audit log (with --audit)
+	Launch *LaunchRecord `json:",omitempty"`
+
+	// Connections groups established connections by remote endpoint
+	// (with --connections)
+	Connections []ConnectionGroup `json:",omitempty"`
+
+	// Integrity holds IMA, fs-verity and signature state of the executable
+	Integrity *Integrity `json:",omitempty"`
+
+	// Package is the package that installed the executable; an empty Name
+	// means no package owns it
+	Package *PackageOwner `json:",omitempty"`
+
+	// Triage holds raw evidence collected by --investigate
+	Triage *Triage `json:",omitempty"`
+
+	// SampledAt records when each part of the result was collected, keyed
+	// by the Sampled* constants, so consumers can judge staleness
+	SampledAt map[string]time.Time `json:",omitempty"`
+
+	// BootID identifies the boot the result was captured in
+	BootID string `json:",omitempty"`
+	// PreviousBoot marks results loaded from history that describe a
+	// process from an earlier boot
+	PreviousBoot bool `json:",omitempty"`
 }
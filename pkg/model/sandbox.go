@@ -0,0 +1,15 @@
+package model
+
+// Sandbox describes a sandboxed or virtualized kernel witr is running
+// under, where parts of /proc behave differently from a regular host
+type Sandbox struct {
+	// Runtime names the sandbox, e.g. "gVisor", "Firecracker microVM" or
+	// "user namespace (LXC)"
+	Runtime  string
+	Evidence string
+	// Skipped lists the probes witr did not run because their data would
+	// be misleading there
+	Skipped []string `json:",omitempty"`
+	// RootUID is the host uid that root maps to, in a user namespace
+	RootUID string `json:",omitempty"`
+}
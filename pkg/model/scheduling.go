@@ -0,0 +1,24 @@
+package model
+
+// Scheduling holds the CPU scheduling and OOM killer settings of a process
+// (Linux)
+type Scheduling struct {
+	Nice int
+	// Policy is the scheduling policy, e.g. SCHED_OTHER, SCHED_FIFO or SCHED_RR
+	Policy string
+	// RTPriority is the realtime priority (1-99) under SCHED_FIFO and SCHED_RR
+	RTPriority int `json:",omitempty"`
+	// CPUs is the affinity list the process may run on, e.g. "0-3,8"
+	CPUs string `json:",omitempty"`
+	// Pinned reports that CPUs excludes some online CPUs
+	Pinned bool `json:",omitempty"`
+	// OOMScoreAdj is the bias the OOM killer applies (-1000 exempts the
+	// process, 1000 makes it the first victim); OOMScore is the result
+	OOMScoreAdj int
+	OOMScore    int
+}
+
+// Realtime reports whether the process runs under a realtime policy
+func (s *Scheduling) Realtime() bool {
+	return s.Policy == "SCHED_FIFO" || s.Policy == "SCHED_RR" || s.Policy == "SCHED_DEADLINE"
+}
@@ -0,0 +1,120 @@
+package model
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// SchemaVersion is the version of the JSON result format, written as
+// schema_version in every JSON result. It is raised whenever a field is
+// renamed, removed or changes type; new fields do not change it.
+const SchemaVersion = 1
+
+// Schema returns the JSON Schema (draft 2020-12) describing a Result as
+// witr --json writes it. It is generated from the Go types, so it always
+// matches the fields of this build.
+func Schema() ([]byte, error) {
+	g := schemaGenerator{defs: make(map[string]any)}
+	root := g.structSchema(reflect.TypeFor[Result]())
+	root["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	root["title"] = "witr result"
+	root["description"] = "One explained process, as written by witr --json (an array of these for several targets) and witr --ndjson (one per line)."
+	root["properties"].(map[string]any)["schema_version"] = map[string]any{
+		"const":       SchemaVersion,
+		"description": "Version of this schema; raised when a field is renamed, removed or changes type",
+	}
+	root["$defs"] = g.defs
+	return json.MarshalIndent(root, "", "  ")
+}
+
+// schemaGenerator collects the schemas of named struct types in $defs, so
+// shared and recursive types (ChildProcess) are described once
+type schemaGenerator struct {
+	defs map[string]any
+}
+
+var (
+	timeType     = reflect.TypeFor[time.Time]()
+	durationType = reflect.TypeFor[time.Duration]()
+	severityType = reflect.TypeFor[Severity]()
+)
+
+// typeSchema describes values of t as encoding/json writes them. Slices
+// and maps can be null, as nil ones are written that way.
+func (g *schemaGenerator) typeSchema(t reflect.Type) map[string]any {
+	switch t {
+	case timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case durationType:
+		return map[string]any{"type": "integer", "description": "nanoseconds"}
+	case severityType:
+		return map[string]any{"enum": severityNames}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Pointer:
+		return map[string]any{"anyOf": []any{g.typeSchema(t.Elem()), map[string]any{"type": "null"}}}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": []string{"string", "null"}, "contentEncoding": "base64"}
+		}
+		return map[string]any{"type": []string{"array", "null"}, "items": g.typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": []string{"object", "null"}, "additionalProperties": g.typeSchema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		ref := map[string]any{"$ref": "#/$defs/" + t.Name()}
+		if _, ok := g.defs[t.Name()]; !ok {
+			// Reserve the name before descending, for recursive types
+			g.defs[t.Name()] = nil
+			g.defs[t.Name()] = g.structSchema(t)
+		}
+		return ref
+	}
+	return map[string]any{}
+}
+
+// structSchema describes a struct as an object, following the json tags.
+// Fields without omitempty are always written and so are required.
+func (g *schemaGenerator) structSchema(t reflect.Type) map[string]any {
+	props := make(map[string]any)
+	required := []string{}
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			embedded := g.structSchema(f.Type)
+			for k, v := range embedded["properties"].(map[string]any) {
+				props[k] = v
+			}
+			required = append(required, embedded["required"].([]string)...)
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		props[name] = g.typeSchema(f.Type)
+		if !strings.Contains(","+opts+",", ",omitempty,") && !strings.Contains(","+opts+",", ",omitzero,") {
+			required = append(required, name)
+		}
+	}
+	return map[string]any{"type": "object", "properties": props, "required": required}
+}
@@ -0,0 +1,89 @@
+package model
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// schemaCovers fails for parts of v the schema does not describe and for
+// required properties v lacks
+func schemaCovers(t *testing.T, defs map[string]any, schema map[string]any, v any, path string) {
+	t.Helper()
+	if ref, ok := schema["$ref"].(string); ok {
+		schema = defs[strings.TrimPrefix(ref, "#/$defs/")].(map[string]any)
+	}
+	if anyOf, ok := schema["anyOf"].([]any); ok {
+		if v == nil {
+			return
+		}
+		schema = anyOf[0].(map[string]any)
+		schemaCovers(t, defs, schema, v, path)
+		return
+	}
+	switch val := v.(type) {
+	case map[string]any:
+		props, ok := schema["properties"].(map[string]any)
+		if !ok {
+			if extra, ok := schema["additionalProperties"].(map[string]any); ok {
+				for k, item := range val {
+					schemaCovers(t, defs, extra, item, path+"."+k)
+				}
+			}
+			return
+		}
+		for k, item := range val {
+			sub, ok := props[k].(map[string]any)
+			if !ok {
+				t.Errorf("%s.%s is not in the schema", path, k)
+				continue
+			}
+			schemaCovers(t, defs, sub, item, path+"."+k)
+		}
+		for _, r := range schema["required"].([]any) {
+			if _, ok := val[r.(string)]; !ok {
+				t.Errorf("%s.%s is required but missing", path, r)
+			}
+		}
+	case []any:
+		for _, item := range val {
+			schemaCovers(t, defs, schema["items"].(map[string]any), item, path+"[]")
+		}
+	}
+}
+
+func TestSchemaDescribesResults(t *testing.T) {
+	data, err := Schema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatal(err)
+	}
+	if v := schema["properties"].(map[string]any)["schema_version"].(map[string]any)["const"]; v != float64(SchemaVersion) {
+		t.Errorf("schema_version const = %v", v)
+	}
+	required := schema["required"].([]any)
+	if !slices.Contains(required, any("Target")) || slices.Contains(required, any("Error")) {
+		t.Errorf("required = %v, want Target but not Error", required)
+	}
+
+	p := Process{PID: 42, Command: "nginx", StartedAt: time.Now(), Env: []string{"A=b"}}
+	res := Result{
+		SchemaVersion: SchemaVersion,
+		Target:        Target{Type: TargetPID, Value: "42"},
+		Process:       p,
+		Ancestry:      []Process{{PID: 1}, p},
+		Source:        Source{Type: SourceSystemd, Details: map[string]string{"unit": "nginx.service"}},
+		Children:      []ChildProcess{{PID: 43, Children: []ChildProcess{{PID: 44}}}},
+		Container:     &Container{Ports: []PortMapping{{ContainerPort: "80/tcp"}}},
+	}
+	res.AddWarnings(Warning{Code: WarnRoot, Severity: SeverityLow, Message: "Process is running as root"})
+	out, _ := json.Marshal(res)
+	var v any
+	json.Unmarshal(out, &v)
+	schemaCovers(t, schema["$defs"].(map[string]any), schema, v, "Result")
+}
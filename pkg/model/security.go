@@ -0,0 +1,18 @@
+package model
+
+// SecurityLabel describes the mandatory access control (AppArmor or
+// SELinux) confinement of a process
+type SecurityLabel struct {
+	// LSM is the security module that assigned the label, "apparmor" or
+	// "selinux"
+	LSM string
+	// Label is the raw label from /proc/<pid>/attr/current
+	Label string
+	// Profile is the AppArmor profile or the SELinux type (domain)
+	Profile string
+	// Mode is the AppArmor profile mode (enforce, complain, kill,
+	// unconfined) or the SELinux mode (enforcing, permissive)
+	Mode string `json:",omitempty"`
+	// Confined reports that a policy restricts the process and is enforced
+	Confined bool
+}
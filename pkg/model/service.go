@@ -0,0 +1,25 @@
+package model
+
+// ServiceUnit holds the configuration and run state of the systemd
+// service a process belongs to
+type ServiceUnit struct {
+	Unit string
+	// ExecStart is the command line systemd runs
+	ExecStart string `json:",omitempty"`
+	// Restart is the Restart= policy, e.g. "on-failure" or "no"
+	Restart string `json:",omitempty"`
+	// NRestarts counts automatic restarts since the unit was last started
+	NRestarts   int
+	ActiveState string `json:",omitempty"`
+	SubState    string `json:",omitempty"`
+	// ActiveSince is when the unit last entered the active state
+	ActiveSince  string   `json:",omitempty"`
+	FragmentPath string   `json:",omitempty"`
+	DropIns      []DropIn `json:",omitempty"`
+}
+
+// DropIn is a unit drop-in file and the settings it assigns
+type DropIn struct {
+	Path     string
+	Settings []string `json:",omitempty"`
+}
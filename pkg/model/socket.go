@@ -9,3 +9,12 @@ type SocketInfo struct {
 	Explanation string // Human-readable explanation of the state
 	Workaround  string // Suggested workaround if applicable
 }
+
+// ListenSocket is a socket a process is listening on (or, for UDP, bound to)
+type ListenSocket struct {
+	Protocol string // tcp, tcp6, udp, udp6, unix
+	Address  string `json:",omitempty"`
+	Port     int    `json:",omitempty"`
+	// Path is the Unix socket path ("@name" for abstract sockets)
+	Path string `json:",omitempty"`
+}
@@ -9,6 +9,15 @@ const (
 	SourceSupervisor SourceType = "supervisor"
 	SourceCron       SourceType = "cron"
 	SourceShell      SourceType = "shell"
+	SourceBackup     SourceType = "backup"
+	SourceSSH        SourceType = "ssh"
+	SourceSubsystem  SourceType = "subsystem"
+	SourceDesktop    SourceType = "desktop"
+	SourceUdev       SourceType = "udev"
+	SourceSnap       SourceType = "snap"
+	SourceFlatpak    SourceType = "flatpak"
+	SourceAppImage   SourceType = "appimage"
+	SourceWSL        SourceType = "wsl"
 	SourceUnknown    SourceType = "unknown"
 )
 
@@ -16,5 +25,7 @@ type Source struct {
 	Type       SourceType
 	Name       string
 	Confidence float64
-	Details    map[string]string
+	// Evidence lists the observations the detector based its guess on
+	Evidence []string `json:",omitempty"`
+	Details  map[string]string
 }
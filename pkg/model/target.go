@@ -6,6 +6,8 @@ const (
 	TargetName TargetType = "name"
 	TargetPID  TargetType = "pid"
 	TargetPort TargetType = "port"
+	// TargetSocket is a Unix domain socket path (or "@name" for abstract sockets)
+	TargetSocket TargetType = "socket"
 )
 
 type Target struct {
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// Triage is the raw evidence collected for a process by --investigate, to
+// help classify processes that no detector recognized
+type Triage struct {
+	StartedAt time.Time
+	// TTY is the controlling terminal ("pts/3"), empty when there is none
+	TTY string `json:",omitempty"`
+	// Session describes the login session (logind properties or the
+	// kernel audit session and login uid)
+	Session map[string]string `json:",omitempty"`
+	// Cgroup lists the process's cgroup memberships
+	Cgroup []string `json:",omitempty"`
+	// EnvHints lists environment variables that hint at the launcher
+	EnvHints []string `json:",omitempty"`
+	// FDs lists open file descriptors as "N -> target"
+	FDs []string `json:",omitempty"`
+}
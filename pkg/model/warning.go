@@ -0,0 +1,125 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severity ranks warnings for automated checks
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityLow
+	SeverityMedium
+	SeverityHigh
+)
+
+var severityNames = []string{"info", "low", "medium", "high"}
+
+func (s Severity) String() string {
+	if int(s) < len(severityNames) {
+		return severityNames[s]
+	}
+	return "unknown"
+}
+
+// MarshalText writes the severity by name, e.g. "medium"
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText reads a severity name
+func (s *Severity) UnmarshalText(text []byte) error {
+	v, err := ParseSeverity(string(text))
+	*s = v
+	return err
+}
+
+// ParseSeverity parses a severity name such as "medium"
+func ParseSeverity(name string) (Severity, error) {
+	for i, n := range severityNames {
+		if strings.EqualFold(name, n) {
+			return Severity(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown severity %q (want info, low, medium or high)", name)
+}
+
+// Warning is a warning message with a stable code and severity, for
+// automation that filters or alerts on specific conditions. Codes are
+// never reused or renumbered; messages may be reworded.
+type Warning struct {
+	Code     string
+	Severity Severity
+	Message  string
+}
+
+// Warning codes
+const (
+	WarnOther                    = "W000_OTHER"
+	WarnDeletedBinary            = "W001_DELETED_BINARY"
+	WarnOutdatedBinary           = "W002_OUTDATED_BINARY"
+	WarnDeepAncestry             = "W003_DEEP_ANCESTRY"
+	WarnManyDescendants          = "W004_MANY_DESCENDANTS"
+	WarnSuspiciousWorkingDir     = "W005_SUSPICIOUS_WORKING_DIR"
+	WarnSignatureUnverified      = "W006_SIGNATURE_UNVERIFIED"
+	WarnDangerousCapabilities    = "W007_DANGEROUS_CAPABILITIES"
+	WarnMemoryLimit              = "W008_MEMORY_LIMIT"
+	WarnResourceLimit            = "W009_RESOURCE_LIMIT"
+	WarnCrashLoop                = "W010_CRASH_LOOP"
+	WarnExeMismatch              = "W011_EXE_MISMATCH"
+	WarnPublicListener           = "W012_PUBLIC_LISTENER"
+	WarnCPUThrottled             = "W013_CPU_THROTTLED"
+	WarnOOMKilled                = "W014_OOM_KILLED"
+	WarnUnitFileChanged          = "W015_UNIT_FILE_CHANGED"
+	WarnCommandDrift             = "W016_COMMAND_DRIFT"
+	WarnUnconfinedNetwork        = "W017_UNCONFINED_NETWORK"
+	WarnNoSeccomp                = "W018_NO_SECCOMP"
+	WarnFrequentRestarts         = "W019_FREQUENT_RESTARTS"
+	WarnZombie                   = "W020_ZOMBIE"
+	WarnHighUsage                = "W021_HIGH_USAGE"
+	WarnRealtime                 = "W022_REALTIME_PRIORITY"
+	WarnOOMExempt                = "W023_OOM_EXEMPT"
+	WarnRootInUserNamespace      = "W024_ROOT_IN_USER_NAMESPACE"
+	WarnRoot                     = "W025_ROOT"
+	WarnUnpackagedBinary         = "W026_UNPACKAGED_BINARY"
+	WarnEnvironmentDrift         = "W027_ENVIRONMENT_DRIFT"
+	WarnContainerCommandOverride = "W028_CONTAINER_COMMAND_OVERRIDE"
+	WarnStopped                  = "W029_STOPPED"
+	WarnNoSupervisor             = "W030_NO_SUPERVISOR"
+	WarnLongRunning              = "W031_LONG_RUNNING"
+	WarnNoHealthcheck            = "W032_NO_HEALTHCHECK"
+	WarnServiceNameMismatch      = "W033_SERVICE_NAME_MISMATCH"
+)
+
+// AddWarnings records warnings in Findings and their messages in Warnings,
+// which the human-readable renderers print
+func (r *Result) AddWarnings(ws ...Warning) {
+	for _, w := range ws {
+		r.Findings = append(r.Findings, w)
+		r.Warnings = append(r.Warnings, w.Message)
+	}
+}
+
+// WarningFindings returns a Warning for each of r.Warnings: the one its
+// emitter recorded in Findings or, for messages added without a code (by
+// WithWarning or a wrapper tool), WarnOther with low severity
+func (r Result) WarningFindings() []Warning {
+	if len(r.Warnings) == 0 {
+		return nil
+	}
+	recorded := make(map[string][]Warning)
+	for _, f := range r.Findings {
+		recorded[f.Message] = append(recorded[f.Message], f)
+	}
+	out := make([]Warning, len(r.Warnings))
+	for i, m := range r.Warnings {
+		if fs := recorded[m]; len(fs) > 0 {
+			out[i], recorded[m] = fs[0], fs[1:]
+		} else {
+			out[i] = Warning{Code: WarnOther, Severity: SeverityLow, Message: m}
+		}
+	}
+	return out
+}
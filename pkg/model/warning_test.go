@@ -0,0 +1,41 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestWarningFindings(t *testing.T) {
+	var r Result
+	r.AddWarnings(Warning{Code: WarnRoot, Severity: SeverityLow, Message: "Process is running as root"})
+	r.AddWarnings(Warning{Code: WarnZombie, Severity: SeverityMedium, Message: "Process is a zombie (defunct)"})
+	r = r.WithWarning("Added by a wrapper")
+	got := r.WarningFindings()
+	want := []Warning{
+		{Code: WarnRoot, Severity: SeverityLow, Message: "Process is running as root"},
+		{Code: WarnZombie, Severity: SeverityMedium, Message: "Process is a zombie (defunct)"},
+		{Code: WarnOther, Severity: SeverityLow, Message: "Added by a wrapper"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("WarningFindings() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("finding %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if r.Warnings[0] != "Process is running as root" {
+		t.Errorf("Warnings = %q, want the messages", r.Warnings)
+	}
+}
+
+func TestWarningJSON(t *testing.T) {
+	data, _ := json.Marshal(Warning{Code: WarnZombie, Severity: SeverityMedium, Message: "Process is a zombie (defunct)"})
+	if want := `{"Code":"W020_ZOMBIE","Severity":"medium","Message":"Process is a zombie (defunct)"}`; string(data) != want {
+		t.Errorf("json = %s, want %s", data, want)
+	}
+	var w Warning
+	if err := json.Unmarshal(data, &w); err != nil || w.Severity != SeverityMedium {
+		t.Errorf("round trip = %+v, %v", w, err)
+	}
+}
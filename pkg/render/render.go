@@ -0,0 +1,73 @@
+// Package render exposes witr's output renderers so that tools embedding
+// witr can re-render a (possibly modified) model.Result in the same formats
+// the CLI produces.
+package render
+
+import (
+	"io"
+
+	"github.com/pranshuparmar/witr/internal/output"
+	"github.com/pranshuparmar/witr/pkg/model"
+)
+
+// Renderer writes a stream of results: Begin once, Emit per result, End once
+type Renderer = output.Renderer
+
+// Output formats accepted by New
+const (
+	FormatStandard = output.FormatStandard
+	FormatVerbose  = output.FormatVerbose
+	FormatShort    = output.FormatShort
+	FormatTree     = output.FormatTree
+	FormatWarnings = output.FormatWarnings
+	FormatJSON     = output.FormatJSON
+	FormatNDJSON   = output.FormatNDJSON
+	FormatDOT      = output.FormatDOT
+	FormatMarkdown = output.FormatMarkdown
+	FormatHTML     = output.FormatHTML
+	FormatCSV      = output.FormatCSV
+	FormatTSV      = output.FormatTSV
+)
+
+// New returns the renderer for a format, writing to w
+func New(format string, w io.Writer, color bool) (Renderer, error) {
+	return output.NewRenderer(format, w, color)
+}
+
+// Template returns the format that renders each result with a Go template
+// over model.Result, as witr --format does
+func Template(text string) string {
+	return output.TemplateFormat(text)
+}
+
+// Standard prints the full human-readable report
+func Standard(w io.Writer, r model.Result, color bool) {
+	output.RenderStandard(w, r, color)
+}
+
+// Verbose prints the full report with every ancestor's command line and
+// an environment summary, as witr --verbose does
+func Verbose(w io.Writer, r model.Result, color bool) {
+	output.RenderVerbose(w, r, color)
+}
+
+// Short prints the one-line ancestry summary
+func Short(w io.Writer, r model.Result, color bool) {
+	output.RenderShort(w, r, color)
+}
+
+// Tree prints the ancestry as a tree, with the subtree below the target
+// when the result has one
+func Tree(w io.Writer, r model.Result, color bool) {
+	output.RenderTree(w, r, color)
+}
+
+// Warnings prints only the warnings
+func Warnings(w io.Writer, r model.Result, color bool) {
+	output.RenderWarnings(w, r.Warnings, color)
+}
+
+// JSON returns the result encoded as indented JSON
+func JSON(r model.Result) (string, error) {
+	return output.ToJSON(r)
+}
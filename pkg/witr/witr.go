@@ -0,0 +1,42 @@
+//go:build linux || darwin
+
+// Package witr lets organizations build their own witr binary with extra
+// source detectors (for example an in-house supervisor) without forking:
+//
+//	func main() {
+//		witr.RegisterDetector(witr.DetectorFunc{ID: "acme-sup", Fn: detectAcme}, witr.PrioritySupervisor-1)
+//		witr.Main()
+//	}
+package witr
+
+import (
+	"github.com/pranshuparmar/witr/internal/cli"
+	"github.com/pranshuparmar/witr/internal/source"
+)
+
+// Detector recognizes one kind of source from a process ancestry
+type Detector = source.Detector
+
+// DetectorFunc adapts a function to the Detector interface
+type DetectorFunc = source.DetectorFunc
+
+// Priorities of the built-in detector groups; lower runs first
+const (
+	PriorityRuntime        = source.PriorityRuntime
+	PrioritySession        = source.PrioritySession
+	PriorityDesktop        = source.PriorityDesktop
+	PriorityUnit           = source.PriorityUnit
+	PrioritySupervisor     = source.PrioritySupervisor
+	PriorityServiceManager = source.PriorityServiceManager
+	PriorityShell          = source.PriorityShell
+)
+
+// RegisterDetector adds a detector that is tried at the given priority
+func RegisterDetector(d Detector, priority int) {
+	source.Register(d, priority)
+}
+
+// Main runs the witr command line, including any registered detectors
+func Main() {
+	cli.Execute("custom", "unknown", "unknown")
+}